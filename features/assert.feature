@@ -0,0 +1,39 @@
+Feature: Change Assertions
+  As a user maintaining a curated mirror
+  I want CI to fail when a sync would make unexpected kinds of changes
+  So that filter regressions are caught before they reach the mirror
+
+  Scenario: An in-sync destination passes --expect-no-changes
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    And I run sync-tools assert with "--expect-no-changes"
+    Then the exit code should be 0
+    And the output should contain "OK: no created, updated, or deleted files"
+
+  Scenario: A pending creation fails --expect-no-changes and names the file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    And the source has a file "added.txt"
+    And I run sync-tools assert with "--expect-no-changes"
+    Then the exit code should be 1
+    And the output should contain "created  added.txt"
+    And the output should contain "expected no created, updated, or deleted files, found 1"
+    And the destination should not contain "added.txt"
+
+  Scenario: Only the asserted kinds of change fail
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    And the source has a file "added.txt"
+    And I run sync-tools assert with "--expect-no deletions"
+    Then the exit code should be 0
+    And the output should contain "OK: no deleted files"
+
+  Scenario: Unknown change kinds are rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools assert with "--expect-no removals"
+    Then the exit code should be 1
+    And the output should contain "invalid --expect-no: removals"
@@ -0,0 +1,49 @@
+Feature: Checksum Algorithm Choice
+  As a user running large local syncs
+  I want to pick rsync's checksum algorithm
+  So that I can use a faster hash like xxhash
+
+  Scenario: The checksum choice is passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--checksum-choice xxh128"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--checksum-choice=xxh128"
+
+  Scenario: An unknown algorithm is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--checksum-choice sha256"
+    Then the exit code should be 1
+    And the output should contain "invalid --checksum-choice"
+
+  Scenario: An rsync too old for the option is warned about
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary reporting version "3.1.3"
+    When I run sync-tools with that rsync binary and flags "--checksum-choice xxh128"
+    Then the output should contain "--checksum-choice=xxh128 needs rsync 3.2 or newer"
+
+  Scenario: The checksum choice can come from the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      checksum_choice = "md5"
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been called with argument "--checksum-choice=md5"
+
+  Scenario: A SyncFile sets the checksum choice with CHECKSUM
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And the SyncFile "Checksum.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      RSYNCBIN {tmp}/recording-rsync
+      CHECKSUM xxh3
+      """
+    When I run sync-tools syncfile "Checksum.SyncFile"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--checksum-choice=xxh3"
@@ -0,0 +1,46 @@
+Feature: Comparing Two Directories
+  As a user auditing two mirrors
+  I want a report of how two directories differ
+  So that I can check them without implying which one should win
+
+  Background:
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    And the destination has an exact copy of the source file "subdir/file3.txt"
+
+  Scenario: The text report lists each kind of difference
+    When I run sync-tools compare on the source and destination
+    Then the exit code should be 0
+    And the output should contain "Only in A (0):"
+    And the output should contain "Only in B (1):"
+    And the output should contain "dest_only.txt"
+    And the output should contain "Differing (2):"
+    And the output should contain "file2.txt"
+    And the output should contain "Identical: 1 files"
+    And the output should not contain "created"
+
+  Scenario: A directory on one side is reported once
+    Given the source has a file "docs/guide/intro.md"
+    When I run sync-tools compare on the source and destination
+    Then the exit code should be 0
+    And the output should contain "Only in A (1):"
+    And the output should contain "docs/"
+    And the output should not contain "intro.md"
+
+  Scenario: The filters apply to both sides
+    When I run sync-tools compare on the source and destination with "--ignore-src file2.txt --ignore-src dest_only.txt"
+    Then the exit code should be 0
+    And the output should contain "Only in B (0):"
+    And the output should contain "Differing (1):"
+
+  Scenario: The report can be Markdown
+    When I run sync-tools compare on the source and destination with "--format md"
+    Then the exit code should be 0
+    And the output should contain "# Directory Comparison"
+    And the output should contain "| 0 | 1 | 2 | 1 |"
+
+  Scenario: The report can be JSON
+    When I run sync-tools compare on the source and destination with "--format json"
+    Then the exit code should be 0
+    And the output should contain "only_in_b"
+    And the output should contain "mod_time"
@@ -0,0 +1,27 @@
+Feature: Patch and Preview Settings in Config Files
+  As a user who always reviews changes as a patch
+  I want to set patch and preview options in sync.toml
+  So that I don't have to pass them on every run
+
+  Scenario: A configured patch file is generated instead of syncing
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a config file containing:
+      """
+      patch = "{tmp}/changes.patch"
+      """
+    When I run sync-tools with one-way sync using the config
+    Then the exit code should be 0
+    And the file "changes.patch" should exist in the temp directory
+    And the destination should not contain "file1.txt"
+
+  Scenario: apply_patch without a patch file is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a config file containing:
+      """
+      apply_patch = true
+      """
+    When I run sync-tools with one-way sync using the config
+    Then the exit code should be 1
+    And the output should contain "apply_patch requires patch"
@@ -0,0 +1,34 @@
+Feature: Conflict Copy Names
+  As a user browsing conflict backups by hand
+  I want to choose how conflict copies are named
+  So that they carry readable dates instead of unix timestamps
+
+  Scenario: Undo restores backups named with a custom suffix
+    Given I have a destination directory with some matching and some different files
+    And the destination has a conflict backup named "file1.2023-11-14_2213.txt.bak" containing "before the conflict"
+    When I run sync-tools undo on the destination with flags "--conflict-suffix {name}.{time:2006-01-02_1504}{ext}.bak"
+    Then the exit code should be 0
+    And the destination file "file1.txt" should contain "before the conflict"
+    And the destination should not contain "file1.2023-11-14_2213.txt.bak"
+
+  Scenario: Undo only looks for the names the suffix produces
+    Given I have a destination directory with some matching and some different files
+    And the destination has a conflict backup named "file1.2023-11-14_2213.txt.bak" containing "before the conflict"
+    When I run sync-tools undo on the destination
+    Then the exit code should be 0
+    And the output should contain "No conflict backups to restore"
+
+  Scenario: Custom-named conflict copies are reported as stale artifacts
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has a conflict backup named "notes.2023-11-14_2213.md.bak" containing "old notes"
+    When I run sync-tools with one-way sync and flags "--dry-run --conflict-suffix {name}.{time:2006-01-02_1504}{ext}.bak"
+    Then the exit code should be 0
+    And the output should contain "notes.2023-11-14_2213.md.bak (conflict copy)"
+
+  Scenario: A suffix that undo couldn't reverse is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--conflict-suffix {name}.bak"
+    Then the exit code should be 1
+    And the output should contain "must include {name}, {ext}, and {time:format}"
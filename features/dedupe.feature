@@ -0,0 +1,30 @@
+Feature: Deduplicating Copied Files
+  As a user consolidating duplicate-heavy folders into a backup
+  I want files whose content is already in the dest to be hard-linked instead of stored again
+  So that the backup only holds each piece of content once
+
+  Scenario: A new file identical to one in the dest is hard-linked to it
+    Given I have a source directory with files
+    And the source has a file "albums/photo.jpg"
+    And the destination has an exact copy of the source file "albums/photo.jpg"
+    And the source has an exact copy of "albums/photo.jpg" at "inbox/photo.jpg"
+    When I run sync-tools with one-way sync and flags "--dedupe"
+    Then the exit code should be 0
+    And the output should contain "Deduplicated 1 files by hard link"
+    And the destination files "albums/photo.jpg" and "inbox/photo.jpg" should be hard links to the same file
+
+  Scenario: Without --dedupe duplicates are stored separately
+    Given I have a source directory with files
+    And the source has a file "albums/photo.jpg"
+    And the destination has an exact copy of the source file "albums/photo.jpg"
+    And the source has an exact copy of "albums/photo.jpg" at "inbox/photo.jpg"
+    When I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And the destination files "albums/photo.jpg" and "inbox/photo.jpg" should not be hard links to the same file
+
+  Scenario: --dedupe is refused with --inplace
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--dedupe --inplace"
+    Then the exit code should be 1
+    And the output should contain "--dedupe cannot be combined with --inplace"
@@ -0,0 +1,33 @@
+Feature: Destination Unignore Patterns
+  As a user mirroring into a directory with files of its own
+  I want ! patterns in the dest ignores to protect dest-only files
+  So that the delete pass leaves them alone
+
+  Scenario: Dest ignores exclude paths from the sync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--ignore-dest *.log"
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- *.log"
+    And rsync should not have been given the filter rule "P keep-this.log"
+
+  Scenario: A dest unignore protects the file from deletion
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--ignore-dest *.log --ignore-dest !keep-this.log"
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "P keep-this.log"
+    And rsync should have been given the filter rule "P keep-this.log/**"
+    And rsync should have been given the filter rule "- *.log"
+    And rsync should not have been given the filter rule "+ keep-this.log"
+
+  Scenario: Dest unignores can be set in the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      ignore_dest = ["*.log", "!/keep-this.log"]
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "P /keep-this.log"
@@ -0,0 +1,23 @@
+Feature: Environment Doctor
+  As a user setting up sync-tools
+  I want to check that the tools it relies on are installed
+  So that I find missing dependencies before a sync fails
+
+  Scenario: Doctor passes when rsync is installed
+    When I run sync-tools doctor
+    Then the exit code should be 0
+    And the output should contain "[PASS] rsync"
+    And the output should contain "[PASS] tmpdir"
+
+  Scenario: Doctor fails when rsync is missing
+    When I run sync-tools doctor with rsync binary "/nonexistent/rsync"
+    Then the exit code should be 1
+    And the output should contain "[FAIL] rsync"
+    And the output should contain "doctor found 1 failing checks"
+
+  Scenario: Doctor warns about an rsync without progress2 support
+    Given I have an rsync binary reporting version "2.6.9"
+    When I run sync-tools doctor with that rsync binary
+    Then the exit code should be 0
+    And the output should contain "[WARN] rsync"
+    And the output should contain "is version 2.6.9; 3.1 or newer is needed for --info=progress2"
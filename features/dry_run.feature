@@ -0,0 +1,28 @@
+Feature: Dry Run Output
+  As a user scripting around sync-tools
+  I want dry runs to always say so and list what they would change
+  So that scripts can detect and parse a dry run at any log level
+
+  Scenario: The banner and itemized changes survive a quiet log level
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--dry-run --log-level ERROR"
+    Then the exit code should be 0
+    And it should show what files would be copied
+    And the output should contain ">f+++++++++ 33 subdir/file3.txt"
+    And no files should actually be copied
+
+  Scenario: Real syncs print no banner
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And the output should not contain "DRY RUN: "
+
+  Scenario: Summary-only dry runs print just the summary
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--summary-only --dry-run"
+    Then the exit code should be 0
+    And the output should not contain "DRY RUN: "
+    And the output should contain "Dry run summary: 3 created"
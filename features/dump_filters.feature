@@ -0,0 +1,32 @@
+Feature: Dump Filters
+  As a user debugging why a file is or isn't synced
+  I want to see the exact filter rules sync-tools gives rsync
+  So that I can tell which setting or ignore file each rule came from
+
+  Scenario: Filter rules are printed with their origins
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the source has a file ".syncignore" containing:
+      """
+      *.log
+      """
+    When I run sync-tools with one-way sync and flags "--dump-filters --ignore-src *.tmp --ignore-dest *.bak --dry-run"
+    Then the exit code should be 0
+    And the output should contain "# from default (--include-git drops it)"
+    And the output should contain "- /.git/"
+    And the output should contain "# from .syncignore"
+    And the output should contain "- *.log"
+    And the output should contain "# from --ignore-src"
+    And the output should contain "- *.tmp"
+    And the output should contain "# from --ignore-dest"
+    And the output should contain "- *.bak"
+
+  Scenario: Filter rules are written to a file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync from the temp directory and flags "--dump-filters=filters.txt --only docs/"
+    Then the exit code should be 0
+    And the file "filters.txt" in the temp directory should contain "# from --only"
+    And the file "filters.txt" in the temp directory should contain "+ /docs/**"
+    And the output should not contain "# from --only"
+
@@ -0,0 +1,33 @@
+Feature: Sync Estimates
+  As a user about to run a large sync
+  I want to know how many files and bytes it will transfer
+  So that I can decide whether to run it now
+
+  Scenario: Estimating a sync into an empty destination
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--estimate"
+    Then the exit code should be 0
+    And the output should contain "Estimate: will transfer ~3 files, 85 B (3 created, 0 updated); 0 deleted"
+    And the destination should not contain "file1.txt"
+
+  Scenario: Files only in the destination are counted as deletions
+    Given I have a source directory with files
+    And I have a destination directory with different files
+    When I run sync-tools with one-way sync and flags "--estimate"
+    Then the exit code should be 0
+    And the output should contain "(3 created, 0 updated); 2 deleted"
+
+  Scenario: Filtered files are left out of the estimate
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--estimate --ignore-src subdir"
+    Then the exit code should be 0
+    And the output should contain "will transfer ~2 files, 52 B"
+
+  Scenario: Two-way syncs can't be estimated
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--estimate --mode two-way"
+    Then the exit code should be 1
+    And the output should contain "--estimate only supports one-way syncs"
@@ -0,0 +1,43 @@
+Feature: Excluding Marked Directories
+  As a user backing up a broad tree
+  I want to opt individual folders out by dropping a marker file in them
+  So that I don't have to maintain ignore patterns for each one
+
+  Scenario: A directory holding the marker is not synced
+    Given I have a source directory with files
+    And the source has a file "scratch/.nobackup"
+    And the source has a file "scratch/big.iso"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--exclude-if-present .nobackup"
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- /scratch/"
+    And rsync should not have been given the filter rule "- /subdir/"
+
+  Scenario: Several marker names can be given
+    Given I have a source directory with files
+    And the source has a file "cache/.nobackup"
+    And the source has a file "build/CACHEDIR.TAG"
+    When I run sync-tools list on the source in "flat" format with "--exclude-if-present .nobackup,CACHEDIR.TAG"
+    Then the exit code should be 0
+    And the output should contain "subdir/file3.txt"
+    And the output should not contain "cache/"
+    And the output should not contain "build/"
+
+  Scenario: A marker in the source root is refused
+    Given I have a source directory with files
+    And the source has a file ".nobackup"
+    When I run sync-tools with one-way sync and flags "--exclude-if-present .nobackup"
+    Then the exit code should be 1
+    And the output should contain "would exclude everything"
+
+  Scenario: Markers can come from the config file
+    Given I have a source directory with files
+    And the source has a file "scratch/.nobackup"
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      exclude_if_present = [".nobackup"]
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- /scratch/"
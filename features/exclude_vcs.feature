@@ -0,0 +1,39 @@
+Feature: VCS and Junk File Exclusion
+  As a user syncing working copies
+  I want to leave out version control metadata and editor clutter
+  So that only the project files reach the destination
+
+  Scenario: --exclude-vcs drops VCS metadata and junk files
+    Given I have a source directory with files
+    And the source has a file ".svn/entries"
+    And the source has a file "lib/.hg/store"
+    And the source has a file "notes.txt~"
+    And the source has a file ".DS_Store"
+    When I run sync-tools list on the source in "flat" format with "--exclude-vcs"
+    Then the exit code should be 0
+    And the output should contain "subdir/file3.txt"
+    And the output should not contain ".svn"
+    And the output should not contain ".hg"
+    And the output should not contain "notes.txt~"
+    And the output should not contain ".DS_Store"
+
+  Scenario: The .git directory is excluded by default
+    Given I have a source directory with files
+    And the source has a file ".git/HEAD"
+    When I run sync-tools list on the source in "flat" format
+    Then the exit code should be 0
+    And the output should not contain ".git/HEAD"
+
+  Scenario: --include-git syncs the .git directory
+    Given I have a source directory with files
+    And the source has a file ".git/HEAD"
+    When I run sync-tools list on the source in "flat" format with "--include-git"
+    Then the exit code should be 0
+    And the output should contain ".git/HEAD"
+
+  Scenario: --include-git conflicts with --exclude-vcs
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--exclude-vcs --include-git"
+    Then the exit code should be 1
+    And the output should contain "--include-git conflicts with --exclude-vcs"
@@ -0,0 +1,54 @@
+Feature: Refusing to Sync Over a Changed Dest
+  As a user mirroring to a production server
+  I want a sync to stop if someone changed the mirror since the last sync
+  So that hotfixes made directly on the mirror aren't silently overwritten
+
+  Scenario: The first sync records the dest for the next one
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    Then the exit code should be 0
+    And the output should contain "No manifest of"
+    And the destination should contain "file1.txt"
+
+  Scenario: An unchanged dest syncs as usual
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    And the source has a file "added.txt"
+    And I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    Then the exit code should be 0
+    And the output should contain "is unchanged since the last sync"
+    And the destination should contain "added.txt"
+
+  Scenario: A file edited on the dest stops the sync
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    And the destination has a file "file1.txt"
+    And the destination has a file "hotfix.txt"
+    And I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    Then the exit code should be 1
+    And the output should contain "Changed on the dest since the last sync: file1.txt (modified)"
+    And the output should contain "Changed on the dest since the last sync: hotfix.txt (added)"
+    And the output should contain "re-run without --expect-dest-clean"
+    And the destination file "file1.txt" should contain "content for file1.txt"
+
+  Scenario: Syncing without the flag overwrites the changes
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    And the destination has a file "hotfix.txt"
+    And I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And the destination should not contain "hotfix.txt"
+    When I run sync-tools with one-way sync and flags "--expect-dest-clean"
+    Then the exit code should be 0
+    And the output should contain "is unchanged since the last sync"
+
+  Scenario: --expect-dest-clean is refused for two-way syncs
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--mode two-way --expect-dest-clean"
+    Then the exit code should be 1
+    And the output should contain "--expect-dest-clean only applies to one-way syncs"
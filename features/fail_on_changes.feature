@@ -0,0 +1,28 @@
+Feature: Drift Check Exit Status
+  As a CI pipeline
+  I want a dry run to fail when the destination has drifted
+  So that a build can catch deployments that don't match the repo
+
+  Scenario: Pending changes fail the dry run
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with a dry run that fails on changes
+    Then the exit code should be 1
+    And the output should contain "4 pending changes (files: 3 created, 0 updated, 0 deleted; directories: 1 created, 0 deleted)"
+    And no files should actually be copied
+
+  Scenario: A destination in sync passes the dry run
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    And I run sync-tools with a dry run that fails on changes
+    Then the exit code should be 0
+    And the output should contain "No pending changes"
+
+  Scenario: --fail-on-changes requires a dry run
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with a real sync that fails on changes
+    Then the exit code should be 1
+    And the output should contain "--fail-on-changes requires --dry-run"
+    And the destination should not contain "file1.txt"
@@ -0,0 +1,48 @@
+Feature: Super and Fake-Super Backups
+  As a user backing up system files without root
+  I want ownership kept in extended attributes on the destination
+  So that restoring the backup as root is faithful
+
+  Scenario: --fake-super is passed to rsync for a local destination
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--fake-super"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--fake-super"
+
+  Scenario: --super is passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--super"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--super"
+
+  Scenario: The two can't be combined
+    Given I have a source directory with files
+    When I run sync-tools with one-way sync and flags "--super --fake-super"
+    Then the exit code should be 1
+    And the output should contain "--super and --fake-super cannot be used together"
+
+  Scenario: Fake-super can be enabled in the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      fake_super = true
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been called with argument "--fake-super"
+
+  Scenario: A remote destination gets fake-super as a remote option
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And the SyncFile "Nas.SyncFile" contains:
+      """
+      SYNC {source} nas:/backups/etc
+      RSYNCBIN {tmp}/recording-rsync
+      FAKESUPER true
+      """
+    When I run sync-tools syncfile "Nas.SyncFile"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--remote-option=--fake-super"
@@ -0,0 +1,53 @@
+Feature: Raw rsync Filter Rules
+  As a user who already knows rsync's filter syntax
+  I want to pass rules straight through to rsync
+  So that I can use rules sync-tools has no flag for
+
+  Scenario: An include and exclude pair keeps one log file
+    Given I have a source directory with files
+    And the source has a file "keep.log"
+    And the source has a file "debug.log"
+    When I run sync-tools list on the source with the filter rules:
+      """
+      + keep.log
+      - *.log
+      """
+    Then the exit code should be 0
+    And the output should contain "keep.log"
+    And the output should not contain "debug.log"
+    And the output should contain "file1.txt"
+
+  Scenario: Rules are passed to rsync after the generated ones
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      filter_rules = ["+ keep.log", "- *.log"]
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "+ keep.log"
+    And rsync should have been given the filter rule "- *.log"
+
+  Scenario: Rules can come from a SyncFile
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And the SyncFile "Filter.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      RSYNCBIN {tmp}/recording-rsync
+      FILTER - *.bak
+      """
+    When I run sync-tools syncfile "Filter.SyncFile"
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- *.bak"
+
+  Scenario: A rule spanning lines is refused
+    Given I have a source directory with files
+    And I have a config file containing:
+      """
+      filter_rules = ["- a\n+ b"]
+      """
+    When I run sync-tools with one-way sync using the config
+    Then the exit code should be 1
+    And the output should contain "single non-empty rsync filter rule"
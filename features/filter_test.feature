@@ -0,0 +1,74 @@
+Feature: Filter Testing
+  As a user
+  I want to ask whether a specific path would be synced
+  So that I can debug my filters without running a sync
+
+  Scenario: A path matching an ignore pattern reports the deciding rule
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    When I run sync-tools filter test for "notes.tmp"
+    Then the output should contain "notes.tmp: excluded by *.tmp"
+    And the exit code should be 0
+
+  Scenario: A path matching no pattern is included
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    When I run sync-tools filter test for "file1.txt"
+    Then the output should contain "file1.txt: included (no rule matched)"
+    And the exit code should be 0
+
+  Scenario: Paths below the max depth are excluded
+    Given I have a source directory with files
+    When I run sync-tools filter test for "subdir/deeper/file.txt" with max depth 2
+    Then the output should contain "subdir/deeper/file.txt: excluded by /*/*/*"
+    And the exit code should be 0
+
+  Scenario: Paths within the max depth are included
+    Given I have a source directory with files
+    When I run sync-tools filter test for "subdir/file3.txt" with max depth 2
+    Then the output should contain "subdir/file3.txt: included (no rule matched)"
+    And the exit code should be 0
+
+  Scenario: Ignore patterns can match case-insensitively
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    When I run sync-tools filter test for "NOTES.TMP" ignoring case
+    Then the output should contain "NOTES.TMP: excluded by *.[tT][mM][pP]"
+    And the exit code should be 0
+
+  Scenario: check-filter reports on several paths at once
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    When I run sync-tools check-filter for "notes.tmp file1.txt"
+    Then the output should contain "notes.tmp: excluded by *.tmp"
+    And the output should contain "file1.txt: included (no rule matched)"
+    And the exit code should be 0
+
+  Scenario: check-filter tries out a hypothetical whitelist
+    Given I have a source directory with files
+    When I run sync-tools check-filter for "subdir/file3.txt file1.txt" with "--only subdir/"
+    Then the output should contain "subdir/file3.txt: included by /subdir/**"
+    And the output should contain "file1.txt: excluded by *"
+    And the exit code should be 0
+
+  Scenario: check-filter explains exclusions inherited from a parent directory
+    Given I have a source directory with files
+    When I run sync-tools check-filter for "build/out/app.o" with "--ignore-src build/"
+    Then the output should contain "build/out/app.o: excluded by build/ (parent directory build)"
+    And the exit code should be 0
+
+  Scenario: check-filter follows globs in a whitelist
+    Given I have a source directory with files
+    When I run sync-tools check-filter for "subdir/file3.txt subdir/notes.md file1.txt" with "--only subdir/**/*.txt"
+    Then the output should contain "subdir/file3.txt: included by /subdir/*.txt"
+    And the output should contain "subdir/notes.md: excluded by *"
+    And the output should contain "file1.txt: excluded by *"
+    And the exit code should be 0
+
+  Scenario: check-filter expands braces in a whitelist
+    Given I have a source directory with files
+    When I run sync-tools check-filter for "a/one.txt b/two.txt c/three.txt" with "--only {a,b}/*.txt"
+    Then the output should contain "a/one.txt: included by /a/*.txt"
+    And the output should contain "b/two.txt: included by /b/*.txt"
+    And the output should contain "c/three.txt: excluded by *"
+    And the exit code should be 0
@@ -53,4 +53,15 @@ Feature: Git Patch Generation
     When I run sync-tools with patch generation to "preview.patch" and dry-run
     Then it should show what would be included in the patch
     And no patch file should be created
-    And the exit code should be 0
\ No newline at end of file
+    And the exit code should be 0
+
+  Scenario: Generate and apply a unified diff without git
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    And git is not available
+    When I run sync-tools with patch generation to "builtin.patch" and apply it
+    Then the exit code should be 0
+    And the output should contain "git not found; generating patch with the built-in diff"
+    And the destination file "file2.txt" should contain "test content for file2.txt"
+    And the destination should contain "subdir/file3.txt"
+    And the destination should not contain "dest_only.txt"
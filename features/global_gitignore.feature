@@ -0,0 +1,49 @@
+Feature: Global Gitignore
+  As a user with personal ignores in git's global gitignore
+  I want sync-tools to honor them
+  So that editor and OS files stay out of every sync without per-project config
+
+  Scenario: Global gitignore patterns exclude matching files
+    Given I have a source directory with files
+    And I have a global gitignore containing:
+      """
+      # editor files
+      *.swp
+      .idea/
+      """
+    When I run sync-tools check-filter for "notes.txt.swp .idea/workspace.xml file1.txt" with "--use-global-gitignore"
+    Then the exit code should be 0
+    And the output should contain "notes.txt.swp: excluded by *.swp"
+    And the output should contain ".idea/workspace.xml: excluded by .idea/"
+    And the output should contain "file1.txt: included"
+
+  Scenario: The global gitignore is only used when asked for
+    Given I have a source directory with files
+    And I have a global gitignore containing:
+      """
+      *.swp
+      """
+    When I run sync-tools check-filter for "notes.txt.swp" with ""
+    Then the exit code should be 0
+    And the output should contain "notes.txt.swp: included"
+
+  Scenario: A missing global gitignore is not an error
+    Given I have a source directory with files
+    When I run sync-tools check-filter for "notes.txt.swp" with "--use-global-gitignore"
+    Then the exit code should be 0
+    And the output should contain "notes.txt.swp: included"
+
+  Scenario: The global gitignore can be enabled in the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a global gitignore containing:
+      """
+      *.swp
+      """
+    And I have a config file containing:
+      """
+      use_global_gitignore = true
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- *.swp"
@@ -0,0 +1,26 @@
+Feature: Post-Sync Hooks
+  As a user automating backups
+  I want to run a command when a sync finishes
+  So that I can send notifications or trigger downstream jobs
+
+  Scenario: The on-success hook sees the sync summary
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and on-success hook "echo hook: exit=$SYNC_EXIT created=$SYNC_FILES_CREATED"
+    Then the exit code should be 0
+    And the output should contain "hook: exit=0 created=3"
+    And files should be copied to destination
+
+  Scenario: The on-failure hook runs when the sync fails
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync, rsync binary "/nonexistent/rsync", and on-failure hook "echo hook: exit=$SYNC_EXIT error=$SYNC_ERROR"
+    Then the exit code should be 1
+    And the output should contain "hook: exit=1 error=rsync not found"
+
+  Scenario: A failing hook doesn't change the sync's exit status
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and on-success hook "exit 3"
+    Then the exit code should be 0
+    And the output should contain "--on-success hook failed"
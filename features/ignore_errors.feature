@@ -0,0 +1,36 @@
+Feature: Skipping Unreadable Files
+  As a user backing up a home directory with a few protected files
+  I want the sync to finish and tell me what it couldn't read
+  So that one root-owned file doesn't fail the whole backup
+
+  Scenario: An unreadable file fails the sync by default
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that can't read "secret.txt"
+    When I run sync-tools with the unreadable-file rsync and flags ""
+    Then the exit code should be 1
+    And the output should contain "rsync command failed"
+
+  Scenario: --ignore-errors finishes the sync and lists the skipped file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that can't read "secret.txt"
+    When I run sync-tools with the unreadable-file rsync and flags "--ignore-errors"
+    Then the exit code should be 0
+    And the output should contain "Skipped unreadable file: secret.txt"
+    And the output should contain "Skipped 1 files that couldn't be transferred"
+
+  Scenario: Skipped files are counted in the summary line
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that can't read "secret.txt"
+    When I run sync-tools with the unreadable-file rsync and flags "--ignore-errors --summary-only"
+    Then the exit code should be 0
+    And the output should contain ", 1 skipped,"
+
+  Scenario: Failures other than unreadable files still fail the sync
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--rsync-binary false --ignore-errors"
+    Then the exit code should be 1
+    And the output should contain "rsync command failed: exit status 1"
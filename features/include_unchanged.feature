@@ -0,0 +1,39 @@
+Feature: Listing Unchanged Files
+  As a user reviewing a dry run
+  I want to see the files a sync would leave alone as well as those it would change
+  So that I can confirm every file I expect is covered by the sync
+
+  Scenario: A dry run lists unchanged files when asked
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync and flags "--dry-run --include-unchanged"
+    Then the exit code should be 0
+    And the output should contain ".f          26 file1.txt"
+    And the output should contain ">f+++++++++ 26 file2.txt"
+
+  Scenario: Unchanged files are left out by default
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync and flags "--dry-run"
+    Then the exit code should be 0
+    And the output should not contain "file1.txt"
+
+  Scenario: The summary counts unchanged files
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync and flags "--dry-run --summary-only --include-unchanged"
+    Then the exit code should be 0
+    And the output should contain "Dry run summary: 2 created, 0 updated, 0 deleted, 1 unchanged"
+
+  Scenario: A markdown report lists unchanged files in their own section
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync from the temp directory and flags "--include-unchanged --report report.md"
+    Then the exit code should be 0
+    And the file "report.md" in the temp directory should contain "## Unchanged"
+    And the file "report.md" in the temp directory should contain "| file1.txt | 26 |"
+    And the file "report.md" in the temp directory should contain "| created | file2.txt | 26 |"
@@ -0,0 +1,60 @@
+Feature: Interactive Conflict Resolution
+  As a user running two-way syncs from a terminal
+  I want to decide how each conflicting file is resolved
+  So that I don't have to accept the same outcome for every file
+
+  Scenario: Interactive conflicts only apply to two-way syncs
+    Given I have a source directory with files
+    When I run sync-tools with one-way sync and flags "--interactive-conflicts"
+    Then the exit code should be 1
+    And the output should contain "--interactive-conflicts only applies to --mode two-way"
+
+  Scenario: An out-of-date dest file isn't a conflict
+    Given I have a source directory with files
+    And the source has a file "notes.txt"
+    And the destination has an older copy of the source file "notes.txt"
+    When I run sync-tools with one-way sync and flags "--mode two-way --interactive-conflicts"
+    Then the exit code should be 0
+    And the output should not contain "changed on both sides"
+    And the destination should contain "file1.txt"
+    And the destination should have no conflict copy of "notes.txt"
+
+  Scenario: A file edited in the dest keeps both versions by default
+    Given I have a source directory with files
+    And the source has a file "notes.txt"
+    And the destination has a newer "notes.txt" containing "edited in the dest"
+    When I run sync-tools with one-way sync and flags "--mode two-way"
+    Then the exit code should be 0
+    And the output should contain "Found 1 conflicts"
+    And the destination file "notes.txt" should contain "content for notes.txt"
+    And the destination should have a conflict copy of "notes.txt" containing "edited in the dest"
+
+  Scenario: Answering dest keeps the dest version on both sides
+    Given I have a source directory with files
+    And the source has a file "notes.txt"
+    And the destination has a newer "notes.txt" containing "edited in the dest"
+    When I run sync-tools with one-way sync and flags "--mode two-way --interactive-conflicts" answering "d"
+    Then the exit code should be 0
+    And the output should contain "Conflict: notes.txt changed on both sides"
+    And the source file "notes.txt" should contain "edited in the dest"
+    And the destination file "notes.txt" should contain "edited in the dest"
+    And the destination should have no conflict copy of "notes.txt"
+
+  Scenario: Answering source lets the source version win without a copy
+    Given I have a source directory with files
+    And the source has a file "notes.txt"
+    And the destination has a newer "notes.txt" containing "edited in the dest"
+    When I run sync-tools with one-way sync and flags "--mode two-way --interactive-conflicts" answering "s"
+    Then the exit code should be 0
+    And the output should contain "Conflict: notes.txt changed on both sides"
+    And the destination file "notes.txt" should contain "content for notes.txt"
+    And the destination should have no conflict copy of "notes.txt"
+
+  Scenario: --yes keeps both versions without asking
+    Given I have a source directory with files
+    And the source has a file "notes.txt"
+    And the destination has a newer "notes.txt" containing "edited in the dest"
+    When I run sync-tools with one-way sync and flags "--mode two-way --interactive-conflicts --yes"
+    Then the exit code should be 0
+    And the output should not contain "changed on both sides"
+    And the destination should have a conflict copy of "notes.txt" containing "edited in the dest"
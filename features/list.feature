@@ -0,0 +1,26 @@
+Feature: Filtered Source Listing
+  As a user tuning my filters
+  I want to see which source files survive them
+  So that I can adjust .syncignore and --only without a dry run
+
+  Scenario: The tree listing shows included files only
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    And the source has a file "notes.tmp"
+    When I run sync-tools list on the source
+    Then the exit code should be 0
+    And the output should contain "└── file3.txt"
+    And the output should contain "file1.txt"
+    And the output should not contain "notes.tmp"
+
+  Scenario: The flat listing prints one path per line
+    Given I have a source directory with files
+    When I run sync-tools list on the source in "flat" format
+    Then the exit code should be 0
+    And the output should contain "subdir/file3.txt"
+
+  Scenario: The JSON listing includes sizes
+    Given I have a source directory with files
+    When I run sync-tools list on the source in "json" format
+    Then the exit code should be 0
+    And the JSON listing should include "subdir/file3.txt" of 33 bytes
@@ -0,0 +1,43 @@
+Feature: Multiple Sources
+  As a user backing up several directories
+  I want to pass --source more than once
+  So that one invocation syncs them all into the same destination
+
+  Scenario: Each source lands in its own directory under the destination
+    Given the temp directory has a file "projects/app/main.go"
+    And the temp directory has a file "projects/lib/lib.go"
+    When I run sync-tools sync with flags "--source projects/app --source projects/lib --dest archive"
+    Then the exit code should be 0
+    And the file "archive/app/main.go" should exist in the temp directory
+    And the file "archive/lib/lib.go" should exist in the temp directory
+
+  Scenario: Relative syncs keep each source's full path
+    Given the temp directory has a file "projects/app/main.go"
+    And the temp directory has a file "projects/lib/lib.go"
+    When I run sync-tools sync with flags "--source projects/app --source projects/lib --dest archive --relative"
+    Then the exit code should be 0
+    And the file "archive/projects/app/main.go" should exist in the temp directory
+    And the file "archive/projects/lib/lib.go" should exist in the temp directory
+
+  Scenario: The summary covers every source
+    Given the temp directory has a file "projects/app/main.go"
+    And the temp directory has a file "projects/lib/lib.go"
+    When I run sync-tools sync with flags "--source projects/app --source projects/lib --dest archive --summary-only"
+    Then the exit code should be 0
+    And the output should contain "Sync summary: 2 created"
+
+  Scenario: Sources with the same name are refused
+    Given the temp directory has a file "one/app/a.txt"
+    And the temp directory has a file "two/app/b.txt"
+    When I run sync-tools sync with flags "--source one/app --source two/app --dest archive"
+    Then the exit code should be 1
+    And the output should contain "would both sync into"
+    And the file "archive/app/a.txt" should not exist in the temp directory
+
+  Scenario: The destination must be a directory
+    Given the temp directory has a file "projects/app/main.go"
+    And the temp directory has a file "projects/lib/lib.go"
+    And the temp directory has a file "archive"
+    When I run sync-tools sync with flags "--source projects/app --source projects/lib --dest archive"
+    Then the exit code should be 1
+    And the output should contain "dest must be a directory when syncing several sources"
@@ -0,0 +1,30 @@
+Feature: Nested Source and Destination
+  As a user
+  I want sync-tools to refuse source and dest paths that contain each other
+  So that a typo can't make rsync recurse into its own output or delete my source
+
+  Scenario: A dest inside the source is refused
+    Given the temp directory has a file "a/notes.txt"
+    When I run sync-tools sync with flags "--source ./a --dest ./a/backup"
+    Then the exit code should be 1
+    And the output should contain "is inside source"
+    And the output should contain "would be synced into itself"
+
+  Scenario: A source inside the dest is refused
+    Given the temp directory has a file "a/backup/notes.txt"
+    When I run sync-tools sync with flags "--source ./a/backup --dest ./a"
+    Then the exit code should be 1
+    And the output should contain "is inside dest"
+    And the output should contain "would be deleted by the sync"
+
+  Scenario: The same directory written two ways is refused
+    Given the temp directory has a file "a/notes.txt"
+    When I run sync-tools sync with flags "--source ./a --dest ./a/../a/"
+    Then the exit code should be 1
+    And the output should contain "into itself"
+
+  Scenario: A sibling whose name shares a prefix is not nested
+    Given the temp directory has a file "a/notes.txt"
+    When I run sync-tools sync with flags "--source ./a --dest ./ab"
+    Then the exit code should be 0
+    And the file "ab/notes.txt" should exist in the temp directory
@@ -0,0 +1,57 @@
+Feature: Nested .syncignore Files
+  As a user with a large source tree
+  I want a .syncignore in a subdirectory to apply to that subdirectory
+  So that each part of the tree can keep its own ignore rules, as with nested .gitignore files
+
+  Scenario: A subdirectory .syncignore applies to its subtree only
+    Given I have a source directory with files
+    And the source has a file "build/out.bin"
+    And the source has a file "docs/build/index.html"
+    And the source has a file "docs/.syncignore" containing:
+      """
+      build/
+      """
+    When I run sync-tools list on the source in "flat" format
+    Then the exit code should be 0
+    And the output should contain "build/out.bin"
+    And the output should not contain "docs/build/index.html"
+
+  Scenario: The closer .syncignore wins when the rules conflict
+    Given I have a source directory with files
+    And the source has a file ".syncignore" containing:
+      """
+      *.log
+      """
+    And the source has a file "logs/.syncignore" containing:
+      """
+      !debug.log
+      """
+    And the source has a file "logs/debug.log"
+    And the source has a file "logs/error.log"
+    When I run sync-tools check-filter for "logs/debug.log"
+    Then the output should contain "logs/debug.log: included by /logs/debug.log"
+    When I run sync-tools check-filter for "logs/error.log"
+    Then the output should contain "logs/error.log: excluded by *.log"
+
+  Scenario: Nested rules are passed to rsync anchored under their directory
+    Given I have a source directory with files
+    And the source has a file "docs/.syncignore" containing:
+      """
+      *.tmp
+      """
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags ""
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- /docs/*.tmp"
+    And rsync should have been given the filter rule "- /docs/**/*.tmp"
+
+  Scenario: --no-nested-syncignore reads only the top-level file
+    Given I have a source directory with files
+    And the source has a file "docs/build/index.html"
+    And the source has a file "docs/.syncignore" containing:
+      """
+      build/
+      """
+    When I run sync-tools list on the source in "flat" format with "--no-nested-syncignore"
+    Then the exit code should be 0
+    And the output should contain "docs/build/index.html"
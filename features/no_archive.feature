@@ -0,0 +1,42 @@
+Feature: Syncing Without --archive
+  As a user who wants full control of what rsync preserves
+  I want to turn off the implicit --archive
+  So that only the transfer options I ask for are used
+
+  Scenario: --archive is used by default
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags ""
+    Then the exit code should be 0
+    And rsync should have been called with argument "--archive"
+    And rsync should not have been called with argument "--recursive"
+
+  Scenario: --no-archive recurses without preserving attributes
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--no-archive"
+    Then the exit code should be 0
+    And rsync should not have been called with argument "--archive"
+    And rsync should have been called with argument "--recursive"
+    And rsync should have been called with argument "--links"
+
+  Scenario: Attributes can be asked for one at a time
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--no-archive --rsync-extra-args --times"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--recursive"
+    And rsync should have been called with argument "--times"
+    And rsync should not have been called with argument "--perms"
+
+  Scenario: --no-archive can be set in the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      no_archive = true
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should not have been called with argument "--archive"
+    And rsync should have been called with argument "--recursive"
@@ -0,0 +1,41 @@
+Feature: Completion Notifications
+  As a user running unattended backups
+  I want to be notified when a sync finishes
+  So that I hear about failures without reading logs
+
+  Scenario: A finished sync posts its summary to a webhook
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And a webhook receiver is listening
+    When I run sync-tools with one-way sync and notify to the webhook
+    Then the exit code should be 0
+    And the webhook should receive a successful notification with 1 run
+    And the webhook notification text should contain "3 created, 0 updated, 0 deleted"
+
+  Scenario: A SyncFile sends one notification covering every operation
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a SyncFile syncing the source to two destinations
+    And a webhook receiver is listening
+    When I run sync-tools syncfile with notify to the webhook
+    Then the exit code should be 0
+    And the webhook should receive a successful notification with 2 runs
+    And the webhook notification text should contain "6 created"
+
+  Scenario: A failed SyncFile operation is reported
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that hangs
+    And I have a SyncFile with two SYNC operations using the hanging rsync
+    And a webhook receiver is listening
+    When I run sync-tools syncfile with notify to the webhook and timeout "1s"
+    Then the exit code should be 1
+    And the webhook should receive a failed notification with 1 run
+    And the webhook notification text should contain "operation 1 timed out"
+
+  Scenario: An invalid notify target is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and notify "ftp://example.com"
+    Then the exit code should be 1
+    And the output should contain "invalid --notify"
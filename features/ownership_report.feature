@@ -0,0 +1,37 @@
+Feature: Destination Ownership Report
+  As a system administrator
+  I want to audit destination ownership after a sync
+  So that I can spot files owned by unexpected users
+
+  Scenario: Ownership matching the source is not flagged
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and a dest ownership report
+    Then the ownership report should show 0 differences
+    And the exit code should be 0
+
+  Scenario: Ownership differing from the expected owner is flagged
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and a dest ownership report expecting owner "54321:54321"
+    Then the ownership report should flag "file1.txt"
+    And the exit code should be 0
+
+  Scenario: An expected owner without the report is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--expected-owner 54321:54321"
+    Then the exit code should be 1
+    And the output should contain "--expected-owner only applies with --dest-ownership-report"
+
+  Scenario: The report needs a local dest
+    Given the temp directory has a file "src/a.txt"
+    When I run sync-tools sync with flags "--source src --dest user@host:/srv/backup --dest-ownership-report"
+    Then the exit code should be 1
+    And the output should contain "--dest-ownership-report needs a local dest"
+
+  Scenario: The report can't follow a relative sync's layout
+    Given the temp directory has a file "projects/app/main.go"
+    When I run sync-tools sync with flags "--source projects/app --dest archive --relative --dest-ownership-report"
+    Then the exit code should be 1
+    And the output should contain "cannot be combined with --relative"
@@ -0,0 +1,47 @@
+Feature: Resumable Transfers
+  As a user copying large files over a flaky link
+  I want interrupted transfers to resume
+  So that a dropped connection doesn't restart a multi-GB file from scratch
+
+  Scenario: --partial is passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--partial"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--partial"
+
+  Scenario: A relative partial dir is protected from deletion
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--partial-dir .rsync-partial"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--partial-dir=.rsync-partial"
+    And rsync should not have been called with argument "--partial"
+    And rsync should have been given the filter rule "P .rsync-partial/"
+
+  Scenario: An interrupted transfer resumes from its partial file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary whose connection drops once while sending "file1.txt"
+    When I run sync-tools with the interrupted rsync and flags "--partial-dir .rsync-partial --retry-files 2"
+    Then the exit code should be 0
+    And the output should contain "resuming from partial files (attempt 1/2)"
+    And the destination file "file1.txt" should contain "test content for file1.txt"
+    And the destination should not contain ".rsync-partial"
+
+  Scenario: Without retries an interrupted transfer fails and keeps its partial file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary whose connection drops once while sending "file1.txt"
+    When I run sync-tools with the interrupted rsync and flags "--partial-dir .rsync-partial"
+    Then the exit code should be 1
+    And the destination should contain ".rsync-partial/file1.txt"
+
+  Scenario: The partial dir is not flagged as a stale artifact
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has a file ".rsync-partial/big.iso"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--partial-dir .rsync-partial"
+    Then the exit code should be 0
+    And the output should not contain "Stale artifact"
@@ -0,0 +1,60 @@
+Feature: Preview Formats
+  As a user reviewing changes before a sync
+  I want to choose how the preview is rendered
+  So that I can pick the view that suits the change, with or without git
+
+  Scenario: A stat preview summarizes the changes without syncing
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--preview-format stat"
+    Then the exit code should be 0
+    And the output should contain "dest_only.txt"
+    And the output should contain "3 files changed"
+    And the destination should contain "dest_only.txt"
+    And the destination file "file2.txt" should contain "DIFFERENT content for file2.txt"
+
+  Scenario: A side-by-side preview shows dest and source in columns
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--preview-format side-by-side"
+    Then the exit code should be 0
+    And the output should contain "=== file2.txt (modified)"
+    And the output should contain "=== subdir/file3.txt (new file)"
+    And the destination should contain "dest_only.txt"
+
+  Scenario: A unified preview works without git
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    And git is not available
+    When I run sync-tools with one-way sync and flags "--preview-format unified"
+    Then the exit code should be 0
+    And the output should contain "+++ b/file2.txt"
+    And the output should contain "-DIFFERENT content for file2.txt"
+
+  Scenario: The default preview falls back to the built-in diff without git
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    And git is not available
+    When I run sync-tools with one-way sync and flags "--preview"
+    Then the exit code should be 0
+    And the output should contain "git not found; showing the built-in unified diff"
+    And the output should contain "+++ b/subdir/file3.txt"
+
+  Scenario: The preview format can be set in the config file
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    And I have a config file containing:
+      """
+      preview = true
+      preview_format = "stat"
+      """
+    When I run sync-tools with one-way sync using the config
+    Then the exit code should be 0
+    And the output should contain "3 files changed"
+
+  Scenario: An unknown preview format is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--preview-format fancy"
+    Then the exit code should be 1
+    And the output should contain "invalid --preview-format: fancy"
@@ -0,0 +1,27 @@
+Feature: Transfer Progress
+  As a user running multi-GB syncs
+  I want to see how far along a sync is and how fast it is going
+  So that I know whether to wait or walk away
+
+  Scenario: --progress asks rsync for overall progress
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--progress"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--info=progress2"
+
+  Scenario: Progress lines are summarized rather than logged
+    Given I have a source directory with files
+    And I have an rsync binary that reports progress
+    When I run sync-tools with the progress rsync and flags "--progress"
+    Then the exit code should be 0
+    And the output should contain "Transferred 2000 bytes in"
+    And the output should contain "MB/s)"
+    And the output should not contain "to-chk"
+
+  Scenario: Dry runs have no progress to report
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--progress --dry-run"
+    Then the exit code should be 0
+    And rsync should not have been called with argument "--info=progress2"
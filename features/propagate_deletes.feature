@@ -0,0 +1,53 @@
+Feature: Propagating Deletes in Two-way Syncs
+  As a user keeping two copies of a tree in step
+  I want a file deleted from the destination to be deleted from the source too
+  So that two-way syncs don't bring deleted files back
+
+  Scenario: By default a source file missing from the dest is copied back
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--mode two-way"
+    Then the exit code should be 0
+    And the source should contain "subdir/file3.txt"
+    And the destination should contain "subdir/file3.txt"
+
+  Scenario: --propagate-deletes deletes it from the source instead
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--mode two-way --propagate-deletes --yes"
+    Then the exit code should be 0
+    And the output should contain "Deleted from source: subdir/file3.txt"
+    And the source should not contain "subdir/file3.txt"
+    And the destination should not contain "subdir/file3.txt"
+    And the source should contain "file1.txt"
+
+  Scenario: Without --yes and no one to ask, nothing is deleted
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--mode two-way --propagate-deletes"
+    Then the exit code should be 0
+    And the output should contain "Not propagating deletes"
+    And the source should contain "subdir/file3.txt"
+
+  Scenario: A dry run only lists what would be deleted
+    Given I have a source directory with files
+    And I have a destination directory with some matching and some different files
+    When I run sync-tools with one-way sync and flags "--mode two-way --propagate-deletes --yes --dry-run"
+    Then the exit code should be 0
+    And the output should contain "Would delete 1 files from the source"
+    And the source should contain "subdir/file3.txt"
+
+  Scenario: An empty destination never empties the source
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--mode two-way --propagate-deletes --yes"
+    Then the exit code should be 0
+    And the output should contain "empty or missing; not propagating deletes"
+    And the source should contain "file1.txt"
+
+  Scenario: The option needs two-way mode
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--propagate-deletes"
+    Then the exit code should be 1
+    And the output should contain "--propagate-deletes only applies to --mode two-way"
@@ -0,0 +1,32 @@
+Feature: Pruning Empty Directories
+  As a user syncing a filtered subset of a tree
+  I want directories the filters emptied left out of the destination
+  So that the destination isn't cluttered with empty directory skeletons
+
+  Scenario: rsync is asked to prune empty directories
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--prune-empty-dirs"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--prune-empty-dirs"
+
+  Scenario: Directories that are empty in the source are kept
+    Given I have a source directory with files
+    And the source has an empty directory "cache"
+    And the source has a file "logs/debug.log"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--prune-empty-dirs --ignore-src *.log"
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "P /cache/"
+    And rsync should not have been given the filter rule "P /logs/"
+
+  Scenario: Pruning can be enabled in the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      prune_empty_dirs = true
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been called with argument "--prune-empty-dirs"
@@ -0,0 +1,49 @@
+Feature: Relative Path Syncs
+  As a user syncing several sources into one archive
+  I want rsync's --relative behavior
+  So that each source keeps its path structure under the destination
+
+  Scenario: The source path is recreated under the destination
+    Given the temp directory has a file "projects/app/main.go"
+    When I run sync-tools sync with flags "--source projects/app --dest archive --relative"
+    Then the exit code should be 0
+    And the file "archive/projects/app/main.go" should exist in the temp directory
+
+  Scenario: A /./ in the source marks where the kept path starts
+    Given the temp directory has a file "projects/app/main.go"
+    When I run sync-tools sync with flags "--source projects/./app --dest archive --relative"
+    Then the exit code should be 0
+    And the file "archive/app/main.go" should exist in the temp directory
+
+  Scenario: Several sources share one destination
+    Given the temp directory has a file "projects/app/main.go"
+    And the temp directory has a file "projects/lib/lib.go"
+    When I run sync-tools sync with flags "--source projects/app --dest archive --relative"
+    And I run sync-tools sync with flags "--source projects/lib --dest archive --relative"
+    Then the exit code should be 0
+    And the file "archive/projects/app/main.go" should exist in the temp directory
+    And the file "archive/projects/lib/lib.go" should exist in the temp directory
+
+  Scenario: Relative syncs can't be two-way
+    Given the temp directory has a file "projects/app/main.go"
+    When I run sync-tools sync with flags "--source projects/app --dest archive --relative --mode two-way"
+    Then the exit code should be 1
+    And the output should contain "--relative cannot be combined with two-way mode"
+
+  Scenario: Relative syncs can't write a patch
+    Given the temp directory has a file "projects/app/main.go"
+    When I run sync-tools sync with flags "--source projects/app --dest archive --relative --patch out.patch"
+    Then the exit code should be 1
+    And the output should contain "--relative cannot be combined with two-way mode, --files-from, --preview, or --patch"
+    And the file "out.patch" should not exist in the temp directory
+
+  Scenario: A SyncFile recreates source paths with RELATIVE
+    Given the temp directory has a file "projects/app/main.go"
+    And the SyncFile "Archive.SyncFile" contains:
+      """
+      SYNC projects/app archive
+      RELATIVE true
+      """
+    When I run sync-tools syncfile "Archive.SyncFile"
+    Then the exit code should be 0
+    And the file "archive/projects/app/main.go" should exist in the temp directory
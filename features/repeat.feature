@@ -0,0 +1,29 @@
+Feature: Repeating the Last Sync
+  As a user who syncs the same pair of directories again and again
+  I want to re-run the last sync without retyping its options
+  So that a quick re-sync is a single short command
+
+  Scenario: --repeat reuses the last sync's source, dest, and filters
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the source has a file "notes.tmp"
+    When I run sync-tools with one-way sync and flags "--ignore-src *.tmp"
+    And the source has a file "added.txt"
+    And I run sync-tools sync with flags "--repeat"
+    Then the exit code should be 0
+    And the destination should contain "added.txt"
+    When I run sync-tools sync with flags "--repeat --filter-test notes.tmp"
+    Then the output should contain "notes.tmp: excluded by *.tmp"
+
+  Scenario: Flags passed with --repeat override the recorded options
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    And I run sync-tools sync with flags "--repeat --dest other"
+    Then the exit code should be 0
+    And the destination "other" should contain "file1.txt"
+
+  Scenario: --repeat needs an earlier sync
+    When I run sync-tools sync with flags "--repeat"
+    Then the exit code should be 1
+    And the output should contain "no previous sync recorded"
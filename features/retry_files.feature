@@ -0,0 +1,13 @@
+Feature: Retry Failed Files
+  As a DevOps engineer
+  I want transiently failed files to be retried
+  So that a single flaky file doesn't fail the whole sync
+
+  Scenario: A file that fails once succeeds on the retry pass
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And rsync fails to transfer "file2.txt" once
+    When I run sync-tools with one-way sync and 2 file retries
+    Then the failed file should be retried successfully
+    And files should be copied to destination
+    And the exit code should be 0
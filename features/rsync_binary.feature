@@ -0,0 +1,19 @@
+Feature: Custom rsync Binary
+  As a user with several rsync installs
+  I want to choose which rsync binary sync-tools runs
+  So that I can use a newer build than the system default
+
+  Scenario: A custom rsync binary is used for the sync
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and the rsync binary from PATH
+    Then the exit code should be 0
+    And the destination should contain "file1.txt"
+
+  Scenario: A missing rsync binary fails with a clear error
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and rsync binary "/nonexistent/rsync"
+    Then the exit code should be 1
+    And the output should contain "rsync not found"
+    And the destination should not contain "file1.txt"
@@ -0,0 +1,27 @@
+Feature: rsync Daemon Targets
+  As a user with rsync daemon mirrors
+  I want to sync to rsync:// and host::module targets
+  So that I can push to mirrors that don't offer SSH
+
+  Scenario: A daemon URL is passed to rsync unchanged with the password file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync to "rsync://mirror.example.com/backup/project" and password file "/etc/rsync.secret"
+    Then the exit code should be 0
+    And rsync should have been called with argument "rsync://mirror.example.com/backup/project"
+    And rsync should have been called with argument "--password-file"
+    And rsync should have been called with argument "/etc/rsync.secret"
+
+  Scenario: The host::module form is a daemon target
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync to "backup@mirror.example.com::backup/project" and password file "/etc/rsync.secret"
+    Then the exit code should be 0
+    And rsync should have been called with argument "backup@mirror.example.com::backup/project"
+
+  Scenario: A password file without a daemon target is rejected
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync to "user@host:/srv/backup" and password file "/etc/rsync.secret"
+    Then the exit code should be 1
+    And the output should contain "--password-file only applies to rsync daemon targets"
@@ -0,0 +1,53 @@
+Feature: Extra rsync Arguments
+  As a user who needs an rsync option sync-tools doesn't expose
+  I want to pass extra arguments straight to rsync
+  So that I am not blocked waiting for a dedicated flag
+
+  Scenario: Extra arguments are passed to rsync with quotes honored
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and extra rsync arguments "--chmod=D755 --rsh='ssh -p 2222'"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--chmod=D755"
+    And rsync should have been called with argument "--rsh=ssh -p 2222"
+    And the output should contain "Executing rsync command"
+
+  Scenario: Overriding a flag sync-tools manages is warned about
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and extra rsync arguments "--no-delete"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--no-delete"
+    And the output should contain "override flags sync-tools manages"
+
+  Scenario: An unbalanced quote is rejected
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and extra rsync arguments "--rsh='ssh -p 2222"
+    Then the exit code should be 1
+    And the output should contain "invalid --rsync-extra-args: unterminated quote"
+
+  Scenario: Extra arguments can come from the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      rsync_extra_args = "--bwlimit=1000"
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been called with argument "--bwlimit=1000"
+
+  Scenario: A SyncFile passes extra arguments with RSYNCARGS
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And the SyncFile "Extra.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      RSYNCBIN {tmp}/recording-rsync
+      RSYNCARGS --chmod=F644 --rsh="ssh -p 2222"
+      """
+    When I run sync-tools syncfile "Extra.SyncFile"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--chmod=F644"
+    And rsync should have been called with argument "--rsh=ssh -p 2222"
@@ -0,0 +1,29 @@
+Feature: rsync Transfer Log
+  As a user auditing transfers
+  I want rsync to write its own transfer log
+  So that I keep a canonical record apart from the sync-tools log
+
+  Scenario: The log file and format are passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--rsync-log-file /tmp/transfers.log --rsync-log-format %o:%f"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--log-file=/tmp/transfers.log"
+    And rsync should have been called with argument "--log-file-format=%o:%f"
+
+  Scenario: The log file can come from the config file
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And I have a config file containing:
+      """
+      rsync_log_file = "/tmp/transfers.log"
+      """
+    When I run sync-tools with the recording rsync using the config
+    Then the exit code should be 0
+    And rsync should have been called with argument "--log-file=/tmp/transfers.log"
+
+  Scenario: A log format needs a log file
+    Given I have a source directory with files
+    When I run sync-tools with one-way sync and flags "--rsync-log-format %o:%f"
+    Then the exit code should be 1
+    And the output should contain "--rsync-log-format needs --rsync-log-file"
@@ -0,0 +1,21 @@
+Feature: Safe Mode
+  As a team lead
+  I want syncs to default to dry runs for a project
+  So that nobody runs a destructive sync by accident
+
+  Scenario: Safe mode turns a sync into a dry run
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a config file with safe mode enabled
+    When I run sync-tools with one-way sync using the config
+    Then the output should contain "Safe mode is enabled"
+    And the destination should not contain "file1.txt"
+    And the exit code should be 0
+
+  Scenario: Execute applies changes in safe mode
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a config file with safe mode enabled
+    When I run sync-tools with one-way sync using the config and execute
+    Then the destination should contain "file1.txt"
+    And the exit code should be 0
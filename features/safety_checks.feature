@@ -0,0 +1,19 @@
+Feature: Safety Checks
+  As a user
+  I want sync-tools to stop before destructive surprises
+  So that an empty source doesn't wipe my destination
+
+  Scenario: Empty source over a populated destination is refused
+    Given I have an empty source directory
+    And I have a destination directory with files
+    When I run sync-tools with one-way sync
+    Then the exit code should be 1
+    And the output should contain "is empty and syncing would delete all"
+    And the destination should contain "dest_file1.txt"
+
+  Scenario: Force allows mirroring an empty source
+    Given I have an empty source directory
+    And I have a destination directory with files
+    When I run sync-tools with one-way sync and force
+    Then the exit code should be 0
+    And the destination should not contain "dest_file1.txt"
@@ -0,0 +1,46 @@
+Feature: Size-Only and Ignore-Times Comparison
+  As a user re-syncing large trees
+  I want to choose how rsync decides a file has changed
+  So that I can skip re-copying append-only logs or force a full refresh
+
+  Scenario: --size-only is passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--size-only"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--size-only"
+
+  Scenario: --ignore-times is passed to rsync
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--ignore-times"
+    Then the exit code should be 0
+    And rsync should have been called with argument "--ignore-times"
+
+  Scenario: The estimate skips same-size files under --size-only
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an older copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync and flags "--estimate --size-only"
+    Then the exit code should be 0
+    And the output should contain "(2 created, 0 updated)"
+
+  Scenario: The estimate counts every file under --ignore-times
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    When I run sync-tools with one-way sync and flags "--estimate --ignore-times"
+    Then the exit code should be 0
+    And the output should contain "(2 created, 1 updated)"
+
+  Scenario: The two modes cannot be combined
+    Given I have a source directory with files
+    When I run sync-tools with one-way sync and flags "--size-only --ignore-times"
+    Then the exit code should be 1
+    And the output should contain "--size-only and --ignore-times cannot be used together"
+
+  Scenario: A checksum comparison cannot be combined with --size-only
+    Given I have a source directory with files
+    When I run sync-tools with one-way sync and flags "--size-only --rsync-extra-args --checksum"
+    Then the exit code should be 1
+    And the output should contain "cannot be combined with rsync's --checksum"
@@ -0,0 +1,60 @@
+Feature: Stale Artifact Detection
+  As a user
+  I want leftovers from interrupted runs to be noticed
+  So that I don't build a new sync on top of a half-finished one
+
+  Scenario: A leftover partial directory is reported before syncing
+    Given I have a source directory with files
+    And I have a destination directory with a leftover partial transfer
+    When I run sync-tools with one-way sync
+    Then the output should contain "Stale artifact from a previous run: .rsync-partial (rsync partial dir)"
+    And the exit code should be 1
+    And the destination should not contain "file1.txt"
+
+  Scenario: Stale artifacts can be ignored
+    Given I have a source directory with files
+    And I have a destination directory with a leftover partial transfer
+    When I run sync-tools with one-way sync ignoring stale artifacts
+    Then the exit code should be 0
+    And the destination should contain "file1.txt"
+
+  Scenario: A dotfile shaped like an rsync temp file is reported but never removed
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has a file ".env.Local1"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--yes --clean-stale-artifacts"
+    Then the exit code should be 0
+    And the output should contain "Stale artifact from a previous run: .env.Local1 (rsync temp file)"
+    And the destination should contain ".env.Local1"
+
+  Scenario: Conflict copies are kept unless cleanup is asked for
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has a file "notes.conflict-1700000000"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--yes"
+    Then the exit code should be 0
+    And the output should contain "notes.conflict-1700000000 (conflict copy)"
+    And the destination should contain "notes.conflict-1700000000"
+    When I run sync-tools with the recording rsync and flags "--clean-stale-artifacts"
+    Then the exit code should be 0
+    And the destination should not contain "notes.conflict-1700000000"
+
+  Scenario: Dest paths left alone by --ignore-dest are not scanned
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has a file ".rsync-partial/big.iso"
+    And I have an rsync binary that records its arguments
+    When I run sync-tools with the recording rsync and flags "--ignore-dest .rsync-partial/"
+    Then the exit code should be 0
+    And the output should not contain "Stale artifact"
+
+  Scenario: Nothing is cleaned up when the sync can't run
+    Given I have a source directory with files
+    And I have a destination directory with a leftover partial transfer
+    When I run sync-tools with one-way sync and flags "--clean-stale-artifacts --rsync-binary /nonexistent/rsync"
+    Then the exit code should be 1
+    And the output should contain "rsync not found"
+    And the output should not contain "Stale artifact"
+    And the destination should contain ".rsync-partial/file1.txt"
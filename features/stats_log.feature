@@ -0,0 +1,12 @@
+Feature: Stats Log
+  As an operator
+  I want each run's stats appended to a log file
+  So that I can monitor syncs over time
+
+  Scenario: Each run appends one JSON line
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync appending to the stats log
+    And I run sync-tools with one-way sync appending to the stats log
+    Then the stats log should have 2 JSON lines with increasing timestamps
+    And the exit code should be 0
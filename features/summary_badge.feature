@@ -0,0 +1,11 @@
+Feature: Summary Badge
+  As a CI pipeline author
+  I want a single greppable summary line
+  So that I can track sync changes in build logs
+
+  Scenario: Badge reflects created and deleted files
+    Given I have a source directory with files
+    And I have a destination directory with different files
+    When I run sync-tools with one-way sync and a summary badge
+    Then the output should contain "SYNC_SUMMARY created=3 updated=0 deleted=2 conflicts=0 bytes=85"
+    And the exit code should be 0
@@ -0,0 +1,30 @@
+Feature: Summary-Only Output
+  As a user running syncs from cron
+  I want a single summary line instead of per-file output
+  So that job logs stay short but still say what happened
+
+  Scenario: A summary-only sync prints one summary line
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--summary-only"
+    Then the exit code should be 0
+    And the output should contain "Sync summary: 3 created, 0 updated, 0 deleted"
+    And the output should contain "transferred in"
+    And the output should not contain "[STDOUT]"
+    And the output should not contain "Sync completed successfully"
+    And the destination should contain "file1.txt"
+
+  Scenario: A summary-only dry run labels its summary
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--summary-only --dry-run"
+    Then the exit code should be 0
+    And the output should contain "Dry run summary: 3 created"
+    And the output should contain "to transfer in"
+
+  Scenario: Summary-only output cannot be combined with the interactive interface
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and flags "--summary-only --interactive"
+    Then the exit code should be 1
+    And the output should contain "--summary-only cannot be combined with --interactive"
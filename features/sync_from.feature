@@ -0,0 +1,51 @@
+Feature: Sync From
+  As a user
+  I want to pull a source into the current directory
+  So that I don't have to spell out --dest . and my project config still applies
+
+  Scenario: Pull a source into the current directory
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools sync from the source directory in the destination
+    Then the exit code should be 0
+    And the destination should contain "subdir/file3.txt"
+
+  Scenario: The project config in the current directory is honored
+    Given I have a source directory with files
+    And I have an rsync binary that records its arguments
+    And the destination has a sync.toml containing:
+      """
+      rsync_binary = "{tmp}/recording-rsync"
+      ignore_src = ["*.log"]
+      ignore_dest = ["!/sync.toml"]
+      """
+    When I run sync-tools sync from the source directory in the destination
+    Then the exit code should be 0
+    And rsync should have been given the filter rule "- *.log"
+    And rsync should have been given the filter rule "P /sync.toml"
+
+  Scenario: The report path comes from the project config
+    Given I have a source directory with files
+    And the destination has a sync.toml containing:
+      """
+      report = "{tmp}/pull-report.md"
+      """
+    When I run sync-tools sync from the source directory in the destination
+    Then the exit code should be 0
+    And the file "pull-report.md" should exist in the temp directory
+
+  Scenario: The source can come from the project config
+    Given I have a source directory with files
+    And the destination has a sync.toml containing:
+      """
+      source = "{source}"
+      """
+    When I run sync-tools sync from without a source in the destination
+    Then the exit code should be 0
+    And the destination should contain "subdir/file3.txt"
+
+  Scenario: A source is required
+    Given I have an empty destination directory
+    When I run sync-tools sync from without a source in the destination
+    Then the exit code should be 1
+    And the output should contain "source and dest must be provided"
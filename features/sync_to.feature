@@ -0,0 +1,17 @@
+Feature: Sync To
+  As a user
+  I want to push the current directory to a destination
+  So that I don't have to spell out --source .
+
+  Scenario: Push the current directory to a destination
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools sync to the destination from the source directory
+    Then the exit code should be 0
+    And the destination should contain "subdir/file3.txt"
+
+  Scenario: Syncing the current directory into itself is refused
+    Given I have a source directory with files
+    When I run sync-tools sync to "." from the source directory
+    Then the exit code should be 1
+    And the output should contain "into itself"
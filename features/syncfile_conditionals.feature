@@ -0,0 +1,100 @@
+Feature: SyncFile Conditional Instructions
+  As a user
+  I want to guard SyncFile operations with IF/ENDIF blocks
+  So that one SyncFile can serve several environments
+
+  Scenario: Guarded SYNC runs when the condition holds
+    Given the environment variable "ENV" is set to "prod"
+    And I have a SyncFile with a SYNC guarded by "IF ${ENV}==prod"
+    When I run sync-tools syncfile with list
+    Then the SyncFile should report 2 sync operations
+    And the exit code should be 0
+
+  Scenario: Guarded SYNC is skipped when the condition does not hold
+    Given the environment variable "ENV" is set to "dev"
+    And I have a SyncFile with a SYNC guarded by "IF ${ENV}==prod"
+    When I run sync-tools syncfile with list
+    Then the SyncFile should report 1 sync operations
+    And the exit code should be 0
+
+  Scenario: A single = compares the same way
+    Given the environment variable "ENV" is set to "prod"
+    And I have a SyncFile with a SYNC guarded by "IF ${ENV}=prod"
+    When I run sync-tools syncfile with list
+    Then the SyncFile should report 2 sync operations
+    And the exit code should be 0
+
+  Scenario: An IF without a comparison is rejected
+    Given I have a SyncFile with a SYNC guarded by "IF ${ENV}"
+    When I run sync-tools syncfile with list
+    Then the exit code should be 1
+    And the output should contain "IF requires format: ${VAR}=value or ${VAR}==value"
+
+  Scenario: A VAR in the SyncFile wins over the environment
+    Given the environment variable "ENV" is set to "prod"
+    And the SyncFile "main.syncfile" contains:
+      """
+      VAR ENV=dev
+      SYNC {source} {dest}
+      IF ${ENV}==prod
+      SYNC {source} {dest}_prod
+      ENDIF
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the SyncFile should report 1 sync operations
+    And the exit code should be 0
+
+  Scenario: An undefined variable doesn't match
+    Given I have a SyncFile with a SYNC guarded by "IF ${SYNC_TOOLS_UNSET_VAR}==prod"
+    When I run sync-tools syncfile with list
+    Then the SyncFile should report 1 sync operations
+    And the exit code should be 0
+
+  Scenario: IF-OS keeps instructions for a matching OS
+    Given the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      IF-OS darwin linux windows freebsd openbsd netbsd
+      SYNC {source} {dest}_os
+      ENDIF
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the SyncFile should report 2 sync operations
+    And the exit code should be 0
+
+  Scenario: IF-OS skips instructions for other OSes
+    Given the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      IF-OS plan9
+      SYNC {source} {dest}_os
+      ENDIF
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the SyncFile should report 1 sync operations
+    And the exit code should be 0
+
+  Scenario: IF-OS rejects names that aren't Go OS names
+    Given the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      IF-OS macos
+      ENDIF
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the exit code should be 1
+    And the output should contain "IF-OS: unknown OS"
+    And the output should contain "macos"
+
+  Scenario: The environment only fills in IF conditions
+    Given the environment variable "STAGE" is set to "prod"
+    And the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}/$STAGE
+      EXCLUDE *.$STAGE
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the exit code should be 0
+    And the output should contain "/$STAGE"
+    And the output should contain "*.$STAGE"
+    And the output should not contain "prod"
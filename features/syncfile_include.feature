@@ -0,0 +1,66 @@
+Feature: SyncFile Includes
+  As a user with many SyncFiles
+  I want to keep shared instructions in one file and include it
+  So that common variables and filters are defined once
+
+  Scenario: Included instructions apply where the INCLUDE-FILE appears
+    Given the SyncFile "common/excludes.syncfile" contains:
+      """
+      VAR SUFFIX=_copy
+      EXCLUDE *.tmp
+      EXCLUDE .DS_Store
+      """
+    And the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      INCLUDE-FILE common/excludes.syncfile
+      SYNC {source} {dest}${SUFFIX}
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the exit code should be 0
+    And the SyncFile should report 2 sync operations
+    And the output should contain "Filters: [*.tmp .DS_Store]"
+    And the output should contain "_copy"
+
+  Scenario: Include paths are resolved relative to the including file
+    Given the SyncFile "nested/inner.syncfile" contains:
+      """
+      EXCLUDE build/
+      """
+    And the SyncFile "nested/outer.syncfile" contains:
+      """
+      INCLUDE-FILE inner.syncfile
+      """
+    And the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      INCLUDE-FILE nested/outer.syncfile
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the exit code should be 0
+    And the output should contain "Filters: [build/]"
+
+  Scenario: Include cycles are reported
+    Given the SyncFile "a.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      INCLUDE-FILE b.syncfile
+      """
+    And the SyncFile "b.syncfile" contains:
+      """
+      INCLUDE-FILE a.syncfile
+      """
+    When I run sync-tools syncfile "a.syncfile" with list
+    Then the exit code should be 1
+    And the output should contain "include cycle"
+    And the output should contain "a.syncfile -> "
+
+  Scenario: INCLUDE-FILE needs exactly one path
+    Given the SyncFile "main.syncfile" contains:
+      """
+      SYNC {source} {dest}
+      INCLUDE-FILE
+      """
+    When I run sync-tools syncfile "main.syncfile" with list
+    Then the exit code should be 1
+    And the output should contain "line 2: INCLUDE-FILE requires exactly 1 argument: path"
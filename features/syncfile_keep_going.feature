@@ -0,0 +1,48 @@
+Feature: Continuing a SyncFile Past Failed Operations
+  As a user running nightly backups to several targets
+  I want one broken target not to skip the others
+  So that every target that can be backed up is
+
+  Scenario: The first failure stops the run by default
+    Given I have a source directory with files
+    And the SyncFile "Backups.SyncFile" contains:
+      """
+      SYNC {source} {dest}/first
+      PLAN missing/plan.md
+
+      SYNC {source} {dest}/second
+      """
+    When I run sync-tools syncfile "Backups.SyncFile"
+    Then the exit code should be 1
+    And the output should contain "sync operation 1 failed"
+    And the destination should not contain "second/file1.txt"
+
+  Scenario: --keep-going runs the rest and exits with status 2
+    Given I have a source directory with files
+    And the SyncFile "Backups.SyncFile" contains:
+      """
+      SYNC {source} {dest}/first
+      PLAN missing/plan.md
+
+      SYNC {source} {dest}/second
+      """
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--keep-going"
+    Then the exit code should be 2
+    And the output should contain "Operation results:"
+    And the output should contain "1   FAILED"
+    And the output should contain "2   ok"
+    And the output should contain "1 of 2 sync operations failed"
+    And the destination should contain "second/file1.txt"
+
+  Scenario: --keep-going exits 0 when every operation succeeds
+    Given I have a source directory with files
+    And the SyncFile "Backups.SyncFile" contains:
+      """
+      SYNC {source} {dest}/first
+
+      SYNC {source} {dest}/second
+      """
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--keep-going"
+    Then the exit code should be 0
+    And the output should contain "All sync operations completed successfully"
+    And the output should not contain "FAILED"
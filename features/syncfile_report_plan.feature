@@ -0,0 +1,57 @@
+Feature: Per-Operation SyncFile Reports and Plans
+  As a user with several SYNC blocks in one SyncFile
+  I want each block to choose its own output
+  So that one run can report on one sync and only plan another
+
+  Scenario: REPORT writes a markdown report and PLAN leaves its block unsynced
+    Given I have a source directory with files
+    And the SyncFile "Site.SyncFile" contains:
+      """
+      SYNC {source} {dest}/docs
+      REPORT docs-sync.md
+
+      SYNC {source} {dest}/code
+      PLAN code-plan.md
+      """
+    When I run sync-tools syncfile "Site.SyncFile"
+    Then the exit code should be 0
+    And the destination should contain "docs/file1.txt"
+    And the destination should not contain "code/file1.txt"
+    And the file "docs-sync.md" in the temp directory should contain "# Sync Report"
+    And the file "docs-sync.md" in the temp directory should contain "| created | file1.txt |"
+    And the file "code-plan.md" in the temp directory should contain "# Sync Plan"
+    And the file "code-plan.md" in the temp directory should contain "| created | subdir/file3.txt |"
+
+  Scenario: REPORT with a patch extension writes a patch
+    Given I have a source directory with files
+    And the SyncFile "Patch.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      REPORT changes.patch
+      """
+    When I run sync-tools syncfile "Patch.SyncFile"
+    Then the exit code should be 0
+    And the file "changes.patch" should exist in the temp directory
+
+  Scenario: REPORT and PLAN are shown by --list
+    Given I have a source directory with files
+    And the SyncFile "Site.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      PLAN code-plan.md
+      """
+    When I run sync-tools syncfile "Site.SyncFile" with list
+    Then the exit code should be 0
+    And the output should contain "Plan: "
+    And the file "code-plan.md" should not exist in the temp directory
+
+  Scenario: PLAN needs a file name
+    Given I have a source directory with files
+    And the SyncFile "Bad.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      PLAN
+      """
+    When I run sync-tools syncfile "Bad.SyncFile"
+    Then the exit code should be 1
+    And the output should contain "PLAN requires exactly 1 argument"
@@ -0,0 +1,48 @@
+Feature: Resuming an Interrupted SyncFile
+  As a user running a SyncFile with many operations
+  I want a re-run after a failure to skip the operations that already finished
+  So that one failure late in the file doesn't mean redoing everything
+
+  Background:
+    Given I have a source directory with files
+    And the SyncFile "Backups.SyncFile" contains:
+      """
+      SYNC {source} {dest}/first
+
+      SYNC {source} {dest}/second
+      PLAN plans/second.md
+
+      SYNC {source} {dest}/third
+      """
+
+  Scenario: Finished operations are journalled and skipped on resume
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--resume --keep-going"
+    Then the exit code should be 2
+    And the file "Backups.SyncFile.journal" should exist in the temp directory
+    Given the temp directory has a file "plans/README"
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--resume"
+    Then the exit code should be 0
+    And the output should contain "Skipping sync operation 1/3, finished by an earlier run"
+    And the output should contain "Skipping sync operation 3/3, finished by an earlier run"
+    And the output should not contain "Skipping sync operation 2/3"
+    And the file "plans/second.md" should exist in the temp directory
+    And the file "Backups.SyncFile.journal" should not exist in the temp directory
+
+  Scenario: --restart ignores the journal
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--resume --keep-going"
+    Then the exit code should be 2
+    Given the temp directory has a file "plans/README"
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--restart"
+    Then the exit code should be 0
+    And the output should not contain "Skipping sync operation"
+    And the file "Backups.SyncFile.journal" should not exist in the temp directory
+
+  Scenario: Runs without --resume keep no journal
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--keep-going"
+    Then the exit code should be 2
+    And the file "Backups.SyncFile.journal" should not exist in the temp directory
+
+  Scenario: --resume and --restart can't be combined
+    When I run sync-tools syncfile "Backups.SyncFile" with flags "--resume --restart"
+    Then the exit code should be 1
+    And the output should contain "--resume and --restart cannot be used together"
@@ -0,0 +1,36 @@
+Feature: SyncFile Summary
+  As a user running multi-operation SyncFiles
+  I want an aggregate of what changed
+  So that I can review a whole run at a glance
+
+  Scenario: Changes are totalled across operations
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a SyncFile syncing the source to two destinations
+    When I run sync-tools syncfile with a report
+    Then the output should contain "Summary: 6 created, 0 updated, 0 deleted, 170 bytes across 2 operations"
+    And the SyncFile report should contain "| **Total** | | | 6 | 0 | 0 | 0 | 170 |"
+    And the exit code should be 0
+
+  Scenario: Each operation logs what it moved
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a SyncFile syncing the source to two destinations
+    When I run sync-tools syncfile with a report
+    Then the output should contain "Sync operation 1/2: 3 created, 0 updated, 0 deleted, 85 B transferred in"
+    And the output should contain "Sync operation 2/2: 3 created, 0 updated, 0 deleted, 85 B transferred in"
+    And the exit code should be 0
+
+  Scenario: Identical files are counted apart from transfers
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the destination has an exact copy of the source file "file1.txt"
+    And the SyncFile "Unchanged.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      INCLUDEUNCHANGED true
+      """
+    When I run sync-tools syncfile "Unchanged.SyncFile"
+    Then the exit code should be 0
+    And the output should contain "Sync operation 1/1: 2 created, 0 updated, 0 deleted, 1 unchanged, 59 B transferred in"
+    And the output should contain "Summary: 2 created, 0 updated, 0 deleted, 1 unchanged, 59 bytes across 1 operations"
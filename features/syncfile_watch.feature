@@ -0,0 +1,75 @@
+Feature: SyncFile Watch Mode
+  As a developer with several source trees feeding one deploy target
+  I want a SyncFile to keep re-running its watched operations when sources change
+  So that the target stays current while I work
+
+  Scenario: A watched operation re-syncs when its source changes
+    Given I have a source directory with files
+    And the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}/watched
+      WATCH true
+      """
+    When I watch the SyncFile "Watch.SyncFile" while adding "later.txt" to the source
+    Then the exit code should be 0
+    And the output should contain "Watching"
+    And the output should contain "Change detected"
+    And the destination should contain "watched/later.txt"
+    And the output should contain "Stopped watching"
+
+  Scenario: Changes the operation wouldn't sync don't trigger a re-sync
+    Given I have a source directory with files
+    And the source has a file ".syncignore" containing:
+      """
+      build/
+      """
+    And the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}/watched
+      WATCH true
+      """
+    When I watch the SyncFile "Watch.SyncFile" for 2 seconds while adding "build/out.bin" to the source
+    Then the exit code should be 0
+    And the output should contain "Watching for changes"
+    And the output should not contain "Change detected"
+
+  Scenario: Changes under .git don't trigger a re-sync
+    Given I have a source directory with files
+    And the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}/watched
+      WATCH true
+      """
+    When I watch the SyncFile "Watch.SyncFile" for 2 seconds while adding ".git/index" to the source
+    Then the exit code should be 0
+    And the output should not contain "Change detected"
+
+  Scenario: Watching needs an operation marked WATCH true
+    Given I have a source directory with files
+    And the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}/unwatched
+      """
+    When I run sync-tools syncfile "Watch.SyncFile" with flags "--watch"
+    Then the exit code should be 1
+    And the output should contain "--watch needs at least one SYNC operation with WATCH true"
+
+  Scenario: WATCH is shown when listing operations
+    Given the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      WATCH true
+      """
+    When I run sync-tools syncfile "Watch.SyncFile" with list
+    Then the exit code should be 0
+    And the output should contain "Watch: true"
+
+  Scenario: Watch mode can't be combined with a total timeout
+    Given the SyncFile "Watch.SyncFile" contains:
+      """
+      SYNC {source} {dest}
+      WATCH true
+      """
+    When I run sync-tools syncfile "Watch.SyncFile" with flags "--watch --total-timeout 1m"
+    Then the exit code should be 1
+    And the output should contain "cannot be combined with --total-timeout"
@@ -0,0 +1,25 @@
+Feature: Temp File Cleanup
+  As a user
+  I want sync-tools to clean up after itself
+  So that filter files don't pile up in my temp directory
+
+  Scenario: A filtered sync leaves no temp files
+    Given I have a source directory with files
+    And I have a .syncignore file in the source directory
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And no sync-tools temp files should remain
+
+  Scenario: A sync sweeps filter files left by a crashed run
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And the temp directory has a file "sync-tools-filter-99999999-abc.txt" last modified 2 hours ago
+    When I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And the file "sync-tools-filter-99999999-abc.txt" should not exist in the temp directory
+
+  Scenario: Commands that don't sync leave temp files alone
+    Given the temp directory has a file "sync-tools-filter-99999999-abc.txt" last modified 2 hours ago
+    When I run sync-tools with help
+    Then the file "sync-tools-filter-99999999-abc.txt" should exist in the temp directory
@@ -0,0 +1,48 @@
+Feature: Sync Timeout
+  As a user syncing to slow or flaky mounts
+  I want a sync that runs too long to be stopped
+  So that a hung rsync doesn't block my scripts forever
+
+  Scenario: A sync that exceeds --timeout stops rsync and fails
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that hangs
+    When I run sync-tools with one-way sync, the hanging rsync, and timeout "1s"
+    Then the exit code should be 1
+    And the output should contain "timed out after 1s"
+    And the hanging rsync should have been stopped
+
+  Scenario: A negative timeout is rejected
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync, the hanging rsync, and timeout "-1s"
+    Then the exit code should be 1
+    And the output should contain "invalid --timeout"
+
+  Scenario: The timeout log names the sync and its progress
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that hangs
+    When I run sync-tools with one-way sync, the hanging rsync, and timeout "1s"
+    Then the output should contain "Timed out syncing"
+    And the output should contain "last file reported: file1.txt"
+
+  Scenario: A SyncFile --timeout applies to each operation
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that hangs
+    And I have a SyncFile with two SYNC operations using the hanging rsync
+    When I run sync-tools syncfile with timeout "1s"
+    Then the exit code should be 1
+    And the output should contain "sync operation 1 failed"
+    And the output should contain "operation 1 timed out after 1s"
+    And the hanging rsync should have been stopped
+
+  Scenario: A SyncFile --total-timeout covers the whole file
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have an rsync binary that hangs
+    And I have a SyncFile with two SYNC operations using the hanging rsync
+    When I run sync-tools syncfile with total timeout "1s"
+    Then the exit code should be 1
+    And the output should contain "SyncFile timed out after 1s"
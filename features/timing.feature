@@ -0,0 +1,28 @@
+Feature: Sync Timing
+  As a user tracking backup performance
+  I want to see how long each sync took
+  So that I can spot regressions over time
+
+  Scenario: The completion line includes the duration
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync
+    Then the exit code should be 0
+    And the output should contain "Sync completed successfully in "
+
+  Scenario: --stats adds bytes and throughput to the completion line
+    Given I have a source directory with files
+    And I have an empty destination directory
+    When I run sync-tools with one-way sync and stats
+    Then the exit code should be 0
+    And the output should contain "(85 bytes, "
+    And the output should contain "MB/s)"
+
+  Scenario: The SyncFile report has a duration column
+    Given I have a source directory with files
+    And I have an empty destination directory
+    And I have a SyncFile syncing the source to two destinations
+    When I run sync-tools syncfile with a report
+    Then the exit code should be 0
+    And the SyncFile report should contain "| Bytes | Duration |"
+    And the output should contain "across 2 operations in "
@@ -0,0 +1,30 @@
+Feature: Undo Conflict Resolution
+  As a user whose two-way sync resolved a conflict the wrong way
+  I want to restore the conflict backups
+  So that the destination files are back to how they were
+
+  Scenario: Undo restores conflict backups
+    Given I have a destination directory with some matching and some different files
+    And the destination has a conflict backup of "file1.txt" from 1700000000 containing "before the conflict"
+    When I run sync-tools undo on the destination
+    Then the exit code should be 0
+    And the destination file "file1.txt" should contain "before the conflict"
+    And the destination should not contain "file1.txt.conflict-1700000000"
+
+  Scenario: Undo restores the oldest backup and can keep a redo copy
+    Given I have a destination directory with some matching and some different files
+    And the destination has a conflict backup of "file1.txt" from 1700000000 containing "first version"
+    And the destination has a conflict backup of "file1.txt" from 1700000500 containing "second version"
+    When I run sync-tools undo on the destination keeping redo copies
+    Then the exit code should be 0
+    And the destination file "file1.txt" should contain "first version"
+    And the destination should have a redo copy of "file1.txt"
+    And the destination should contain "file1.txt.conflict-1700000500"
+
+  Scenario: Undo ignores backups older than --since
+    Given I have a destination directory with some matching and some different files
+    And the destination has a conflict backup of "file1.txt" from 1700000000 containing "before the conflict"
+    When I run sync-tools undo on the destination since 1800000000
+    Then the exit code should be 0
+    And the output should contain "No conflict backups to restore"
+    And the destination should contain "file1.txt.conflict-1700000000"
@@ -0,0 +1,29 @@
+Feature: Version Information
+  As a user reporting a bug
+  I want to see exactly which build I am running
+  So that the report can be matched to the code
+
+  Scenario: The version command shows the build details
+    Given the sync-tools binary exists
+    When I run sync-tools version with flags ""
+    Then the exit code should be 0
+    And the output should contain "sync-tools 0.2.0"
+    And the output should contain "commit:"
+    And the output should contain "go:"
+
+  Scenario: The version command prints JSON for scripts
+    Given the sync-tools binary exists
+    When I run sync-tools version with flags "--json"
+    Then the exit code should be 0
+    And the output should be JSON with a non-empty "version"
+    And the output should be JSON with a non-empty "commit"
+    And the output should be JSON with a non-empty "build_date"
+    And the output should be JSON with a non-empty "go_version"
+    And the output should be JSON with a non-empty "platform"
+
+  Scenario: The --version flag still prints the bare version
+    Given the sync-tools binary exists
+    When I run sync-tools with the version flag
+    Then the exit code should be 0
+    And the output should contain "sync-tools version 0.2.0"
+    And the output should not contain "commit:"
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// assertCmd fails when a dry run would make changes of the asserted kinds
+var assertCmd = &cobra.Command{
+	Use:   "assert",
+	Short: "Fail if a sync would make changes of the given kinds",
+	Long: `Run a one-way dry run and exit with status 1 if it would create, update, or
+delete files of the asserted kinds, listing each offending path. Use it in CI to
+guard filters and curated mirrors: --expect-no-changes allows no changes at all,
+while --expect-no picks the kinds that must not occur.
+
+Nothing in the destination is changed.
+
+Examples:
+  sync-tools assert --source ./project --dest ./mirror --expect-no-changes
+  sync-tools assert --source ./project --dest ./mirror --expect-no creations
+  sync-tools assert --source ./project --dest ./mirror --expect-no deletions,updates`,
+	PreRunE: validateAssertFlags,
+	RunE:    runAssert,
+}
+
+// changeKindsByName maps --expect-no values to the change kinds they forbid
+var changeKindsByName = map[string]rsync.ChangeKind{
+	"creations": rsync.ChangeCreated,
+	"updates":   rsync.ChangeUpdated,
+	"deletions": rsync.ChangeDeleted,
+}
+
+var (
+	validExpectNo = []string{"creations", "updates", "deletions"}
+
+	flagAssertSource    string
+	flagExpectNoChanges bool
+	flagExpectNo        []string
+)
+
+func init() {
+	rootCmd.AddCommand(assertCmd)
+
+	assertCmd.Flags().BoolVar(&flagExpectNoChanges, "expect-no-changes", false, "Fail if any file or directory would be created, updated, or deleted")
+	assertCmd.Flags().StringSliceVar(&flagExpectNo, "expect-no", nil, "Fail on these kinds of change: creations, updates, deletions")
+
+	// Shares the sync command's flag variables apart from --source, so the same options apply
+	assertCmd.Flags().StringVar(&flagAssertSource, "source", "", "Source directory path")
+	assertCmd.Flags().StringVar(&flagDest, "dest", "", "Destination directory path")
+	assertCmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include .gitignore patterns from source")
+	assertCmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
+	assertCmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	assertCmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	assertCmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, includeGitUsage)
+	assertCmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	assertCmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
+	assertCmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	assertCmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
+	assertCmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Source-side ignore patterns")
+	assertCmd.Flags().StringSliceVar(&flagIgnoreDest, "ignore-dest", nil, "Destination-side ignore patterns")
+	assertCmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only check these paths")
+	assertCmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	assertCmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only check paths up to N levels below the source (0 for unlimited)")
+	assertCmd.Flags().StringVar(&flagRsyncBinary, "rsync-binary", "", "Path to the local rsync executable (default: rsync on PATH)")
+	assertCmd.Flags().StringVar(&flagLogLevel, "log-level", "", "Log level: DEBUG, INFO, WARNING, ERROR, CRITICAL")
+
+	assertCmd.RegisterFlagCompletionFunc("expect-no", cobra.FixedCompletions(validExpectNo, cobra.ShellCompDirectiveNoFileComp))
+	assertCmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(validLogLevels, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// validateAssertFlags requires something to assert and rejects unknown change kinds
+func validateAssertFlags(cmd *cobra.Command, args []string) error {
+	if !flagExpectNoChanges && len(flagExpectNo) == 0 {
+		return fmt.Errorf("nothing to assert; pass --expect-no-changes or --expect-no")
+	}
+	for _, name := range flagExpectNo {
+		if err := validateChoice("--expect-no", name, validExpectNo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runAssert(cmd *cobra.Command, args []string) error {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := mergeOptionsWithConfig(cfg, flagAssertSource)
+	if err := validateMergedOptions(opts); err != nil {
+		return err
+	}
+	if opts.Source == "" || opts.Dest == "" {
+		return fmt.Errorf("source and dest must be provided either via CLI or config file")
+	}
+
+	// Always a one-way dry run, even if the config asks for a preview or patch; only the
+	// violations and problems are worth logging
+	opts.Mode = "one-way"
+	opts.DryRun = true
+	opts.Preview = false
+	opts.Patch = ""
+	opts.SummaryOnly = true
+	if opts.LogLevel == "" && verbosity == 0 {
+		opts.LogLevel = "WARNING"
+	}
+
+	logger, err := logging.Setup(opts.LogLevel, opts.LogFile, opts.LogFormat, verbosity)
+	if err != nil {
+		return fmt.Errorf("error setting up logging: %w", err)
+	}
+
+	for _, path := range []*string{&opts.Source, &opts.Dest} {
+		if rsync.IsRemotePath(*path) {
+			continue
+		}
+		if *path, err = filepath.Abs(*path); err != nil {
+			return fmt.Errorf("error resolving path: %w", err)
+		}
+	}
+
+	forbidden := forbiddenChangeKinds()
+	var violations []rsync.Change
+
+	ctx, cancel := syncContext(0, "assert")
+	defer cancel()
+	runner := rsync.NewRunner(logger)
+	runner.OnChange(func(change rsync.Change) {
+		if slices.Contains(forbidden, change.Kind) {
+			violations = append(violations, change)
+		}
+	})
+	if err := runner.SyncContext(ctx, opts); err != nil {
+		return err
+	}
+
+	if len(violations) == 0 {
+		fmt.Printf("OK: no %s\n", describeKinds(forbidden))
+		return nil
+	}
+	for _, change := range violations {
+		fmt.Printf("%-8s %s\n", change.Kind, change.Path)
+	}
+	cmd.SilenceUsage = true
+	return fmt.Errorf("expected no %s, found %d", describeKinds(forbidden), len(violations))
+}
+
+// forbiddenChangeKinds returns the change kinds the flags assert against, in a fixed order
+func forbiddenChangeKinds() []rsync.ChangeKind {
+	var kinds []rsync.ChangeKind
+	for _, name := range validExpectNo {
+		if flagExpectNoChanges || slices.Contains(flagExpectNo, name) {
+			kinds = append(kinds, changeKindsByName[name])
+		}
+	}
+	return kinds
+}
+
+// describeKinds names the asserted kinds for messages, e.g. "created or deleted files"
+func describeKinds(kinds []rsync.ChangeKind) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	if len(names) > 2 {
+		return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1] + " files"
+	}
+	return strings.Join(names, " or ") + " files"
+}
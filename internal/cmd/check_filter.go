@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// checkFilterCmd explains the filter decision for individual paths
+var checkFilterCmd = &cobra.Command{
+	Use:   "check-filter <path>...",
+	Short: "Report whether paths would be synced and which filter rule decided",
+	Long: `Build the source filters the way a sync would and report, for each path
+relative to the source, whether it would be included or excluded and which rule
+decided it. Paths don't have to exist; a trailing slash marks a directory.
+
+The filter flags are the same as for sync, so hypothetical filter sets can be
+tried out without editing .syncignore or the config.
+
+Examples:
+  sync-tools check-filter --source ./project build/output.log
+  sync-tools check-filter --source ./project --only docs/ docs/index.md src/main.go
+  sync-tools check-filter --source ./project --ignore-src "*.tmp" notes.tmp cache/`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCheckFilter,
+}
+
+var flagCheckFilterSource string
+
+func init() {
+	rootCmd.AddCommand(checkFilterCmd)
+
+	// Shares the sync command's filter flag variables, so the same options apply; --source is
+	// its own, since sync's is repeatable
+	checkFilterCmd.Flags().StringVar(&flagCheckFilterSource, "source", "", "Source directory path")
+	checkFilterCmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include .gitignore patterns from source")
+	checkFilterCmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
+	checkFilterCmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	checkFilterCmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	checkFilterCmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, includeGitUsage)
+	checkFilterCmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	checkFilterCmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
+	checkFilterCmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	checkFilterCmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
+	checkFilterCmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Source-side ignore patterns")
+	checkFilterCmd.Flags().StringSliceVar(&flagIgnoreDest, "ignore-dest", nil, "Destination-side ignore patterns")
+	checkFilterCmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only sync these paths")
+	checkFilterCmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	checkFilterCmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only sync paths up to N levels below the source (0 for unlimited)")
+}
+
+func runCheckFilter(cmd *cobra.Command, args []string) error {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := mergeOptionsWithConfig(cfg, flagCheckFilterSource)
+	if err := validateMergedOptions(opts); err != nil {
+		return err
+	}
+
+	logger, err := logging.Setup(opts.LogLevel, opts.LogFile, opts.LogFormat, verbosity)
+	if err != nil {
+		return fmt.Errorf("error setting up logging: %w", err)
+	}
+
+	return runFilterTest(opts, logger, args...)
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// compareCmd reports how two directories differ without treating either as the source
+var compareCmd = &cobra.Command{
+	Use:   "compare <dir-a> <dir-b>",
+	Short: "Report how two directories differ, without implying a sync direction",
+	Long: `Compare two directories and report the paths found only in A, only in B,
+the files that differ (with both sides' size and modification time), and how many
+are identical. Unlike a dry run or --preview, neither side is treated as the
+source, which makes it suited to auditing two mirrors.
+
+Files match when their size and modification time do, as in rsync's quick check.
+The filter flags work as for sync and apply to both sides, and each side's own
+.syncignore is read.
+
+Examples:
+  sync-tools compare /mnt/backup-a /mnt/backup-b
+  sync-tools compare ./site ./mirror --exclude-vcs --format md > audit.md
+  sync-tools compare ./site ./mirror --format json`,
+	Args: cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateChoice("format", flagCompareFormat, validCompareFormats)
+	},
+	RunE: runCompare,
+}
+
+var (
+	validCompareFormats = []string{"text", "md", "json"}
+
+	flagCompareFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	// Shares the sync command's filter flag variables, so the same options apply
+	compareCmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include each side's .gitignore patterns")
+	compareCmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
+	compareCmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	compareCmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	compareCmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, "Compare the top-level .git directories, which are excluded by default")
+	compareCmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	compareCmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
+	compareCmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	compareCmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
+	compareCmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Ignore patterns, applied to both sides")
+	compareCmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only compare these paths")
+	compareCmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	compareCmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only compare paths up to N levels deep (0 for unlimited)")
+	compareCmd.Flags().BoolVar(&flagSizeOnly, "size-only", false, "Treat files of equal size as identical, whatever their modification times")
+	compareCmd.Flags().StringVar(&flagCompareFormat, "format", "text", "Output format: text, md, or json")
+
+	compareCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(validCompareFormats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := mergeOptionsWithConfig(cfg, "")
+	// Neither side is a destination, so dest-only ignores don't apply
+	opts.IgnoreDest = nil
+	if err := validateMergedOptions(opts); err != nil {
+		return err
+	}
+
+	logger, err := logging.Setup(opts.LogLevel, opts.LogFile, opts.LogFormat, verbosity)
+	if err != nil {
+		return fmt.Errorf("error setting up logging: %w", err)
+	}
+
+	dirA, dirB := args[0], args[1]
+	if !rsync.IsRemotePath(dirA) {
+		if dirA, err = filepath.Abs(dirA); err != nil {
+			return fmt.Errorf("error resolving path: %w", err)
+		}
+	}
+	if !rsync.IsRemotePath(dirB) {
+		if dirB, err = filepath.Abs(dirB); err != nil {
+			return fmt.Errorf("error resolving path: %w", err)
+		}
+	}
+
+	cmp, err := rsync.NewRunner(logger).CompareTrees(opts, dirA, dirB)
+	if err != nil {
+		return err
+	}
+
+	switch flagCompareFormat {
+	case "json":
+		report := struct {
+			A string `json:"a"`
+			B string `json:"b"`
+			rsync.Comparison
+		}{dirA, dirB, cmp}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding comparison: %w", err)
+		}
+		fmt.Println(string(data))
+	case "md":
+		fmt.Print(formatComparisonMarkdown(dirA, dirB, cmp))
+	default:
+		fmt.Print(formatComparisonText(dirA, dirB, cmp))
+	}
+	return nil
+}
+
+// formatComparisonText renders a comparison as indented plain text
+func formatComparisonText(dirA, dirB string, cmp rsync.Comparison) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "A: %s\nB: %s\n", dirA, dirB)
+	for _, section := range []struct {
+		title   string
+		entries []rsync.ListedEntry
+	}{{"Only in A", cmp.OnlyInA}, {"Only in B", cmp.OnlyInB}} {
+		fmt.Fprintf(&sb, "\n%s (%d):\n", section.title, len(section.entries))
+		for _, entry := range section.entries {
+			fmt.Fprintf(&sb, "  %s\n", comparedPath(entry.Path, entry.IsDir))
+		}
+	}
+	fmt.Fprintf(&sb, "\nDiffering (%d):\n", len(cmp.Differing))
+	for _, file := range cmp.Differing {
+		fmt.Fprintf(&sb, "  %s\n    A: %s\n    B: %s\n", file.Path, describeSide(file.A), describeSide(file.B))
+	}
+	fmt.Fprintf(&sb, "\nIdentical: %d files\n", cmp.Identical)
+	return sb.String()
+}
+
+// formatComparisonMarkdown renders a comparison as a Markdown report
+func formatComparisonMarkdown(dirA, dirB string, cmp rsync.Comparison) string {
+	var sb strings.Builder
+	sb.WriteString("# Directory Comparison\n\n")
+	fmt.Fprintf(&sb, "- **A:** `%s`\n- **B:** `%s`\n\n", dirA, dirB)
+	sb.WriteString("| Only in A | Only in B | Differing | Identical |\n")
+	sb.WriteString("|-----------|-----------|-----------|-----------|\n")
+	fmt.Fprintf(&sb, "| %d | %d | %d | %d |\n", len(cmp.OnlyInA), len(cmp.OnlyInB), len(cmp.Differing), cmp.Identical)
+
+	for _, section := range []struct {
+		title   string
+		entries []rsync.ListedEntry
+	}{{"Only in A", cmp.OnlyInA}, {"Only in B", cmp.OnlyInB}} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\n", section.title)
+		for _, entry := range section.entries {
+			fmt.Fprintf(&sb, "- `%s`\n", comparedPath(entry.Path, entry.IsDir))
+		}
+	}
+	if len(cmp.Differing) > 0 {
+		sb.WriteString("\n## Differing\n\n")
+		sb.WriteString("| Path | A | B |\n")
+		sb.WriteString("|------|---|---|\n")
+		for _, file := range cmp.Differing {
+			fmt.Fprintf(&sb, "| `%s` | %s | %s |\n", file.Path, describeSide(file.A), describeSide(file.B))
+		}
+	}
+	return sb.String()
+}
+
+// comparedPath marks directories with a trailing slash
+func comparedPath(relPath string, isDir bool) string {
+	if isDir {
+		return relPath + "/"
+	}
+	return relPath
+}
+
+// describeSide summarizes one side of a differing file, e.g. "1.5 KiB, 2025-09-01 14:33:00"
+func describeSide(side rsync.ComparedSide) string {
+	modified := side.ModTime.Local().Format(time.DateTime)
+	if side.IsDir {
+		return "directory, " + modified
+	}
+	return formatSize(side.Size) + ", " + modified
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd checks that the tools sync-tools relies on are installed and usable
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment for the tools sync-tools needs",
+	Long: `Check for rsync and its version, git, less, a writable temp directory, and the
+configured editor, reporting each as PASS, WARN, or FAIL.
+
+rsync and the temp directory are required, so doctor exits with status 1 when
+either fails. Warnings point at optional tools whose absence disables or degrades
+a feature. Include the output when reporting a problem.
+
+Examples:
+  sync-tools doctor
+  sync-tools doctor --rsync-binary /opt/homebrew/bin/rsync`,
+	RunE: runDoctor,
+}
+
+// minRsyncMajor and minRsyncMinor are the oldest rsync with --info=progress2
+const (
+	minRsyncMajor = 3
+	minRsyncMinor = 1
+)
+
+// checkStatus is the outcome of one doctor check
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN"
+	checkFail checkStatus = "FAIL"
+)
+
+// checkResult is one line of the doctor report
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	// Shares the sync command's flag, so the same binary is checked that a sync would run
+	doctorCmd.Flags().StringVar(&flagRsyncBinary, "rsync-binary", "", "Path to the local rsync executable to check (default: rsync on PATH)")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+	opts := mergeOptionsWithConfig(cfg, "")
+
+	results := []checkResult{
+		checkRsync(opts),
+		checkTool("git", "previews and patches use the built-in diff, and --apply-patch falls back to patch"),
+		checkTool("less", "previews are printed without a pager"),
+		checkTempDir(),
+		checkEditor(),
+	}
+
+	failed := 0
+	for _, result := range results {
+		fmt.Printf("[%s] %-8s %s\n", result.status, result.name, result.detail)
+		if result.status == checkFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("doctor found %d failing checks", failed)
+	}
+	return nil
+}
+
+// checkRsync requires the configured rsync and warns when it predates --info=progress2
+func checkRsync(opts *rsync.Options) checkResult {
+	result := checkResult{name: "rsync"}
+	if err := rsync.CheckRsync(opts); err != nil {
+		result.status, result.detail = checkFail, err.Error()
+		return result
+	}
+
+	path, _ := exec.LookPath(rsyncBinaryName(opts))
+	version, err := rsync.RsyncVersion(opts)
+	if err != nil {
+		result.status, result.detail = checkWarn, fmt.Sprintf("%s (%v)", path, err)
+		return result
+	}
+	if !version.AtLeast(minRsyncMajor, minRsyncMinor) {
+		result.status = checkWarn
+		result.detail = fmt.Sprintf("%s is version %s; %d.%d or newer is needed for --info=progress2",
+			path, version, minRsyncMajor, minRsyncMinor)
+		return result
+	}
+	result.status, result.detail = checkPass, fmt.Sprintf("%s (version %s)", path, version)
+	return result
+}
+
+// rsyncBinaryName is the rsync a sync would run: --rsync-binary or rsync on PATH
+func rsyncBinaryName(opts *rsync.Options) string {
+	if opts.RsyncBinary != "" {
+		return opts.RsyncBinary
+	}
+	return "rsync"
+}
+
+// checkTool warns when an optional tool is missing, explaining what degrades without it
+func checkTool(name, without string) checkResult {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return checkResult{name: name, status: checkWarn, detail: "not found on PATH; " + without}
+	}
+	return checkResult{name: name, status: checkPass, detail: path}
+}
+
+// checkTempDir requires a writable temp directory, where filter and file-list files are written
+func checkTempDir() checkResult {
+	result := checkResult{name: "tmpdir"}
+	file, err := os.CreateTemp("", "sync-tools-doctor-*")
+	if err != nil {
+		result.status, result.detail = checkFail, fmt.Sprintf("%s is not writable: %v", os.TempDir(), err)
+		return result
+	}
+	file.Close()
+	os.Remove(file.Name())
+	result.status, result.detail = checkPass, os.TempDir()+" is writable"
+	return result
+}
+
+// checkEditor warns when $VISUAL or $EDITOR is unset or names a program that isn't installed
+func checkEditor() checkResult {
+	result := checkResult{name: "editor"}
+	variable, editor := "VISUAL", os.Getenv("VISUAL")
+	if editor == "" {
+		variable, editor = "EDITOR", os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		result.status, result.detail = checkWarn, "neither $VISUAL nor $EDITOR is set"
+		return result
+	}
+
+	// The variable may carry arguments, e.g. "code --wait"
+	program := strings.Fields(editor)[0]
+	path, err := exec.LookPath(program)
+	if err != nil {
+		result.status, result.detail = checkWarn, fmt.Sprintf("$%s is %q, which is not found on PATH", variable, editor)
+		return result
+	}
+	result.status, result.detail = checkPass, fmt.Sprintf("$%s resolves to %s", variable, path)
+	return result
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/notify"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+const notifyUsage = "When done, post a JSON summary to this webhook URL, or pass 'desktop' for a desktop notification"
+
+// runSyncHook runs --on-success or --on-failure, whichever matches the sync's outcome.
+// Hook failures are logged rather than returned, so they never mask the sync's result.
+func runSyncHook(opts *rsync.Options, stats rsync.SyncStats, syncErr error, logger logging.Logger) {
+	name, command := "on-success", flagOnSuccess
+	if syncErr != nil {
+		name, command = "on-failure", flagOnFailure
+	}
+	if command == "" {
+		return
+	}
+
+	logger.Infof("Running --%s hook: %s", name, command)
+	hook := shellCommand(command)
+	hook.Env = append(os.Environ(), hookEnv(opts, stats, syncErr)...)
+	hook.Stdout = os.Stdout
+	hook.Stderr = os.Stderr
+	if err := hook.Run(); err != nil {
+		logger.Errorf("--%s hook failed: %v", name, err)
+	}
+}
+
+// shellCommand runs command through the platform shell, so hooks can use pipes and quoting
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// hookEnv describes the finished sync to a hook as SYNC_* environment variables
+func hookEnv(opts *rsync.Options, stats rsync.SyncStats, syncErr error) []string {
+	exit, message := 0, ""
+	if syncErr != nil {
+		exit, message = 1, syncErr.Error()
+	}
+	return []string{
+		"SYNC_SOURCE=" + opts.Source,
+		"SYNC_DEST=" + opts.Dest,
+		"SYNC_MODE=" + opts.Mode,
+		"SYNC_DRY_RUN=" + strconv.FormatBool(!opts.WritesDest()),
+		"SYNC_EXIT=" + strconv.Itoa(exit),
+		"SYNC_ERROR=" + message,
+		"SYNC_FILES_CREATED=" + strconv.Itoa(stats.FilesCreated),
+		"SYNC_FILES_UPDATED=" + strconv.Itoa(stats.FilesUpdated),
+		"SYNC_FILES_DELETED=" + strconv.Itoa(stats.FilesDeleted),
+		"SYNC_CONFLICTS=" + strconv.Itoa(stats.Conflicts),
+		"SYNC_BYTES_TRANSFERRED=" + strconv.FormatInt(stats.BytesTransferred, 10),
+	}
+}
+
+// sendNotification reports the finished runs to --notify's target, if one was given.
+// Like hooks, a failed notification is logged without affecting the exit status.
+func sendNotification(target, subject string, runs []rsync.StatsRecord, start time.Time, runErr error, logger logging.Logger) {
+	if target == "" {
+		return
+	}
+	if err := notify.Send(target, notify.New(subject, runs, start, runErr)); err != nil {
+		logger.Warnf("Could not send notification: %v", err)
+		return
+	}
+	logger.Debugf("Sent completion notification to %s", target)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// listCmd shows the source as the filters leave it
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the source files that survive the filters",
+	Long: `List the files and directories in the source that a sync would transfer,
+after .syncignore, --only, and the other filters are applied. It is the positive
+counterpart of --list-filtered and needs no destination, which makes it handy for
+tuning filters before a dry run.
+
+Examples:
+  sync-tools list --source ./project
+  sync-tools list --source ./project --only docs/ --format flat
+  sync-tools list --source ./project --max-depth 2 --format json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateChoice("format", flagListFormat, validListFormats)
+	},
+	RunE: runList,
+}
+
+var (
+	validListFormats = []string{"tree", "flat", "json"}
+
+	flagListSource string
+	flagListFormat string
+)
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+
+	// Shares the sync command's filter flag variables, so the same options apply; --source is
+	// its own, since sync's is repeatable
+	listCmd.Flags().StringVar(&flagListSource, "source", "", "Source directory path")
+	listCmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include .gitignore patterns from source")
+	listCmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
+	listCmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	listCmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	listCmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, includeGitUsage)
+	listCmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	listCmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
+	listCmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	listCmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
+	listCmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Source-side ignore patterns")
+	listCmd.Flags().StringSliceVar(&flagIgnoreDest, "ignore-dest", nil, "Destination-side ignore patterns")
+	listCmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only list these paths")
+	listCmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	listCmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only list paths up to N levels below the source (0 for unlimited)")
+	listCmd.Flags().StringVar(&flagListFormat, "format", "tree", "Output format: tree, flat, or json")
+
+	listCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(validListFormats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	opts := mergeOptionsWithConfig(cfg, flagListSource)
+	if opts.Source == "" {
+		return fmt.Errorf("source must be provided either via CLI or config file")
+	}
+	sourcePath, err := filepath.Abs(opts.Source)
+	if err != nil {
+		return fmt.Errorf("error resolving source path: %w", err)
+	}
+	if info, err := os.Stat(sourcePath); err != nil || !info.IsDir() {
+		return fmt.Errorf("%w: %s", rsync.ErrSourceNotFound, opts.Source)
+	}
+	opts.Source = sourcePath
+
+	logger, err := logging.Setup(opts.LogLevel, opts.LogFile, opts.LogFormat, verbosity)
+	if err != nil {
+		return fmt.Errorf("error setting up logging: %w", err)
+	}
+
+	entries, err := rsync.NewRunner(logger).ListIncluded(opts)
+	if err != nil {
+		return fmt.Errorf("error listing source: %w", err)
+	}
+
+	switch flagListFormat {
+	case "json":
+		if entries == nil {
+			entries = []rsync.ListedEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding listing: %w", err)
+		}
+		fmt.Println(string(data))
+	case "flat":
+		for _, entry := range entries {
+			if entry.IsDir {
+				fmt.Printf("%10s  %s/\n", "-", entry.Path)
+			} else {
+				fmt.Printf("%10s  %s\n", formatSize(entry.Size), entry.Path)
+			}
+		}
+	default:
+		printTree(filepath.Base(sourcePath), entries)
+	}
+	return nil
+}
+
+// listNode is a directory or file in the rendered tree
+type listNode struct {
+	name     string
+	isDir    bool
+	size     int64
+	files    int
+	children []*listNode
+}
+
+// printTree renders the listing as an indented tree with file sizes and per-directory totals
+func printTree(rootName string, entries []rsync.ListedEntry) {
+	root := &listNode{name: rootName, isDir: true}
+	dirs := map[string]*listNode{".": root}
+
+	// WalkDir lists parents before their children, so each parent already exists
+	for _, entry := range entries {
+		node := &listNode{name: path.Base(entry.Path), isDir: entry.IsDir, size: entry.Size}
+		parent := dirs[path.Dir(entry.Path)]
+		parent.children = append(parent.children, node)
+		if entry.IsDir {
+			dirs[entry.Path] = node
+			continue
+		}
+		// Roll the file into every ancestor's totals
+		for dir := path.Dir(entry.Path); ; dir = path.Dir(dir) {
+			dirs[dir].size += entry.Size
+			dirs[dir].files++
+			if dir == "." {
+				break
+			}
+		}
+	}
+
+	fmt.Printf("%s/ (%d files, %s)\n", root.name, root.files, formatSize(root.size))
+	printTreeChildren(root, "")
+}
+
+// printTreeChildren prints a directory's children with box-drawing branches
+func printTreeChildren(dir *listNode, prefix string) {
+	for i, child := range dir.children {
+		branch, indent := "├── ", "│   "
+		if i == len(dir.children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if child.isDir {
+			fmt.Printf("%s%s%s/ (%d files, %s)\n", prefix, branch, child.name, child.files, formatSize(child.size))
+			printTreeChildren(child, prefix+indent)
+		} else {
+			fmt.Printf("%s%s%s (%s)\n", prefix, branch, child.name, formatSize(child.size))
+		}
+	}
+}
+
+// formatSize renders a byte count with a binary unit, e.g. 1.5 KiB
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -1,15 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"os"
+	"time"
 
+	"github.com/DamianReeves/sync-tools/internal/filters"
 	"github.com/spf13/cobra"
 )
 
-var (
-	version = "0.2.0" // Incremented from Python version
-)
-
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "sync-tools",
@@ -22,19 +21,44 @@ var rootCmd = &cobra.Command{
 • Per-side ignore files and inline patterns (with ! unignore)
 • "Whitelist" mode to sync only specified paths
 • Optional config file (sync.conf) OR pure CLI usage
-• Default exclusion of .git/
+• Default exclusion of .git/ (--include-git to sync it)
 • Optional exclusion of all hidden directories (dot-dirs)
+• Optional exclusion of VCS metadata and editor/OS junk (--exclude-vcs)
 • Dry-run previews and detailed change output
 • Interactive sync mode with Bubble Tea UI
 • Custom SyncFile format (Dockerfile-like syntax)`,
 	Version: version,
 }
 
+// staleTempFileAge is how old a leftover temp filter file must be before it is swept
+const staleTempFileAge = time.Hour
+
+// sweepStaleTempFiles clears filter files left in $TMPDIR by runs that crashed before
+// cleaning up. Only the commands that sync call it, so --help and read-only commands never
+// delete anything; files of a sync-tools process that is still running are kept.
+func sweepStaleTempFiles() {
+	filters.SweepStaleFiles(staleTempFileAge)
+}
+
+// exitError is a command failure that exits with its own status rather than 1, so scripts
+// can tell kinds of failure apart
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -43,4 +67,6 @@ func init() {
 	// Global flags can be added here
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Path to a TOML config file to load default options")
 	rootCmd.PersistentFlags().CountP("verbose", "v", "Verbose output (use -v, -vv, etc.)")
+	rootCmd.PersistentFlags().String("config-dir", "", "Directory holding the global config.toml (default ~/.config/sync-tools)")
+	rootCmd.PersistentFlags().Bool("no-global-config", false, "Don't load the global config file")
 }
\ No newline at end of file
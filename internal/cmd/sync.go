@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/DamianReeves/sync-tools/internal/config"
 	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/notify"
 	"github.com/DamianReeves/sync-tools/internal/rsync"
 	"github.com/DamianReeves/sync-tools/pkg/tui"
 	"github.com/spf13/cobra"
@@ -24,19 +32,35 @@ You can specify defaults in a TOML config and override them on the command line.
 Examples:
   sync-tools sync --source ./project --dest ./backup --dry-run
   sync-tools sync --config sync.toml --mode two-way
-  sync-tools sync --source ./src --dest ./dst --only docs/ --report report.md`,
-	RunE: runSync,
+  sync-tools sync --source ./src --dest ./dst --only docs/ --report report.md
+  sync-tools sync --source ~/docs --source ~/photos --dest /mnt/backup`,
+	PreRunE: validateSyncFlags,
+	RunE:    runSync,
 }
 
+// Accepted values for the enumerated sync flags, used for both completion and validation
+var (
+	validModes         = []string{"one-way", "two-way"}
+	validLogLevels     = []string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"}
+	validLogFormats    = []string{"text", "json"}
+	validListFiltered  = []string{"src", "dst", "both"}
+)
+
 // Sync command flags
 var (
-	flagSource           string
+	flagSources          []string
 	flagDest             string
 	flagMode             string
 	flagDryRun           bool
 	flagUseSourceGitignore bool
+	flagUseGlobalGitignore bool
 	flagExcludeHiddenDirs bool
+	flagExcludeVCS        bool
+	flagIncludeGit        bool
+	flagExcludeIfPresent  []string
+	flagFilterRules       []string
 	flagOnlySyncignore    bool
+	flagNoNestedSyncignore bool
 	flagIgnoreSrc         []string
 	flagIgnoreDest        []string
 	flagOnly              []string
@@ -44,60 +68,453 @@ var (
 	flagLogFile           string
 	flagLogFormat         string
 	flagDumpCommands      string
+	flagDumpFilters       string
 	flagReport            string
 	flagListFiltered      string
 	flagInteractive       bool
+	flagAutoStart         bool
 	flagPatch             string
 	flagApplyPatch        bool
 	flagYes               bool
 	flagPreview           bool
+	flagPreviewFormat     string
+	flagFilesFrom         string
+	flagOneFileSystem     bool
+	flagRelative          bool
+	flagPruneEmptyDirs    bool
+	flagSuper             bool
+	flagFakeSuper         bool
+	flagDestOwnership     bool
+	flagExpectedOwner     string
+	flagLinks             string
+	flagRetryFiles        int
+	flagIgnoreErrors      bool
+	flagPropagateDeletes  bool
+	flagInteractiveConflicts bool
+	flagConflictSuffix    string
+	flagFilterTest        string
+	flagEstimate          bool
+	flagSummaryBadge      bool
+	flagStatsJSONAppend   string
+	flagForce             bool
+	flagIgnoreStale       bool
+	flagCleanStale        bool
+	flagExpectDestClean   bool
+	flagWholeFile         bool
+	flagNoWholeFile       bool
+	flagInplace           bool
+	flagDelayUpdates      bool
+	flagSizeOnly          bool
+	flagDedupe            bool
+	flagIncludeUnchanged  bool
+	flagPartial           bool
+	flagPartialDir        string
+	flagIgnoreTimes       bool
+	flagNoArchive         bool
+	flagMaxDepth          int
+	flagIgnoreCase        bool
+	flagSparse            bool
+	flagChecksumChoice    string
+	flagExecute           bool
+	flagRsyncBinary       string
+	flagRsyncPath         string
+	flagPasswordFile      string
+	flagRsyncLogFile      string
+	flagRsyncLogFormat    string
+	flagRsyncExtraArgs    string
+	flagTimeout           time.Duration
+	flagOnSuccess         string
+	flagOnFailure         string
+	flagNotify            string
+	flagStats             bool
+	flagProgress          bool
+	flagFailOnChanges     bool
+	flagSummaryOnly       bool
+	flagRepeat            bool
 )
 
 func init() {
 	rootCmd.AddCommand(syncCmd)
 
 	// Required flags
-	syncCmd.Flags().StringVar(&flagSource, "source", "", "Source directory path")
+	syncCmd.Flags().StringArrayVar(&flagSources, "source", nil, "Source directory path; repeat to sync several sources, each into its own directory under the dest")
 	syncCmd.Flags().StringVar(&flagDest, "dest", "", "Destination directory path")
 
 	// Mode flags
+	syncCmd.Flags().BoolVar(&flagRepeat, "repeat", false, "Reuse the source, dest, and filter options of the last successful sync; other flags override them")
 	syncCmd.Flags().StringVar(&flagMode, "mode", "one-way", "Sync mode: one-way or two-way")
+	syncCmd.Flags().BoolVar(&flagPropagateDeletes, "propagate-deletes", false, propagateDeletesUsage)
+	syncCmd.Flags().BoolVar(&flagInteractiveConflicts, "interactive-conflicts", false, interactiveConflictsUsage)
+	syncCmd.Flags().StringVar(&flagConflictSuffix, "conflict-suffix", "", conflictSuffixUsage)
 	syncCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show what would be synced without making changes")
+	syncCmd.Flags().BoolVar(&flagFailOnChanges, "fail-on-changes", false, "With --dry-run, exit with status 1 if any file would change (for CI drift checks)")
+	syncCmd.Flags().BoolVar(&flagExecute, "execute", false, "Apply changes when safe_mode is enabled in the config (otherwise syncs run as dry runs)")
 	syncCmd.Flags().BoolVar(&flagInteractive, "interactive", false, "Use interactive Bubble Tea interface")
+	syncCmd.Flags().BoolVar(&flagAutoStart, "auto-start", false, "With --interactive, start syncing immediately and exit shortly after it finishes")
+	syncCmd.Flags().IntVar(&flagRetryFiles, "retry-files", 0, "Retry files that failed to transfer up to N more times")
+	syncCmd.Flags().BoolVar(&flagIgnoreErrors, "ignore-errors", false, ignoreErrorsUsage)
+	syncCmd.Flags().StringVar(&flagLinks, "links", "preserve", "Symlink handling: preserve, copy (follow links), safe (skip links leaving the tree), or munge")
+	syncCmd.Flags().DurationVar(&flagTimeout, "timeout", 0, timeoutUsage)
+	syncCmd.Flags().BoolVarP(&flagOneFileSystem, "one-file-system", "x", false, "Don't cross filesystem boundaries (skip mounted volumes)")
+	syncCmd.Flags().BoolVarP(&flagRelative, "relative", "R", false, "Recreate the source path under the dest (/a/b/c lands at dest/a/b/c); a /./ in the source marks where the kept path starts")
+
+	// rsync program flags
+	syncCmd.Flags().StringVar(&flagRsyncBinary, "rsync-binary", "", "Path to the local rsync executable (default: rsync on PATH)")
+	syncCmd.Flags().StringVar(&flagRsyncPath, "rsync-path", "", "rsync program to run on the remote host for SSH targets")
+	syncCmd.Flags().StringVar(&flagPasswordFile, "password-file", "", "File holding the password for rsync daemon (rsync://) targets")
+	syncCmd.Flags().StringVar(&flagRsyncLogFile, "rsync-log-file", "", rsyncLogFileUsage)
+	syncCmd.Flags().StringVar(&flagRsyncLogFormat, "rsync-log-format", "", rsyncLogFormatUsage)
+	syncCmd.Flags().StringVar(&flagRsyncExtraArgs, "rsync-extra-args", "", rsyncExtraArgsUsage)
+
+	// Performance tuning flags
+	syncCmd.Flags().BoolVar(&flagWholeFile, "whole-file", false, "Copy whole files without rsync's delta algorithm (default for local syncs)")
+	syncCmd.Flags().BoolVar(&flagNoWholeFile, "no-whole-file", false, "Always use rsync's delta algorithm, even for local syncs")
+	syncCmd.Flags().BoolVar(&flagInplace, "inplace", false, "Update dest files in place instead of writing a temp copy and renaming")
+	syncCmd.Flags().BoolVar(&flagSparse, "sparse", false, "Turn runs of zeros into sparse blocks in the dest")
+	syncCmd.Flags().StringVar(&flagChecksumChoice, "checksum-choice", "", checksumChoiceUsage)
+	syncCmd.Flags().BoolVar(&flagDelayUpdates, "delay-updates", false, "Stage updated files and move them into place together at the end of the transfer")
+	syncCmd.Flags().BoolVar(&flagPartial, "partial", false, partialUsage)
+	syncCmd.Flags().StringVar(&flagPartialDir, "partial-dir", "", partialDirUsage)
+	syncCmd.Flags().BoolVar(&flagSizeOnly, "size-only", false, sizeOnlyUsage)
+	syncCmd.Flags().BoolVar(&flagDedupe, "dedupe", false, dedupeUsage)
+	syncCmd.Flags().BoolVar(&flagIncludeUnchanged, "include-unchanged", false, includeUnchangedUsage)
+	syncCmd.Flags().BoolVar(&flagIgnoreTimes, "ignore-times", false, ignoreTimesUsage)
+	syncCmd.Flags().BoolVar(&flagNoArchive, "no-archive", false, noArchiveUsage)
 
 	// Filter flags
 	syncCmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include .gitignore patterns from source")
+	syncCmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
 	syncCmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	syncCmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	syncCmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, includeGitUsage)
+	syncCmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	syncCmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
 	syncCmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	syncCmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
 	syncCmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Source-side ignore patterns")
 	syncCmd.Flags().StringSliceVar(&flagIgnoreDest, "ignore-dest", nil, "Destination-side ignore patterns")
 	syncCmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only sync these paths")
+	syncCmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	syncCmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only sync paths up to N levels below the source (0 for unlimited); deeper dest content is left alone")
+	syncCmd.Flags().BoolVar(&flagPruneEmptyDirs, "prune-empty-dirs", false, pruneEmptyDirsUsage)
+	syncCmd.Flags().BoolVar(&flagSuper, "super", false, superUsage)
+	syncCmd.Flags().BoolVar(&flagFakeSuper, "fake-super", false, fakeSuperUsage)
+	syncCmd.Flags().StringVar(&flagFilesFrom, "files-from", "", "Sync only the paths listed in this file (use - for stdin); bypasses .syncignore and --only filters")
 
 	// Output flags
 	syncCmd.Flags().StringVar(&flagLogLevel, "log-level", "", "Log level: DEBUG, INFO, WARNING, ERROR, CRITICAL")
-	syncCmd.Flags().StringVar(&flagLogFile, "log-file", "", "Path to write logs")
+	syncCmd.Flags().StringVar(&flagLogFile, "log-file", "", "Path to write sync-tools' own logs (see --rsync-log-file for rsync's transfer log)")
 	syncCmd.Flags().StringVar(&flagLogFormat, "log-format", "text", "Log format: text or json")
 	syncCmd.Flags().StringVar(&flagDumpCommands, "dump-commands", "", "Write rsync command and filters to JSON file")
+	syncCmd.Flags().StringVar(&flagDumpFilters, "dump-filters", "", dumpFiltersUsage)
+	syncCmd.Flags().Lookup("dump-filters").NoOptDefVal = "-"
 	syncCmd.Flags().StringVar(&flagReport, "report", "", "Write a sync report to this path (format detected from extension: .md/.markdown for markdown, .patch for patch)")
+	syncCmd.Flags().BoolVar(&flagEstimate, "estimate", false, "Print how many files and bytes a one-way sync would transfer, without syncing or running rsync (local paths only)")
+	syncCmd.Flags().StringVar(&flagFilterTest, "filter-test", "", "Report whether this source-relative path would be included or excluded, and by which rule, without syncing")
+	syncCmd.Flags().BoolVar(&flagSummaryOnly, "summary-only", false, "Print only a one-line summary of changes, bytes, and duration (warnings and errors are still logged; for cron jobs)")
+	syncCmd.Flags().BoolVar(&flagStats, "stats", false, "Include bytes transferred and throughput (MB/s) in the completion log line")
+	syncCmd.Flags().BoolVar(&flagProgress, "progress", false, progressUsage)
+	syncCmd.Flags().BoolVar(&flagSummaryBadge, "report-summary-badge", false, "Print a one-line SYNC_SUMMARY with change counts to stdout (for CI logs)")
+	syncCmd.Flags().StringVar(&flagStatsJSONAppend, "stats-json-append", "", "Append this run's stats as a JSON line to this file (time-series log)")
+	syncCmd.Flags().StringVar(&flagOnSuccess, "on-success", "", "Shell command to run after a successful sync (SYNC_* environment variables describe the run)")
+	syncCmd.Flags().StringVar(&flagOnFailure, "on-failure", "", "Shell command to run after a failed sync (SYNC_* environment variables describe the run)")
+	syncCmd.Flags().StringVar(&flagNotify, "notify", "", notifyUsage)
 	syncCmd.Flags().StringVar(&flagListFiltered, "list-filtered", "", "List items that would be filtered: src, dst, or both")
 	syncCmd.Flags().StringVar(&flagPatch, "patch", "", "Generate git patch file instead of syncing")
 	syncCmd.Flags().BoolVar(&flagApplyPatch, "apply-patch", false, "Apply the generated patch after creation (with confirmation)")
 	syncCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Automatically confirm patch application (skip confirmation prompt)")
+	syncCmd.Flags().BoolVar(&flagForce, "force", false, "Skip safety checks, such as refusing to mirror an empty source over a populated dest")
+	syncCmd.Flags().BoolVar(&flagIgnoreStale, "ignore-stale-artifacts", false, "Don't scan the dest for leftovers (conflict copies, partial dirs, temp files) from earlier runs")
+	syncCmd.Flags().BoolVar(&flagCleanStale, "clean-stale-artifacts", false, cleanStaleUsage)
+	syncCmd.Flags().BoolVar(&flagExpectDestClean, "expect-dest-clean", false, expectDestCleanUsage)
 	syncCmd.Flags().BoolVar(&flagPreview, "preview", false, "Show a colored diff preview of changes (with paging)")
+	syncCmd.Flags().StringVar(&flagPreviewFormat, "preview-format", "", previewFormatUsage)
+	syncCmd.Flags().BoolVar(&flagDestOwnership, "dest-ownership-report", false, "After syncing, report dest files whose owner differs from the source (or --expected-owner)")
+	syncCmd.Flags().StringVar(&flagExpectedOwner, "expected-owner", "", "Expected uid:gid for every dest file in the ownership report")
+
+	// Shell completion for enumerated flags
+	syncCmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions(validModes, cobra.ShellCompDirectiveNoFileComp))
+	syncCmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(validLogLevels, cobra.ShellCompDirectiveNoFileComp))
+	syncCmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(validLogFormats, cobra.ShellCompDirectiveNoFileComp))
+	syncCmd.RegisterFlagCompletionFunc("list-filtered", cobra.FixedCompletions(validListFiltered, cobra.ShellCompDirectiveNoFileComp))
+	syncCmd.RegisterFlagCompletionFunc("checksum-choice", cobra.FixedCompletions(rsync.ChecksumChoices, cobra.ShellCompDirectiveNoFileComp))
+	syncCmd.RegisterFlagCompletionFunc("preview-format", cobra.FixedCompletions(rsync.PreviewFormats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// validateSyncFlags rejects unknown values for enumerated flags before any config or path work
+func validateSyncFlags(cmd *cobra.Command, args []string) error {
+	checks := []struct {
+		flag  string
+		value string
+		valid []string
+	}{
+		{"mode", flagMode, validModes},
+		{"log-level", flagLogLevel, validLogLevels},
+		{"log-format", flagLogFormat, validLogFormats},
+		{"list-filtered", flagListFiltered, validListFiltered},
+		{"checksum-choice", flagChecksumChoice, rsync.ChecksumChoices},
+		{"preview-format", flagPreviewFormat, rsync.PreviewFormats},
+	}
+
+	for _, check := range checks {
+		if !cmd.Flags().Changed(check.flag) {
+			continue
+		}
+		if err := validateChoice("--"+check.flag, check.value, check.valid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMergedOptions checks enumerated values once config defaults have been applied,
+// so typos in either source fail before logging, path resolution, or rsync setup
+func validateMergedOptions(opts *rsync.Options) error {
+	if err := validateChoice("mode", opts.Mode, validModes); err != nil {
+		return err
+	}
+	if opts.LogFormat != "" {
+		if err := validateChoice("log format", opts.LogFormat, validLogFormats); err != nil {
+			return err
+		}
+	}
+	if opts.ListFiltered != "" {
+		if err := validateChoice("list-filtered value", opts.ListFiltered, validListFiltered); err != nil {
+			return err
+		}
+	}
+	if opts.ChecksumChoice != "" {
+		if err := validateChoice("checksum choice", opts.ChecksumChoice, rsync.ChecksumChoices); err != nil {
+			return err
+		}
+	}
+	if opts.PreviewFormat != "" {
+		if err := validateChoice("preview format", opts.PreviewFormat, rsync.PreviewFormats); err != nil {
+			return err
+		}
+	}
+	if _, err := rsync.ParseConflictSuffix(opts.ConflictSuffix); err != nil {
+		return err
+	}
+	if opts.PropagateDeletes && opts.Mode != "two-way" {
+		return fmt.Errorf("--propagate-deletes only applies to --mode two-way")
+	}
+	if opts.InteractiveConflicts && opts.Mode != "two-way" {
+		return fmt.Errorf("--interactive-conflicts only applies to --mode two-way")
+	}
+	if opts.ExcludeVCS && opts.IncludeGit {
+		return fmt.Errorf("--include-git conflicts with --exclude-vcs, which excludes .git directories")
+	}
+	return nil
+}
+
+// isNestedPath reports whether path lies strictly inside parent; both must be absolute
+func isNestedPath(parent, path string) bool {
+	rel, err := filepath.Rel(parent, path)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// validateSyncTargets checks local source and dest before syncing. Because sync always mirrors
+// with --delete, an empty source over a populated dest would wipe it, so that is refused unless
+// force is set; a missing dest is only warned about since rsync creates it.
+func validateSyncTargets(opts *rsync.Options, logger logging.Logger, force bool) error {
+	var sourceEmpty bool
+	if !rsync.IsRemotePath(opts.Source) {
+		info, err := os.Stat(opts.Source)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", rsync.ErrSourceNotFound, opts.Source)
+		}
+		if err == nil && info.IsDir() {
+			entries, err := os.ReadDir(opts.Source)
+			if err != nil {
+				return fmt.Errorf("error reading source directory: %w", err)
+			}
+			sourceEmpty = len(entries) == 0
+		}
+	}
+
+	if rsync.IsRemotePath(opts.Dest) {
+		return nil
+	}
+
+	// Mirroring a directory into itself would recurse, and mirroring into a parent
+	// would delete the source along with everything else not in it
+	if !rsync.IsRemotePath(opts.Source) {
+		if opts.Dest == opts.Source {
+			return fmt.Errorf("cannot sync %s into itself", opts.Source)
+		}
+		if isNestedPath(opts.Source, opts.Dest) && !force {
+			return fmt.Errorf("dest %s is inside source %s and would be synced into itself; pass --force if it is excluded by your filters",
+				opts.Dest, opts.Source)
+		}
+		if isNestedPath(opts.Dest, opts.Source) && !force {
+			return fmt.Errorf("source %s is inside dest %s and would be deleted by the sync; pass --force if your dest filters protect it",
+				opts.Source, opts.Dest)
+		}
+	}
+
+	destEntries, err := os.ReadDir(opts.Dest)
+	if os.IsNotExist(err) {
+		logger.Warnf("Destination directory does not exist and will be created: %s", opts.Dest)
+		return nil
+	}
+	if err != nil {
+		// Not a directory or unreadable; let rsync report the problem
+		return nil
+	}
+
+	if sourceEmpty && len(destEntries) > 0 && opts.WritesDest() && opts.FilesFrom == "" {
+		if !force {
+			return fmt.Errorf("source %s is empty and syncing would delete all %d entries in %s; use --dry-run to inspect or pass --yes/--force to proceed",
+				opts.Source, len(destEntries), opts.Dest)
+		}
+		logger.Warnf("Source is empty; removing all %d entries in %s (forced)", len(destEntries), opts.Dest)
+	}
+	return nil
+}
+
+// checkStaleArtifacts reports leftovers from interrupted or conflicted runs in a local dest.
+// Before a real sync, leftovers of an interrupted run must be cleaned up or the sync aborts;
+// --clean-stale-artifacts also removes conflict and redo copies. rsync temp files are only
+// recognized by name, so they are reported and never removed.
+func checkStaleArtifacts(opts *rsync.Options, logger logging.Logger) error {
+	if rsync.IsRemotePath(opts.Dest) {
+		return nil
+	}
+	if _, err := os.Stat(opts.Dest); os.IsNotExist(err) {
+		return nil
+	}
+	// A sync that would fail anyway leaves the dest as it is, and reports the failure itself
+	if opts.WritesDest() && rsync.Preflight(opts) != nil {
+		return nil
+	}
+
+	artifacts, err := rsync.FindStaleArtifacts(opts)
+	if err != nil {
+		return fmt.Errorf("error scanning dest for stale artifacts: %w", err)
+	}
+	// A resumable sync keeps its partial dir on purpose, to pick up where it stopped
+	if opts.PartialDir != "" {
+		artifacts = slices.DeleteFunc(artifacts, func(artifact rsync.StaleArtifact) bool {
+			return filepath.Base(artifact.Path) == filepath.Base(opts.PartialDir)
+		})
+	}
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	for _, artifact := range artifacts {
+		logger.Warnf("Stale artifact from a previous run: %s (%s)", artifact.Path, artifact.Kind)
+	}
+	if !opts.WritesDest() {
+		return nil
+	}
+
+	// Only an interrupted run's leftovers stand in the way of a new sync; conflict and redo
+	// copies are kept unless --clean-stale-artifacts asks for them to go too
+	artifacts = slices.DeleteFunc(artifacts, func(artifact rsync.StaleArtifact) bool {
+		return !artifact.Removable || !(artifact.Interrupted || flagCleanStale)
+	})
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	if !flagCleanStale && !confirmStaleCleanup(len(artifacts)) {
+		return fmt.Errorf("found %d leftovers of an interrupted run in %s; remove them, re-run with --clean-stale-artifacts to clean them up, or pass --ignore-stale-artifacts",
+			len(artifacts), opts.Dest)
+	}
+	if err := rsync.RemoveStaleArtifacts(opts.Dest, artifacts); err != nil {
+		return fmt.Errorf("error removing stale artifacts: %w", err)
+	}
+	logger.Infof("Removed %d stale artifacts", len(artifacts))
+	return nil
+}
+
+// stdoutIsTerminal reports whether stdout is a terminal, where a status line can be redrawn in place
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmStaleCleanup asks whether to clean up stale artifacts, answering no when stdin isn't a terminal
+func confirmStaleCleanup(count int) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	fmt.Printf("\nRemove %d stale artifacts before syncing? Answering no aborts the sync. [y/N]: ", count)
+
+	var response string
+	if _, err := fmt.Scanln(&response); err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+// validateChoice returns an error listing the valid values when value isn't one of them
+func validateChoice(name, value string, valid []string) error {
+	if slices.Contains(valid, value) {
+		return nil
+	}
+	return fmt.Errorf("invalid %s: %s (must be one of: %s)", name, value, strings.Join(valid, ", "))
+}
+
+// loadConfig loads the global and project configs selected by the root command's flags
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	configDir, _ := cmd.Flags().GetString("config-dir")
+	noGlobalConfig, _ := cmd.Flags().GetBool("no-global-config")
+
+	cfg, err := config.LoadLayeredConfig(configPath, configDir, !noGlobalConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	return cfg, nil
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
+	sweepStaleTempFiles()
+
 	// Load configuration
-	configPath, _ := cmd.Flags().GetString("config")
 	verbosity, _ := cmd.Flags().GetCount("verbose")
-	
-	cfg, err := config.LoadConfig(configPath)
+	cfg, err := loadConfig(cmd)
 	if err != nil {
-		return fmt.Errorf("error loading config: %w", err)
+		return err
+	}
+
+	// The last run takes the place of the config file's options, and flags still win
+	configDir, _ := cmd.Flags().GetString("config-dir")
+	if flagRepeat {
+		lastRun, err := config.LoadLastRun(configDir)
+		if err != nil {
+			return err
+		}
+		lastRun.ApplyTo(cfg)
 	}
 
 	// Merge CLI flags with config
-	opts := mergeOptionsWithConfig(cfg)
+	var source string
+	if len(flagSources) > 0 {
+		source = flagSources[0]
+	}
+	opts := mergeOptionsWithConfig(cfg, source)
+	if err := validateMergedOptions(opts); err != nil {
+		return err
+	}
+	if opts.RsyncExtraArgs, err = rsyncExtraArgs(cfg); err != nil {
+		return err
+	}
+
+	// The summary replaces the progress log, so only warnings and errors get through
+	// unless a log level or -v was asked for
+	if opts.SummaryOnly && opts.LogLevel == "" && verbosity == 0 {
+		opts.LogLevel = "WARNING"
+	}
 
 	// Setup logging
 	logger, err := logging.Setup(opts.LogLevel, opts.LogFile, opts.LogFormat, verbosity)
@@ -108,62 +525,236 @@ func runSync(cmd *cobra.Command, args []string) error {
 	logger.Debugf("CLI options after merge: source=%s dest=%s mode=%s dry-run=%v", 
 		opts.Source, opts.Dest, opts.Mode, opts.DryRun)
 
+	// Filter testing only inspects the source, so it doesn't need a dest
+	if flagFilterTest != "" {
+		return runFilterTest(opts, logger, flagFilterTest)
+	}
+
 	// Validate required options
 	if opts.Source == "" || opts.Dest == "" {
 		return fmt.Errorf("source and dest must be provided either via CLI or config file")
 	}
 
-	// Resolve paths
-	sourcePath, err := filepath.Abs(opts.Source)
-	if err != nil {
-		return fmt.Errorf("error resolving source path: %w", err)
+	// An explicit file list and whitelist mode would silently fight each other
+	if opts.FilesFrom != "" && len(opts.Only) > 0 {
+		return fmt.Errorf("--files-from cannot be combined with --only; list the whitelisted paths in the file instead")
+	}
+	if err := validateTimeout("--timeout", flagTimeout); err != nil {
+		return err
+	}
+	if err := notify.Validate(flagNotify); err != nil {
+		return err
+	}
+	if err := validateFailOnChanges(opts); err != nil {
+		return err
+	}
+	if opts.SummaryOnly && (opts.Interactive || opts.Preview || opts.Patch != "") {
+		return fmt.Errorf("--summary-only cannot be combined with --interactive, --preview, or --patch")
+	}
+	if flagEstimate && (opts.Mode != "one-way" || opts.FilesFrom != "" || opts.Relative || opts.Interactive) {
+		return fmt.Errorf("--estimate only supports one-way syncs without --files-from, --relative, or --interactive")
+	}
+	if err := validateExpectDestClean(opts); err != nil {
+		return err
+	}
+	if flagAutoStart && !opts.Interactive {
+		return fmt.Errorf("--auto-start requires --interactive")
+	}
+	if opts.FilesFrom == "-" && opts.Interactive {
+		return fmt.Errorf("--files-from - reads stdin and cannot be used with --interactive")
 	}
 
-	destPath, err := filepath.Abs(opts.Dest)
-	if err != nil {
-		return fmt.Errorf("error resolving dest path: %w", err)
+	if len(flagSources) > 1 {
+		return runSourcesSync(cmd, opts, flagSources, logger)
 	}
 
-	opts.Source = sourcePath
-	opts.Dest = destPath
+	// Resolve local paths; remote host:path specs are passed to rsync unchanged
+	if !rsync.IsRemotePath(opts.Source) {
+		sourcePath, err := filepath.Abs(opts.Source)
+		if err != nil {
+			return fmt.Errorf("error resolving source path: %w", err)
+		}
+		// Resolving would lose which part of a relative source --relative keeps
+		if opts.Relative {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("error resolving source path: %w", err)
+			}
+			sourcePath = rsync.RelativeSource(opts.Source, cwd)
+		}
+		opts.Source = sourcePath
+	}
+
+	if !rsync.IsRemotePath(opts.Dest) {
+		destPath, err := filepath.Abs(opts.Dest)
+		if err != nil {
+			return fmt.Errorf("error resolving dest path: %w", err)
+		}
+		opts.Dest = destPath
+	}
 
-	// Check if source exists
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("source directory does not exist: %s", sourcePath)
+	// Estimates only read the trees, so they skip the safety and stale-artifact checks
+	if flagEstimate {
+		return runEstimate(opts, logger)
+	}
+
+	if err := validateSyncTargets(opts, logger, opts.Yes || flagForce); err != nil {
+		return err
+	}
+
+	// Checked before stale artifacts are cleaned up, which would itself change the dest
+	if flagExpectDestClean {
+		if err := checkDestClean(configDir, opts, logger); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+	}
+
+	if !flagIgnoreStale {
+		if err := checkStaleArtifacts(opts, logger); err != nil {
+			return err
+		}
 	}
 
-	// Check if using interactive mode
+	// Run the sync in interactive or traditional CLI mode
+	start := time.Now()
+	var stats rsync.SyncStats
 	if opts.Interactive {
-		return runInteractiveSync(opts, logger)
+		stats, err = runInteractiveSync(opts, logger)
+	} else {
+		stats, err = runTraditionalSync(opts, logger)
 	}
 
-	// Run traditional CLI sync
-	return runTraditionalSync(opts, logger)
+	err = checkFailOnChanges(cmd, stats, err, logger)
+	if err == nil {
+		recordLastRun(configDir, opts, logger)
+		if flagExpectDestClean {
+			recordDestManifest(configDir, opts, logger)
+		}
+	}
+
+	finishSync(opts, stats, []rsync.StatsRecord{rsync.NewStatsRecord(opts, stats, start, err)}, start, err, logger)
+	return err
+}
+
+// checkFailOnChanges turns a successful sync with pending changes into a failure under
+// --fail-on-changes, so hooks and notifications report it as one
+func checkFailOnChanges(cmd *cobra.Command, stats rsync.SyncStats, err error, logger logging.Logger) error {
+	if err != nil || !flagFailOnChanges {
+		return err
+	}
+	err = checkPendingChanges(stats, logger)
+	if err != nil {
+		cmd.SilenceUsage = true
+	}
+	return err
 }
 
-func mergeOptionsWithConfig(cfg *config.Config) *rsync.Options {
+// finishSync runs the --on-success/--on-failure hook and sends the notification for a
+// finished sync. A failing hook is logged, but the sync's own result decides the exit status.
+func finishSync(opts *rsync.Options, stats rsync.SyncStats, runs []rsync.StatsRecord, start time.Time, err error, logger logging.Logger) {
+	runSyncHook(opts, stats, err, logger)
+	sendNotification(flagNotify, opts.Source+" -> "+opts.Dest, runs, start, err, logger)
+}
+
+// recordLastRun saves the sync's paths and filter options for --repeat. It only warns on
+// failure, since the sync itself has already succeeded.
+func recordLastRun(configDir string, opts *rsync.Options, logger logging.Logger) {
+	lastRun := config.LastRun{
+		Source:             opts.Source,
+		Dest:               opts.Dest,
+		Mode:               opts.Mode,
+		UseSourceGitignore: opts.UseSourceGitignore,
+		UseGlobalGitignore: opts.UseGlobalGitignore,
+		ExcludeHiddenDirs:  opts.ExcludeHiddenDirs,
+		ExcludeVCS:         opts.ExcludeVCS,
+		IncludeGit:         opts.IncludeGit,
+		OnlySyncignore:     opts.OnlySyncignore,
+		NoNestedSyncignore: opts.NoNestedSyncignore,
+		IgnoreSrc:          opts.IgnoreSrc,
+		IgnoreDest:         opts.IgnoreDest,
+		Only:               opts.Only,
+		RsyncBinary:        opts.RsyncBinary,
+		RsyncPath:          opts.RsyncPath,
+		PasswordFile:       opts.PasswordFile,
+	}
+	if err := config.SaveLastRun(configDir, lastRun); err != nil {
+		logger.Warnf("Could not record this sync for --repeat: %v", err)
+	}
+}
+
+// mergeOptionsWithConfig builds sync options from the shared flag variables and source,
+// the command's own --source value, falling back to cfg for anything not given
+func mergeOptionsWithConfig(cfg *config.Config, source string) *rsync.Options {
 	opts := &rsync.Options{
-		Source:              flagSource,
+		Source:              source,
 		Dest:                flagDest,
 		Mode:                flagMode,
 		DryRun:              flagDryRun,
 		UseSourceGitignore:  flagUseSourceGitignore,
+		UseGlobalGitignore:  flagUseGlobalGitignore,
 		ExcludeHiddenDirs:   flagExcludeHiddenDirs,
+		ExcludeVCS:          flagExcludeVCS,
+		IncludeGit:          flagIncludeGit,
+		ExcludeIfPresent:    flagExcludeIfPresent,
+		FilterRules:         flagFilterRules,
 		OnlySyncignore:      flagOnlySyncignore,
-		IgnoreSrc:           flagIgnoreSrc,
-		IgnoreDest:          flagIgnoreDest,
-		Only:                flagOnly,
+		NoNestedSyncignore:  flagNoNestedSyncignore,
+		IgnoreSrc:           joinBraceSplits(flagIgnoreSrc),
+		IgnoreDest:          joinBraceSplits(flagIgnoreDest),
+		Only:                joinBraceSplits(flagOnly),
 		LogLevel:            flagLogLevel,
 		LogFile:             flagLogFile,
 		LogFormat:           flagLogFormat,
 		DumpCommands:        flagDumpCommands,
+		DumpFilters:         flagDumpFilters,
 		Report:              flagReport,
 		ListFiltered:        flagListFiltered,
 		Interactive:         flagInteractive,
 		Patch:               flagPatch,
 		ApplyPatch:          flagApplyPatch,
 		Yes:                 flagYes,
-		Preview:             flagPreview,
+		// Picking a preview format asks for a preview
+		Preview:             flagPreview || flagPreviewFormat != "",
+		PreviewFormat:       flagPreviewFormat,
+		FilesFrom:           flagFilesFrom,
+		OneFileSystem:       flagOneFileSystem,
+		Relative:            flagRelative,
+		PruneEmptyDirs:      flagPruneEmptyDirs,
+		Super:               flagSuper,
+		FakeSuper:           flagFakeSuper,
+		DestOwnershipReport: flagDestOwnership,
+		ExpectedOwner:       flagExpectedOwner,
+		Links:               flagLinks,
+		RetryFiles:          flagRetryFiles,
+		IgnoreErrors:        flagIgnoreErrors,
+		PropagateDeletes:    flagPropagateDeletes,
+		InteractiveConflicts: flagInteractiveConflicts,
+		ConflictSuffix:      flagConflictSuffix,
+		WholeFile:           flagWholeFile,
+		NoWholeFile:         flagNoWholeFile,
+		Inplace:             flagInplace,
+		DelayUpdates:        flagDelayUpdates,
+		SizeOnly:            flagSizeOnly,
+		Dedupe:              flagDedupe,
+		NoArchive:           flagNoArchive,
+		IncludeUnchanged:    flagIncludeUnchanged,
+		Partial:             flagPartial,
+		PartialDir:          flagPartialDir,
+		IgnoreTimes:         flagIgnoreTimes,
+		MaxDepth:            flagMaxDepth,
+		IgnoreCase:          flagIgnoreCase,
+		Sparse:              flagSparse,
+		ChecksumChoice:      flagChecksumChoice,
+		Execute:             flagExecute,
+		RsyncBinary:         flagRsyncBinary,
+		RsyncPath:           flagRsyncPath,
+		PasswordFile:        flagPasswordFile,
+		RsyncLogFile:        flagRsyncLogFile,
+		RsyncLogFormat:      flagRsyncLogFormat,
+		ShowThroughput:      flagStats,
+		Progress:            flagProgress,
+		SummaryOnly:         flagSummaryOnly,
 	}
 
 	// Merge with config values (config provides defaults)
@@ -174,6 +765,18 @@ func mergeOptionsWithConfig(cfg *config.Config) *rsync.Options {
 		if opts.Dest == "" && cfg.Dest != "" {
 			opts.Dest = cfg.Dest
 		}
+		if opts.LogLevel == "" && cfg.LogLevel != "" {
+			opts.LogLevel = cfg.LogLevel
+		}
+		if opts.LogFile == "" && cfg.LogFile != "" {
+			opts.LogFile = cfg.LogFile
+		}
+		if opts.LogFormat == "text" && cfg.LogFormat != "" {
+			opts.LogFormat = cfg.LogFormat
+		}
+		if opts.Report == "" && cfg.Report != "" {
+			opts.Report = cfg.Report
+		}
 		if opts.Mode == "one-way" && cfg.Mode != "" {
 			opts.Mode = cfg.Mode
 		}
@@ -183,12 +786,30 @@ func mergeOptionsWithConfig(cfg *config.Config) *rsync.Options {
 		if !opts.UseSourceGitignore && cfg.UseSourceGitignore {
 			opts.UseSourceGitignore = cfg.UseSourceGitignore
 		}
+		if !opts.UseGlobalGitignore && cfg.UseGlobalGitignore {
+			opts.UseGlobalGitignore = cfg.UseGlobalGitignore
+		}
 		if !opts.ExcludeHiddenDirs && cfg.ExcludeHiddenDirs {
 			opts.ExcludeHiddenDirs = cfg.ExcludeHiddenDirs
 		}
+		if !opts.ExcludeVCS && cfg.ExcludeVCS {
+			opts.ExcludeVCS = cfg.ExcludeVCS
+		}
+		if !opts.IncludeGit && cfg.IncludeGit {
+			opts.IncludeGit = cfg.IncludeGit
+		}
+		if len(opts.ExcludeIfPresent) == 0 && len(cfg.ExcludeIfPresent) > 0 {
+			opts.ExcludeIfPresent = cfg.ExcludeIfPresent
+		}
+		if len(opts.FilterRules) == 0 && len(cfg.FilterRules) > 0 {
+			opts.FilterRules = cfg.FilterRules
+		}
 		if !opts.OnlySyncignore && cfg.OnlySyncignore {
 			opts.OnlySyncignore = cfg.OnlySyncignore
 		}
+		if !opts.NoNestedSyncignore && cfg.NoNestedSyncignore {
+			opts.NoNestedSyncignore = cfg.NoNestedSyncignore
+		}
 		if len(opts.IgnoreSrc) == 0 && len(cfg.IgnoreSrc) > 0 {
 			opts.IgnoreSrc = cfg.IgnoreSrc
 		}
@@ -198,30 +819,348 @@ func mergeOptionsWithConfig(cfg *config.Config) *rsync.Options {
 		if len(opts.Only) == 0 && len(cfg.Only) > 0 {
 			opts.Only = cfg.Only
 		}
+		if opts.RsyncBinary == "" && cfg.RsyncBinary != "" {
+			opts.RsyncBinary = cfg.RsyncBinary
+		}
+		if opts.RsyncPath == "" && cfg.RsyncPath != "" {
+			opts.RsyncPath = cfg.RsyncPath
+		}
+		if opts.PasswordFile == "" && cfg.PasswordFile != "" {
+			opts.PasswordFile = cfg.PasswordFile
+		}
+		if opts.RsyncLogFile == "" && cfg.RsyncLogFile != "" {
+			opts.RsyncLogFile = cfg.RsyncLogFile
+		}
+		if opts.RsyncLogFormat == "" && cfg.RsyncLogFormat != "" {
+			opts.RsyncLogFormat = cfg.RsyncLogFormat
+		}
+		if opts.ConflictSuffix == "" && cfg.ConflictSuffix != "" {
+			opts.ConflictSuffix = cfg.ConflictSuffix
+		}
+		if !opts.Partial && cfg.Partial {
+			opts.Partial = cfg.Partial
+		}
+		if opts.PartialDir == "" && cfg.PartialDir != "" {
+			opts.PartialDir = cfg.PartialDir
+		}
+		if !opts.SizeOnly && cfg.SizeOnly {
+			opts.SizeOnly = cfg.SizeOnly
+		}
+		if !opts.Dedupe && cfg.Dedupe {
+			opts.Dedupe = cfg.Dedupe
+		}
+		if !opts.IncludeUnchanged && cfg.IncludeUnchanged {
+			opts.IncludeUnchanged = cfg.IncludeUnchanged
+		}
+		if !opts.IgnoreTimes && cfg.IgnoreTimes {
+			opts.IgnoreTimes = cfg.IgnoreTimes
+		}
+		if !opts.NoArchive && cfg.NoArchive {
+			opts.NoArchive = cfg.NoArchive
+		}
+		if !opts.PruneEmptyDirs && cfg.PruneEmptyDirs {
+			opts.PruneEmptyDirs = cfg.PruneEmptyDirs
+		}
+		if !opts.Super && cfg.Super {
+			opts.Super = cfg.Super
+		}
+		if !opts.FakeSuper && cfg.FakeSuper {
+			opts.FakeSuper = cfg.FakeSuper
+		}
+		if !opts.Relative && cfg.Relative {
+			opts.Relative = cfg.Relative
+		}
+		if !opts.IgnoreErrors && cfg.IgnoreErrors {
+			opts.IgnoreErrors = cfg.IgnoreErrors
+		}
+		if opts.ChecksumChoice == "" && cfg.ChecksumChoice != "" {
+			opts.ChecksumChoice = cfg.ChecksumChoice
+		}
+		if opts.Patch == "" && cfg.Patch != "" {
+			opts.Patch = cfg.Patch
+		}
+		if !opts.ApplyPatch && cfg.ApplyPatch {
+			opts.ApplyPatch = cfg.ApplyPatch
+		}
+		if !opts.Preview && cfg.Preview {
+			opts.Preview = cfg.Preview
+		}
+		if opts.PreviewFormat == "" && cfg.PreviewFormat != "" {
+			opts.PreviewFormat = cfg.PreviewFormat
+		}
+		opts.SafeMode = cfg.SafeMode
 	}
 
 	return opts
 }
 
-func runInteractiveSync(opts *rsync.Options, logger logging.Logger) error {
+// runFilterTest prints whether each source-relative path would be transferred and which rule decided it
+func runFilterTest(opts *rsync.Options, logger logging.Logger, relPaths ...string) error {
+	if opts.Source == "" {
+		return fmt.Errorf("source must be provided either via CLI or config file")
+	}
+
+	sourcePath, err := filepath.Abs(opts.Source)
+	if err != nil {
+		return fmt.Errorf("error resolving source path: %w", err)
+	}
+	opts.Source = sourcePath
+
+	runner := rsync.NewRunner(logger)
+	for _, relPath := range relPaths {
+		decision, err := runner.CheckFilter(opts, relPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", relPath, decision)
+	}
+	return nil
+}
+
+// rsyncExtraArgs splits --rsync-extra-args, or the config's rsync_extra_args when the flag isn't set
+func rsyncExtraArgs(cfg *config.Config) ([]string, error) {
+	extra := flagRsyncExtraArgs
+	if extra == "" && cfg != nil {
+		extra = cfg.RsyncExtraArgs
+	}
+	args, err := rsync.SplitArgs(extra)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --rsync-extra-args: %w", err)
+	}
+	return args, nil
+}
+
+// runEstimate prints the projected transfer volume of a one-way sync without syncing
+func runEstimate(opts *rsync.Options, logger logging.Logger) error {
+	if info, err := os.Stat(opts.Source); err != nil || !info.IsDir() {
+		return fmt.Errorf("%w: %s", rsync.ErrSourceNotFound, opts.Source)
+	}
+
+	est, err := rsync.NewRunner(logger).EstimateSync(opts)
+	if err != nil {
+		return fmt.Errorf("error estimating sync: %w", err)
+	}
+	fmt.Printf("Estimate: will transfer ~%s files, %s (%s created, %s updated); %s deleted\n",
+		formatCount(est.Transfers()), formatSize(est.TotalSize),
+		formatCount(est.FilesCreated), formatCount(est.FilesUpdated), formatCount(est.FilesDeleted))
+	return nil
+}
+
+// formatCount renders n with thousands separators, e.g. 1,240
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+const (
+	propagateDeletesUsage = "In two-way mode, delete source files missing from the dest instead of copying them (asks first unless --yes)"
+	interactiveConflictsUsage = "In two-way mode, ask for each conflicting file whether to keep the source, dest, or both versions, or skip it (--yes keeps both)"
+	superUsage            = "Have the receiver set ownership and other privileged attributes even when not run as root (rsync --super)"
+	fakeSuperUsage        = "Store ownership, permissions, and devices in dest xattrs so a non-root backup can be restored faithfully as root (needs xattr support on the dest)"
+	pruneEmptyDirsUsage   = "Don't create dest directories that the filters leave empty (e.g. with --only); directories empty in a local source are still synced"
+	conflictSuffixUsage   = "Name conflict copies from a template of {name}, {ext}, {side}, and {time:format} (a Go time layout or unix), e.g. \"{name}.{time:2006-01-02_1504}{ext}.bak\" (default \"" + rsync.DefaultConflictSuffix + "\")"
+	ignoreErrorsUsage     = "Finish the sync when some source files can't be read (e.g. permission denied), listing them as skipped instead of failing"
+	previewFormatUsage    = "Preview format: git (colored git diff, the default), unified, side-by-side, or stat (a diffstat); all but git work without git. Implies --preview"
+	useGlobalGitignoreUsage = "Include the patterns of git's global gitignore (core.excludesFile, or ~/.config/git/ignore)"
+	checksumChoiceUsage   = "rsync checksum algorithm, e.g. xxh128 for fast large local syncs (needs rsync 3.2+; default: rsync decides)"
+	rsyncExtraArgsUsage   = "Extra rsync options appended before the source and dest, e.g. \"--chmod=D755 --bwlimit=1000\" (quotes are honored; overriding flags sync-tools sets, like --delete, can break the sync)"
+	excludeVCSUsage       = "Exclude version control metadata (.git, .svn, .hg, .bzr, CVS, ...) and editor/OS junk files (*~, *.swp, .DS_Store, ...)"
+	includeGitUsage       = "Sync the top-level .git directory, which is excluded by default"
+	rsyncLogFileUsage     = "Have rsync append its own transfer log to this path (rsync --log-file); unlike --log-file, which holds sync-tools' application log"
+	rsyncLogFormatUsage   = "Per-file format for --rsync-log-file, using rsync's %-escapes, e.g. \"%i %n%L\" (rsync --log-file-format)"
+	partialUsage          = "Keep partially transferred files so an interrupted sync resumes them (rsync --partial)"
+	partialDirUsage       = "Keep partial files in this directory inside each dest directory until they finish (rsync --partial-dir; implies --partial)"
+	dedupeUsage           = "After copying, hard-link each new or updated file to an identical file already in the dest, storing duplicate content once (local one-way syncs; not with --inplace)"
+	includeUnchangedUsage = "Also list files that are already up to date, as \"unchanged\", in dry-run output and markdown reports, and count them in summaries"
+	sizeOnlyUsage         = "Skip files whose size matches the dest, even if their mtimes differ (rsync --size-only)"
+	ignoreTimesUsage      = "Update every file, even those matching the dest in size and mtime (rsync --ignore-times)"
+	noArchiveUsage        = "Run rsync with --recursive instead of --archive, keeping permissions, times, and owners only if --rsync-extra-args asks for them"
+	progressUsage         = "Show transfer progress with an ETA during the run and the average rate at the end (redrawn in place on a terminal, logged every 10s otherwise)"
+	dumpFiltersUsage      = "Before syncing, write the rsync filter rules, each run commented with the setting or file it came from, to this path (--dump-filters=PATH; stderr without one)"
+	cleanStaleUsage       = "Before a real sync, remove leftovers of interrupted runs and conflict and redo copies from the dest without asking (rsync temp files are only reported)"
+	filterRuleUsage       = "Raw rsync filter rule, e.g. \"- *.tmp\" or \": .rsync-filter\" (repeatable; added after the generated rules, so they only decide paths no other rule matched)"
+	excludeIfPresentUsage = "Skip any source directory holding a file with this name, e.g. .nobackup (repeatable; local sources only)"
+	noNestedSyncignoreUsage = "Only read the source root's .syncignore; by default a .syncignore in any subdirectory also applies to that subdirectory"
+	expectDestCleanUsage  = "Refuse to sync if the dest changed since the last sync with this flag, e.g. a hotfix made directly on a mirror (one-way, local dest; the first run records the dest)"
+)
+
+const timeoutUsage = "Stop the sync, including any running rsync, if it takes longer than this (e.g. 30m; 0 for no limit)"
+
+// validateTimeout rejects negative durations, which would fail every sync immediately
+func validateTimeout(flag string, timeout time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("invalid %s: %s (must be 0 for no limit, or positive)", flag, timeout)
+	}
+	return nil
+}
+
+// validateFailOnChanges makes sure --fail-on-changes has rsync dry-run counts to check:
+// a real sync would apply the changes, and preview and patch modes don't count them
+func validateFailOnChanges(opts *rsync.Options) error {
+	if !flagFailOnChanges {
+		return nil
+	}
+	if opts.Preview || opts.Patch != "" || rsync.IsPatchReport(opts.Report) {
+		return fmt.Errorf("--fail-on-changes cannot be combined with --preview or --patch")
+	}
+	if opts.WritesDest() {
+		return fmt.Errorf("--fail-on-changes requires --dry-run")
+	}
+	return nil
+}
+
+// checkPendingChanges logs the dry run's change count and fails when there is anything to sync
+func checkPendingChanges(stats rsync.SyncStats, logger logging.Logger) error {
+	if stats.Changes() == 0 {
+		logger.Info("No pending changes: destination is in sync")
+		return nil
+	}
+	logger.Errorf("%d pending changes (files: %d created, %d updated, %d deleted; directories: %d created, %d deleted)",
+		stats.Changes(), stats.FilesCreated, stats.FilesUpdated, stats.FilesDeleted, stats.DirsCreated, stats.DirsDeleted)
+	return fmt.Errorf("destination has %d pending changes", stats.Changes())
+}
+
+// joinBraceSplits undoes the comma splitting of pattern list flags inside braces, so
+// --only "{a,b}/*.txt" arrives as one pattern for brace expansion rather than "{a" and "b}/*.txt"
+func joinBraceSplits(values []string) []string {
+	var joined []string
+	depth := 0
+	for _, value := range values {
+		if depth > 0 {
+			joined[len(joined)-1] += "," + value
+		} else {
+			joined = append(joined, value)
+		}
+		depth += strings.Count(value, "{") - strings.Count(value, "}")
+		depth = max(depth, 0)
+	}
+	return joined
+}
+
+// formatSummary renders the --summary-only line, e.g.
+// "Sync summary: 3 created, 1 updated, 0 deleted, 12.5 KiB transferred in 1.234s"
+func formatSummary(stats *rsync.SyncStats, dryRun bool) string {
+	label := "Sync summary"
+	if dryRun {
+		label = "Dry run summary"
+	}
+	return label + ": " + describeStats(stats, dryRun)
+}
+
+// describeStats lists a run's change counts, the bytes it moved, and how long it took,
+// e.g. "3 created, 1 updated, 0 deleted, 1.2 KiB transferred in 15ms"
+func describeStats(stats *rsync.SyncStats, dryRun bool) string {
+	transferred := "transferred"
+	if dryRun {
+		transferred = "to transfer"
+	}
+	summary := fmt.Sprintf("%d created, %d updated, %d deleted",
+		stats.FilesCreated, stats.FilesUpdated, stats.FilesDeleted)
+	if stats.Conflicts > 0 {
+		summary += fmt.Sprintf(", %d conflicts", stats.Conflicts)
+	}
+	if len(stats.Skipped) > 0 {
+		summary += fmt.Sprintf(", %d skipped", len(stats.Skipped))
+	}
+	if stats.FilesUnchanged > 0 {
+		summary += fmt.Sprintf(", %d unchanged", stats.FilesUnchanged)
+	}
+	return fmt.Sprintf("%s, %s %s in %s", summary, formatSize(stats.BytesTransferred), transferred,
+		rsync.FormatDuration(stats.Duration))
+}
+
+// syncContext returns the context a sync runs under. It is cancelled by Ctrl+C or SIGTERM,
+// and after timeout when positive, so rsync is always stopped along with sync-tools.
+func syncContext(timeout time.Duration, what string) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := withTimeout(ctx, timeout, what)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// withTimeout bounds ctx by timeout when it is positive. The cancellation cause names
+// what timed out, which ends up in the sync's error.
+func withTimeout(ctx context.Context, timeout time.Duration, what string) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%s timed out after %s", what, timeout))
+}
+
+func runInteractiveSync(opts *rsync.Options, logger logging.Logger) (rsync.SyncStats, error) {
+	ctx, cancel := syncContext(flagTimeout, "sync")
+	defer cancel()
+
 	// Create the Bubble Tea model
-	model := tui.NewModel(opts, logger)
+	cfg := tui.Config{AutoStart: flagAutoStart}
+	if flagAutoStart {
+		cfg.AutoQuitDelay = autoQuitDelay
+	}
+	model := tui.NewModel(ctx, opts, logger, cfg)
 
 	// Create the program
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
 	// Run the program
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("error running interactive sync: %w", err)
+	final, err := p.Run()
+
+	// Quitting mid-sync cancels it; wait for rsync to stop rather than orphaning it
+	model.Stop()
+	if err != nil {
+		return rsync.SyncStats{}, fmt.Errorf("error running interactive sync: %w", err)
 	}
 
-	return nil
+	// Surface sync failures in the exit status, so wrapper scripts can tell
+	if m, ok := final.(tui.Model); ok {
+		return m.Stats(), m.Err()
+	}
+	return rsync.SyncStats{}, nil
 }
 
-func runTraditionalSync(opts *rsync.Options, logger logging.Logger) error {
+// autoQuitDelay is how long --auto-start leaves the result on screen before exiting
+const autoQuitDelay = 2 * time.Second
+
+func runTraditionalSync(opts *rsync.Options, logger logging.Logger) (rsync.SyncStats, error) {
+	ctx, cancel := syncContext(flagTimeout, "sync")
+	defer cancel()
+
 	// Create rsync runner
 	runner := rsync.NewRunner(logger)
+	runner.ShowDryRun(os.Stdout)
+	runner.ShowProgress(os.Stdout, stdoutIsTerminal())
 
 	// Execute sync
-	return runner.Sync(opts)
+	start := time.Now()
+	err := runner.SyncContext(ctx, opts)
+
+	printSyncSummary(opts, runner.Stats())
+	appendStatsRecord(rsync.NewStatsRecord(opts, runner.Stats(), start, err), logger)
+	return runner.Stats(), err
+}
+
+// printSyncSummary prints the --report-summary-badge and --summary-only lines. Both go to
+// stdout even when the sync fails, so CI logs always show how far it got.
+func printSyncSummary(opts *rsync.Options, stats rsync.SyncStats) {
+	if flagSummaryBadge {
+		fmt.Println(stats.Badge())
+	}
+	if opts.SummaryOnly {
+		fmt.Println(formatSummary(&stats, opts.DryRun))
+	}
+}
+
+// appendStatsRecord writes record to the --stats-json-append log, if one was given. A failing
+// stats log shouldn't turn a successful sync into a failure.
+func appendStatsRecord(record rsync.StatsRecord, logger logging.Logger) {
+	if flagStatsJSONAppend == "" {
+		return
+	}
+	if err := rsync.AppendStatsRecord(flagStatsJSONAppend, record); err != nil {
+		logger.Warnf("Could not append stats log: %v", err)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/config"
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// validateExpectDestClean rejects --expect-dest-clean where a dest manifest can't be kept or
+// where dest changes are expected
+func validateExpectDestClean(opts *rsync.Options) error {
+	if !flagExpectDestClean {
+		return nil
+	}
+	if rsync.IsRemotePath(opts.Dest) {
+		return fmt.Errorf("--expect-dest-clean needs a local dest")
+	}
+	if opts.Mode != "one-way" {
+		return fmt.Errorf("--expect-dest-clean only applies to one-way syncs; %s syncs carry dest changes back", opts.Mode)
+	}
+	return nil
+}
+
+// checkDestClean refuses to sync when the dest has changed since the manifest recorded by the
+// previous --expect-dest-clean sync, so edits made directly on a mirror aren't overwritten.
+// Without a manifest there is nothing to check yet; the sync records one.
+func checkDestClean(configDir string, opts *rsync.Options, logger logging.Logger) error {
+	path, err := config.DestManifestPath(configDir, opts.Dest)
+	if err != nil {
+		return err
+	}
+	manifest, err := rsync.LoadDestManifest(path)
+	if err != nil {
+		return err
+	}
+	if manifest == nil {
+		logger.Warnf("No manifest of %s from an earlier sync; a successful sync records one for --expect-dest-clean to check next time", opts.Dest)
+		return nil
+	}
+	recorded := manifest.Recorded.Format(time.RFC3339)
+	if _, err := os.Stat(opts.Dest); os.IsNotExist(err) {
+		return fmt.Errorf("dest %s was removed since the last sync on %s", opts.Dest, recorded)
+	}
+
+	cmp, err := rsync.NewRunner(logger).CheckDestManifest(opts, manifest)
+	if err != nil {
+		return fmt.Errorf("error checking dest against its manifest: %w", err)
+	}
+	for _, entry := range cmp.OnlyInA {
+		logger.Warnf("Changed on the dest since the last sync: %s (removed)", entry.Path)
+	}
+	for _, entry := range cmp.OnlyInB {
+		logger.Warnf("Changed on the dest since the last sync: %s (added)", entry.Path)
+	}
+	for _, file := range cmp.Differing {
+		logger.Warnf("Changed on the dest since the last sync: %s (modified)", file.Path)
+	}
+
+	changed := len(cmp.OnlyInA) + len(cmp.OnlyInB) + len(cmp.Differing)
+	if changed > 0 {
+		return fmt.Errorf("%d paths in %s changed since the last sync on %s; keep or copy them, then re-run without --expect-dest-clean to overwrite them",
+			changed, opts.Dest, recorded)
+	}
+	logger.Infof("Dest %s is unchanged since the last sync on %s", opts.Dest, recorded)
+	return nil
+}
+
+// recordDestManifest saves the dest as this sync left it for the next --expect-dest-clean
+// check. It only warns on failure, since the sync itself has already succeeded.
+func recordDestManifest(configDir string, opts *rsync.Options, logger logging.Logger) {
+	if !opts.WritesDest() {
+		return
+	}
+	path, err := config.DestManifestPath(configDir, opts.Dest)
+	if err == nil {
+		var manifest *rsync.DestManifest
+		if manifest, err = rsync.NewRunner(logger).RecordDestManifest(opts); err == nil {
+			err = rsync.SaveDestManifest(path, manifest)
+		}
+	}
+	if err != nil {
+		logger.Warnf("Could not record the dest manifest for --expect-dest-clean: %v", err)
+		return
+	}
+	logger.Debugf("Recorded dest manifest: %s", path)
+}
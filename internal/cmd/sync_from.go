@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// syncFromCmd pulls a source into the current directory
+var syncFromCmd = &cobra.Command{
+	Use:   "from [SOURCE_DIR]",
+	Short: "Sync SOURCE_DIR into the current directory",
+	Long: `Sync SOURCE_DIR into the current directory, the "pull" shorthand for
+sync --source SOURCE_DIR --dest .
+
+Without SOURCE_DIR, the source comes from the config file. Like sync, it reads
+sync.toml from the current directory, so a project config's ignore patterns,
+report path, and other defaults apply.
+
+Examples:
+  sync-tools sync from ../upstream --dry-run
+  sync-tools sync from /mnt/share/project --ignore-src "*.log"`,
+	Args:    cobra.MaximumNArgs(1),
+	PreRunE: validateSyncFlags,
+	RunE:    runSyncFrom,
+}
+
+func init() {
+	syncCmd.AddCommand(syncFromCmd)
+	addShorthandSyncFlags(syncFromCmd)
+}
+
+func runSyncFrom(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	// Without an argument the config's source is used, as it is for sync
+	flagSources = args
+	flagDest = cwd
+	return runSync(cmd, args)
+}
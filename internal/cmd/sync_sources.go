@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// runSourcesSync syncs several sources into opts.Dest, one rsync run per source. Like rsync
+// given several sources, each lands in a directory named after it (~/docs at dest/docs), or
+// at its recreated path with --relative, so one source's mirror never deletes another's
+// files. Sources run in order and the first failure stops the rest.
+func runSourcesSync(cmd *cobra.Command, opts *rsync.Options, sources []string, logger logging.Logger) error {
+	if opts.Interactive || opts.Preview || opts.Patch != "" || rsync.IsPatchReport(opts.Report) || opts.FilesFrom != "" || flagEstimate {
+		return fmt.Errorf("several --source flags can't be combined with --interactive, --preview, --patch, --files-from, or --estimate")
+	}
+
+	if !rsync.IsRemotePath(opts.Dest) {
+		destPath, err := filepath.Abs(opts.Dest)
+		if err != nil {
+			return fmt.Errorf("error resolving dest path: %w", err)
+		}
+		opts.Dest = destPath
+		if info, err := os.Stat(opts.Dest); err == nil && !info.IsDir() {
+			return fmt.Errorf("dest must be a directory when syncing several sources: %s", opts.Dest)
+		}
+	}
+
+	optsList, err := sourceOptions(opts, sources)
+	if err != nil {
+		return err
+	}
+	configDir, _ := cmd.Flags().GetString("config-dir")
+	for _, sourceOpts := range optsList {
+		if err := validateSyncTargets(sourceOpts, logger, opts.Yes || flagForce); err != nil {
+			return err
+		}
+		if flagExpectDestClean {
+			if err := checkDestClean(configDir, sourceOpts, logger); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+		}
+		if !flagIgnoreStale {
+			if err := checkStaleArtifacts(sourceOpts, logger); err != nil {
+				return err
+			}
+		}
+	}
+
+	ctx, cancel := syncContext(flagTimeout, "sync")
+	defer cancel()
+
+	start := time.Now()
+	var total rsync.SyncStats
+	var runs []rsync.StatsRecord
+	runner := rsync.NewRunner(logger)
+	runner.ShowDryRun(os.Stdout)
+	runner.ShowProgress(os.Stdout, stdoutIsTerminal())
+	for i, sourceOpts := range optsList {
+		logger.Infof("Syncing source %d/%d: %s -> %s", i+1, len(optsList), sourceOpts.Source, sourceOpts.Dest)
+		sourceStart := time.Now()
+		err = runner.SyncContext(ctx, sourceOpts)
+		total.Add(runner.Stats())
+		record := rsync.NewStatsRecord(sourceOpts, runner.Stats(), sourceStart, err)
+		runs = append(runs, record)
+		appendStatsRecord(record, logger)
+		if err != nil {
+			err = fmt.Errorf("source %s failed: %w", sourceOpts.Source, err)
+			break
+		}
+		if flagExpectDestClean {
+			recordDestManifest(configDir, sourceOpts, logger)
+		}
+	}
+	// Safe mode turns each run into a dry run, which the summary should say
+	printSyncSummary(optsList[0], total)
+
+	err = checkFailOnChanges(cmd, total, err, logger)
+
+	// Hooks see every source, separated like a PATH
+	hookOpts := *opts
+	hookOpts.Source = strings.Join(sources, string(os.PathListSeparator))
+	finishSync(&hookOpts, total, runs, start, err, logger)
+	return err
+}
+
+// sourceOptions builds one set of options per source, resolving local paths and placing each
+// source's dest under opts.Dest. Without --relative, sources sharing a name would sync into
+// the same directory and delete each other's files, so that is refused.
+func sourceOptions(opts *rsync.Options, sources []string) ([]*rsync.Options, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error resolving source path: %w", err)
+	}
+
+	optsList := make([]*rsync.Options, 0, len(sources))
+	destSources := map[string]string{}
+	for _, source := range sources {
+		sourceOpts := *opts
+		if !rsync.IsRemotePath(source) {
+			if opts.Relative {
+				source = rsync.RelativeSource(source, cwd)
+			} else if filepath.IsAbs(source) {
+				source = filepath.Clean(source)
+			} else {
+				source = filepath.Join(cwd, source)
+			}
+		}
+		sourceOpts.Source = source
+
+		if !opts.Relative {
+			name := filepath.Base(strings.TrimRight(filepath.ToSlash(source), "/"))
+			if rsync.IsRemotePath(opts.Dest) {
+				sourceOpts.Dest = strings.TrimRight(opts.Dest, "/") + "/" + name
+			} else {
+				sourceOpts.Dest = filepath.Join(opts.Dest, name)
+			}
+			if other, ok := destSources[sourceOpts.Dest]; ok {
+				return nil, fmt.Errorf("sources %s and %s would both sync into %s; use --relative to keep their paths apart", other, source, sourceOpts.Dest)
+			}
+			destSources[sourceOpts.Dest] = source
+		}
+		optsList = append(optsList, &sourceOpts)
+	}
+	return optsList, nil
+}
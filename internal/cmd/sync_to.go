@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// syncToCmd pushes the current directory to a destination
+var syncToCmd = &cobra.Command{
+	Use:   "to DEST_DIR",
+	Short: "Sync the current directory to DEST_DIR",
+	Long: `Sync the current directory to DEST_DIR, the "push" shorthand for
+sync --source . --dest DEST_DIR.
+
+Examples:
+  sync-tools sync to ../backup --dry-run
+  sync-tools sync to /mnt/share/project --ignore-src "*.log"`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateSyncFlags,
+	RunE:    runSyncTo,
+}
+
+func init() {
+	syncCmd.AddCommand(syncToCmd)
+	addShorthandSyncFlags(syncToCmd)
+}
+
+// addShorthandSyncFlags registers the flags sync to and sync from accept. They share the sync
+// command's flag variables, so runSync sees the same options.
+func addShorthandSyncFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&flagMode, "mode", "one-way", "Sync mode: one-way or two-way")
+	cmd.Flags().BoolVar(&flagPropagateDeletes, "propagate-deletes", false, propagateDeletesUsage)
+	cmd.Flags().BoolVar(&flagInteractiveConflicts, "interactive-conflicts", false, interactiveConflictsUsage)
+	cmd.Flags().StringVar(&flagConflictSuffix, "conflict-suffix", "", conflictSuffixUsage)
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Show what would be synced without making changes")
+	cmd.Flags().BoolVar(&flagExecute, "execute", false, "Apply changes when safe_mode is enabled in the config (otherwise syncs run as dry runs)")
+	cmd.Flags().BoolVar(&flagUseSourceGitignore, "use-source-gitignore", false, "Include .gitignore patterns from source")
+	cmd.Flags().BoolVar(&flagUseGlobalGitignore, "use-global-gitignore", false, useGlobalGitignoreUsage)
+	cmd.Flags().BoolVar(&flagExcludeHiddenDirs, "exclude-hidden-dirs", false, "Exclude all hidden directories (starting with .)")
+	cmd.Flags().BoolVar(&flagExcludeVCS, "exclude-vcs", false, excludeVCSUsage)
+	cmd.Flags().BoolVar(&flagIncludeGit, "include-git", false, includeGitUsage)
+	cmd.Flags().StringSliceVar(&flagExcludeIfPresent, "exclude-if-present", nil, excludeIfPresentUsage)
+	cmd.Flags().StringArrayVar(&flagFilterRules, "filter-rule", nil, filterRuleUsage)
+	cmd.Flags().StringVar(&flagDumpFilters, "dump-filters", "", dumpFiltersUsage)
+	cmd.Flags().Lookup("dump-filters").NoOptDefVal = "-"
+	cmd.Flags().BoolVar(&flagOnlySyncignore, "only-syncignore", false, "Only use .syncignore files, ignore other filters")
+	cmd.Flags().BoolVar(&flagNoNestedSyncignore, "no-nested-syncignore", false, noNestedSyncignoreUsage)
+	cmd.Flags().StringSliceVar(&flagIgnoreSrc, "ignore-src", nil, "Source-side ignore patterns")
+	cmd.Flags().StringSliceVar(&flagIgnoreDest, "ignore-dest", nil, "Destination-side ignore patterns")
+	cmd.Flags().StringSliceVar(&flagOnly, "only", nil, "Whitelist mode - only sync these paths")
+	cmd.Flags().BoolVar(&flagIgnoreCase, "ignore-case", false, "Match ignore and --only patterns case-insensitively")
+	cmd.Flags().IntVar(&flagMaxDepth, "max-depth", 0, "Only sync paths up to N levels below the source (0 for unlimited); deeper dest content is left alone")
+	cmd.Flags().BoolVar(&flagPruneEmptyDirs, "prune-empty-dirs", false, pruneEmptyDirsUsage)
+	cmd.Flags().BoolVar(&flagSuper, "super", false, superUsage)
+	cmd.Flags().BoolVar(&flagFakeSuper, "fake-super", false, fakeSuperUsage)
+	cmd.Flags().StringVar(&flagLogLevel, "log-level", "", "Log level: DEBUG, INFO, WARNING, ERROR, CRITICAL")
+	cmd.Flags().StringVar(&flagLogFile, "log-file", "", "Path to write sync-tools' own logs (see --rsync-log-file for rsync's transfer log)")
+	cmd.Flags().StringVar(&flagRsyncLogFile, "rsync-log-file", "", rsyncLogFileUsage)
+	cmd.Flags().StringVar(&flagRsyncLogFormat, "rsync-log-format", "", rsyncLogFormatUsage)
+	cmd.Flags().StringVar(&flagLogFormat, "log-format", "text", "Log format: text or json")
+	cmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Automatically confirm prompts")
+	cmd.Flags().BoolVar(&flagPartial, "partial", false, partialUsage)
+	cmd.Flags().StringVar(&flagPartialDir, "partial-dir", "", partialDirUsage)
+	cmd.Flags().BoolVar(&flagSizeOnly, "size-only", false, sizeOnlyUsage)
+	cmd.Flags().BoolVar(&flagDedupe, "dedupe", false, dedupeUsage)
+	cmd.Flags().BoolVar(&flagIncludeUnchanged, "include-unchanged", false, includeUnchangedUsage)
+	cmd.Flags().BoolVar(&flagIgnoreTimes, "ignore-times", false, ignoreTimesUsage)
+	cmd.Flags().BoolVar(&flagNoArchive, "no-archive", false, noArchiveUsage)
+	cmd.Flags().BoolVar(&flagDelayUpdates, "delay-updates", false, "Stage updated files and move them into place together at the end of the transfer")
+	cmd.Flags().StringVar(&flagRsyncBinary, "rsync-binary", "", "Path to the local rsync executable (default: rsync on PATH)")
+	cmd.Flags().BoolVar(&flagIgnoreErrors, "ignore-errors", false, ignoreErrorsUsage)
+	cmd.Flags().StringVar(&flagChecksumChoice, "checksum-choice", "", checksumChoiceUsage)
+	cmd.Flags().StringVar(&flagRsyncExtraArgs, "rsync-extra-args", "", rsyncExtraArgsUsage)
+	cmd.Flags().DurationVar(&flagTimeout, "timeout", 0, timeoutUsage)
+	cmd.Flags().BoolVar(&flagForce, "force", false, "Skip safety checks, such as refusing to mirror an empty source over a populated dest")
+	cmd.Flags().BoolVar(&flagExpectDestClean, "expect-dest-clean", false, expectDestCleanUsage)
+
+	cmd.RegisterFlagCompletionFunc("mode", cobra.FixedCompletions(validModes, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("log-level", cobra.FixedCompletions(validLogLevels, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("log-format", cobra.FixedCompletions(validLogFormats, cobra.ShellCompDirectiveNoFileComp))
+	cmd.RegisterFlagCompletionFunc("checksum-choice", cobra.FixedCompletions(rsync.ChecksumChoices, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runSyncTo(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("error getting current directory: %w", err)
+	}
+
+	flagSources = []string{cwd}
+	flagDest = args[0]
+	return runSync(cmd, args)
+}
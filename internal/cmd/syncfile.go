@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/notify"
 	"github.com/DamianReeves/sync-tools/internal/rsync"
 	"github.com/DamianReeves/sync-tools/pkg/syncfile"
 	"github.com/spf13/cobra"
@@ -48,26 +52,81 @@ Available Instructions:
   EXCLUDE pattern            - Exclude files/folders matching pattern
   INCLUDE pattern            - Include files (unignore pattern)
   ONLY pattern               - Whitelist mode - only sync matching files
+  EXCLUDEIFPRESENT name      - Skip directories holding a file with this name
+  FILTER rule               - Raw rsync filter rule, after the generated ones
   DRYRUN true|false         - Enable/disable dry run mode
   PATCH filename            - Generate git patch file instead of syncing
   APPLYPATCH true|false     - Apply generated patch after creation
   PREVIEW true|false        - Show colored diff preview before sync
+  PREVIEWFORMAT format      - Preview as git, unified, side-by-side, or stat (implies PREVIEW)
+  REPORT filename           - Write this operation's report (.md, or .patch/.diff)
+  PLAN filename             - Write the planned changes as markdown instead of syncing
   AUTOCONFIRM true|false    - Auto-confirm patch application (like -y)
   GITIGNORE true|false      - Use source .gitignore patterns
+  GLOBALGITIGNORE true|false - Use git's global gitignore (core.excludesFile)
   HIDDENDIRS exclude|include - Exclude or include hidden directories
+  RELATIVE true|false       - Recreate the source path under the dest (rsync -R)
+  PRUNEEMPTYDIRS true|false - Skip directories the filters leave empty
+  SUPER true|false          - Have the receiver set ownership as if root (rsync --super)
+  FAKESUPER true|false      - Keep ownership in dest xattrs for non-root backups
+  WHOLEFILE true|false      - Copy whole files (true) or force delta transfers (false)
+  INPLACE true|false        - Update dest files in place
+  SPARSE true|false         - Write sparse files in the dest
+  DELAYUPDATES true|false   - Move updated files into place together at the end
+  SIZEONLY true|false       - Skip files whose size matches, whatever their mtimes
+  PARTIAL true|false        - Keep partial files so an interrupted sync resumes them
+  PARTIALDIR dir            - Keep partial files in this directory until they finish
+  IGNORETIMES true|false    - Update every file, even those matching in size and mtime
+  CHECKSUM algorithm        - rsync checksum algorithm, e.g. xxh128 (rsync 3.2+)
+  DEDUPE true|false         - Hard-link copied files to identical files already in the dest
+  NOARCHIVE true|false      - Use rsync --recursive instead of --archive
+  INCLUDEUNCHANGED true|false - Also list and count files already up to date
+  RSYNCBIN path             - Use a specific rsync executable
+  RSYNCARGS args...         - Pass extra options to rsync (quotes honored)
+  SAFEMODE true|false       - Run every SYNC as a dry run unless --execute is passed
+  IGNOREERRORS true|false   - Skip unreadable source files instead of failing the SYNC
+  WATCH true|false          - Re-run this SYNC when its source changes (with --watch)
   VAR name=value            - Define a variable
   ENV name=value            - Define an environment variable
   RUN command               - Execute command (pre/post sync hooks)
+  IF ${VAR}=value           - Only apply the following instructions when the condition holds (== also works)
+  ENDIF                     - Close an IF block
   # comment                 - Comments
 
-Variables can be referenced using ${name} or $name syntax.`,
+Variables can be referenced using ${name} or $name syntax.
+
+--timeout limits each SYNC operation and --total-timeout the whole SyncFile;
+whichever fires first stops the running rsync and fails the run.
+
+The first failed SYNC stops the run unless --keep-going is passed, which runs
+the rest, prints which failed, and exits with status 2 if any did.
+
+--resume records each finished SYNC in SYNCFILE.journal, so running it again
+after a failure skips them; the journal is removed once every SYNC succeeds.
+--restart discards the journal and runs them all.
+
+--watch runs every operation once, then keeps re-running the ones marked
+WATCH true whenever their source changes, until interrupted.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runSyncfile,
 }
 
 var (
-	flagSyncfileDryRun bool
-	flagSyncfileList   bool
+	flagSyncfileDryRun  bool
+	flagSyncfileList    bool
+	flagSyncfileExecute bool
+	flagSyncfileReport  string
+
+	flagSyncfileTimeout      time.Duration
+	flagSyncfileTotalTimeout time.Duration
+	flagSyncfileNotify       string
+	flagSyncfileStats        bool
+	flagSyncfileProgress     bool
+	flagSyncfileWatch        bool
+	flagSyncfileDebounce     time.Duration
+	flagSyncfileKeepGoing    bool
+	flagSyncfileResume       bool
+	flagSyncfileRestart      bool
 )
 
 func init() {
@@ -75,9 +134,41 @@ func init() {
 
 	syncfileCmd.Flags().BoolVar(&flagSyncfileDryRun, "dry-run", false, "Override all SYNC operations to use dry-run mode")
 	syncfileCmd.Flags().BoolVar(&flagSyncfileList, "list", false, "List sync operations without executing")
+	syncfileCmd.Flags().StringVar(&flagSyncfileReport, "report", "", "Write a markdown summary of all operations to this path")
+	syncfileCmd.Flags().BoolVar(&flagSyncfileExecute, "execute", false, "Apply changes when the SyncFile sets SAFEMODE true")
+	syncfileCmd.Flags().DurationVar(&flagSyncfileTimeout, "timeout", 0, "Stop any single SYNC operation that takes longer than this (e.g. 10m; 0 for no limit)")
+	syncfileCmd.Flags().DurationVar(&flagSyncfileTotalTimeout, "total-timeout", 0, "Stop the whole SyncFile run if it takes longer than this (0 for no limit)")
+	syncfileCmd.Flags().StringVar(&flagSyncfileNotify, "notify", "", notifyUsage)
+	syncfileCmd.Flags().BoolVar(&flagSyncfileStats, "stats", false, "Include throughput (MB/s) in each operation's log line and the summary")
+	syncfileCmd.Flags().BoolVar(&flagSyncfileProgress, "progress", false, progressUsage)
+	syncfileCmd.Flags().BoolVar(&flagSyncfileWatch, "watch", false, "After the first run, re-run operations marked WATCH true whenever their source changes")
+	syncfileCmd.Flags().DurationVar(&flagSyncfileDebounce, "debounce", time.Second, "With --watch, wait until a source has been quiet this long before re-syncing it")
+	syncfileCmd.Flags().BoolVar(&flagSyncfileResume, "resume", false, "Record finished operations in a journal next to the SyncFile and skip those an earlier --resume run finished")
+	syncfileCmd.Flags().BoolVar(&flagSyncfileRestart, "restart", false, "Like --resume, but discard the journal first and run every operation")
+	syncfileCmd.Flags().BoolVar(&flagSyncfileKeepGoing, "keep-going", false, "Run the remaining operations when one fails, then exit with status 2 and a table of which failed")
 }
 
-func runSyncfile(cmd *cobra.Command, args []string) error {
+func runSyncfile(cmd *cobra.Command, args []string) (runErr error) {
+	if err := validateTimeout("--timeout", flagSyncfileTimeout); err != nil {
+		return err
+	}
+	if err := validateTimeout("--total-timeout", flagSyncfileTotalTimeout); err != nil {
+		return err
+	}
+	if err := notify.Validate(flagSyncfileNotify); err != nil {
+		return err
+	}
+	if flagSyncfileWatch && (flagSyncfileTotalTimeout > 0 || flagSyncfileNotify != "") {
+		return fmt.Errorf("--watch runs until interrupted and cannot be combined with --total-timeout or --notify")
+	}
+	if flagSyncfileDebounce < 0 {
+		return fmt.Errorf("--debounce must not be negative")
+	}
+	if flagSyncfileResume && flagSyncfileRestart {
+		return fmt.Errorf("--resume and --restart cannot be used together")
+	}
+	sweepStaleTempFiles()
+
 	// Determine SyncFile path
 	syncfilePath := "SyncFile"
 	if len(args) > 0 {
@@ -130,6 +221,12 @@ func runSyncfile(cmd *cobra.Command, args []string) error {
 		logger.Info("Dry-run mode enabled for all operations")
 	}
 
+	for _, opts := range optsList {
+		opts.Execute = flagSyncfileExecute
+		opts.ShowThroughput = flagSyncfileStats
+		opts.Progress = flagSyncfileProgress
+	}
+
 	// List operations if requested
 	if flagSyncfileList {
 		for i, opts := range optsList {
@@ -146,6 +243,15 @@ func runSyncfile(cmd *cobra.Command, args []string) error {
 			if opts.Preview {
 				logger.Infof("  Preview: %v", opts.Preview)
 			}
+			if opts.Report != "" {
+				logger.Infof("  Report: %s", opts.Report)
+			}
+			if opts.Plan != "" {
+				logger.Infof("  Plan: %s", opts.Plan)
+			}
+			if opts.Watch {
+				logger.Infof("  Watch: %v", opts.Watch)
+			}
 			if len(opts.IgnoreSrc) > 0 {
 				logger.Infof("  Filters: %v", opts.IgnoreSrc)
 			}
@@ -156,27 +262,207 @@ func runSyncfile(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Execute sync operations
+	// --total-timeout covers every operation; --timeout is applied to each one below
+	ctx, cancel := syncContext(flagSyncfileTotalTimeout, "SyncFile")
+	defer cancel()
+
+	// Execute sync operations, keeping each one's stats and result for the final summary
 	runner := rsync.NewRunner(logger)
-	
-	for i, opts := range optsList {
-		logger.Infof("Executing sync operation %d/%d", i+1, len(optsList))
-		logger.Infof("  %s -> %s", opts.Source, opts.Dest)
+	runner.ShowDryRun(os.Stdout)
+	runner.ShowProgress(os.Stdout, stdoutIsTerminal())
+	opStats := make([]rsync.SyncStats, 0, len(optsList))
+	opErrs := make([]error, 0, len(optsList))
+
+	// --resume skips the operations its journal says an interrupted run already finished
+	var journal *syncfileJournal
+	if flagSyncfileResume || flagSyncfileRestart {
+		if journal, err = openSyncfileJournal(syncfileJournalPath(syncfilePath), flagSyncfileRestart); err != nil {
+			return err
+		}
+	} else if _, err := os.Stat(syncfileJournalPath(syncfilePath)); err == nil {
+		logger.Warnf("An interrupted --resume run left %s; running every operation (pass --resume to skip the finished ones)", syncfileJournalPath(syncfilePath))
+	}
 
-		// Resolve paths relative to SyncFile location
+	// One notification covers the whole file, including the operation that failed
+	start := time.Now()
+	var runs []rsync.StatsRecord
+	defer func() {
+		sendNotification(flagSyncfileNotify, "SyncFile "+syncfilePath, runs, start, runErr, logger)
+	}()
+
+	for i, opts := range optsList {
+		if ctx.Err() != nil {
+			logger.Errorf("Stopped before sync operation %d/%d: %v", i+1, len(optsList), context.Cause(ctx))
+			return fmt.Errorf("sync operation %d not started: %w", i+1, context.Cause(ctx))
+		}
+		// Resolve local paths relative to SyncFile location
 		syncfileDir := filepath.Dir(syncfilePath)
-		if !filepath.IsAbs(opts.Source) {
-			opts.Source = filepath.Join(syncfileDir, opts.Source)
+		if !rsync.IsRemotePath(opts.Source) {
+			if opts.Relative {
+				opts.Source = rsync.RelativeSource(opts.Source, syncfileDir)
+			} else if !filepath.IsAbs(opts.Source) {
+				opts.Source = filepath.Join(syncfileDir, opts.Source)
+			}
 		}
-		if !filepath.IsAbs(opts.Dest) {
+		if !filepath.IsAbs(opts.Dest) && !rsync.IsRemotePath(opts.Dest) {
 			opts.Dest = filepath.Join(syncfileDir, opts.Dest)
 		}
+		if opts.Report != "" && !filepath.IsAbs(opts.Report) {
+			opts.Report = filepath.Join(syncfileDir, opts.Report)
+		}
+		if opts.Plan != "" && !filepath.IsAbs(opts.Plan) {
+			opts.Plan = filepath.Join(syncfileDir, opts.Plan)
+		}
 
-		if err := runner.Sync(opts); err != nil {
+		if journal != nil && journal.completed(i+1, opts) {
+			logger.Infof("Skipping sync operation %d/%d, finished by an earlier run", i+1, len(optsList))
+			opStats = append(opStats, rsync.SyncStats{})
+			opErrs = append(opErrs, nil)
+			continue
+		}
+		logger.Infof("Executing sync operation %d/%d", i+1, len(optsList))
+		logger.Infof("  %s -> %s", opts.Source, opts.Dest)
+
+		opStart := time.Now()
+		opCtx, opCancel := withTimeout(ctx, flagSyncfileTimeout, fmt.Sprintf("operation %d", i+1))
+		err := runner.SyncContext(opCtx, opts)
+		opCancel()
+		runs = append(runs, rsync.NewStatsRecord(opts, runner.Stats(), opStart, err))
+		if err != nil && !flagSyncfileKeepGoing {
 			return fmt.Errorf("sync operation %d failed: %w", i+1, err)
 		}
+		if err != nil {
+			logger.Errorf("Sync operation %d failed, continuing with the rest: %v", i+1, err)
+		} else {
+			stats := runner.Stats()
+			logger.Infof("Sync operation %d/%d: %s", i+1, len(optsList), describeStats(&stats, opts.DryRun || opts.Plan != ""))
+		}
+		// Dry runs and plans change nothing, so a resumed run must still do them for real
+		if err == nil && journal != nil && opts.WritesDest() && opts.Plan == "" {
+			if err := journal.record(i+1, opts); err != nil {
+				return err
+			}
+		}
+		opStats = append(opStats, runner.Stats())
+		opErrs = append(opErrs, err)
 	}
 
-	logger.Info("All sync operations completed successfully")
+	failed := 0
+	for _, err := range opErrs {
+		if err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		logger.Info("All sync operations completed successfully")
+		if journal != nil {
+			if err := journal.remove(); err != nil {
+				return err
+			}
+		}
+	}
+
+	total := logSyncfileSummary(logger, optsList, opStats)
+	if flagSyncfileReport != "" {
+		if err := writeSyncfileReport(flagSyncfileReport, optsList, opStats, opErrs, total); err != nil {
+			return fmt.Errorf("error writing SyncFile report: %w", err)
+		}
+		logger.Infof("SyncFile report written to %s", flagSyncfileReport)
+	}
+	if flagSyncfileKeepGoing {
+		printSyncfileResults(optsList, opErrs)
+	}
+	if failed > 0 {
+		// The results table already says which operations failed and why
+		cmd.SilenceUsage = true
+		return &exitError{code: exitOperationsFailed, err: fmt.Errorf("%d of %d sync operations failed", failed, len(optsList))}
+	}
+
+	if flagSyncfileWatch {
+		return watchSyncfile(ctx, logger, optsList, flagSyncfileDebounce)
+	}
 	return nil
+}
+
+// exitOperationsFailed is the exit status of a --keep-going run in which some operations
+// failed, apart from the 1 of a run that failed outright
+const exitOperationsFailed = 2
+
+// printSyncfileResults prints one line per operation saying whether it succeeded, with the
+// error of each that failed
+func printSyncfileResults(optsList []*rsync.Options, opErrs []error) {
+	fmt.Println("Operation results:")
+	for i, err := range opErrs {
+		status, detail := "ok", ""
+		if err != nil {
+			status, detail = "FAILED", ": "+err.Error()
+		}
+		fmt.Printf("  %-3d %-7s %s -> %s%s\n", i+1, status, optsList[i].Source, optsList[i].Dest, detail)
+	}
+}
+
+// logSyncfileSummary logs the changes across all operations and which ones hit conflicts,
+// returning the aggregate stats
+func logSyncfileSummary(logger logging.Logger, optsList []*rsync.Options, opStats []rsync.SyncStats) rsync.SyncStats {
+	var total rsync.SyncStats
+	for i, stats := range opStats {
+		total.Add(stats)
+		if stats.Conflicts > 0 {
+			logger.Warnf("Operation %d (%s -> %s) had %d conflicts", i+1, optsList[i].Source, optsList[i].Dest, stats.Conflicts)
+		}
+	}
+
+	// Unchanged files are only counted by operations that set INCLUDEUNCHANGED
+	unchanged := ""
+	if total.FilesUnchanged > 0 {
+		unchanged = fmt.Sprintf(", %d unchanged", total.FilesUnchanged)
+	}
+	summary := fmt.Sprintf("Summary: %d created, %d updated, %d deleted%s, %d bytes across %d operations in %s",
+		total.FilesCreated, total.FilesUpdated, total.FilesDeleted, unchanged, total.BytesTransferred, len(opStats),
+		rsync.FormatDuration(total.Duration))
+	if flagSyncfileStats {
+		summary += fmt.Sprintf(" (%.2f MB/s)", total.Throughput())
+	}
+	logger.Info(summary)
+	return total
+}
+
+// writeSyncfileReport writes a markdown table of per-operation and total changes, and the
+// operations --keep-going ran past after they failed
+func writeSyncfileReport(path string, optsList []*rsync.Options, opStats []rsync.SyncStats, opErrs []error, total rsync.SyncStats) error {
+	var sb strings.Builder
+	sb.WriteString("# SyncFile Report\n\n")
+	sb.WriteString("| # | Source | Dest | Created | Updated | Deleted | Conflicts | Bytes | Duration |\n")
+	sb.WriteString("|---|--------|------|---------|---------|---------|-----------|-------|----------|\n")
+	for i, stats := range opStats {
+		fmt.Fprintf(&sb, "| %d | %s | %s | %d | %d | %d | %d | %d | %s |\n", i+1, optsList[i].Source, optsList[i].Dest,
+			stats.FilesCreated, stats.FilesUpdated, stats.FilesDeleted, stats.Conflicts, stats.BytesTransferred,
+			rsync.FormatDuration(stats.Duration))
+	}
+	fmt.Fprintf(&sb, "| **Total** | | | %d | %d | %d | %d | %d | %s |\n",
+		total.FilesCreated, total.FilesUpdated, total.FilesDeleted, total.Conflicts, total.BytesTransferred,
+		rsync.FormatDuration(total.Duration))
+
+	var failures strings.Builder
+	for i, err := range opErrs {
+		if err != nil {
+			fmt.Fprintf(&failures, "- Operation %d (%s -> %s): %v\n", i+1, optsList[i].Source, optsList[i].Dest, err)
+		}
+	}
+	if failures.Len() > 0 {
+		sb.WriteString("\n## Failed Operations\n\n")
+		sb.WriteString(failures.String())
+	}
+
+	// Files IGNOREERRORS let an operation finish without
+	if len(total.Skipped) > 0 {
+		sb.WriteString("\n## Skipped Files\n\n")
+		for i, stats := range opStats {
+			for _, file := range stats.Skipped {
+				fmt.Fprintf(&sb, "- %s (operation %d)\n", file, i+1)
+			}
+		}
+	}
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
 }
\ No newline at end of file
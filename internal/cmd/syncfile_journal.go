@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// journalEntry records one SyncFile operation that finished, so --resume can skip it. The
+// source and dest are kept so an edited SyncFile doesn't skip a different operation that
+// moved into the same position.
+type journalEntry struct {
+	Operation int       `json:"operation"`
+	Source    string    `json:"source"`
+	Dest      string    `json:"dest"`
+	Completed time.Time `json:"completed"`
+}
+
+// syncfileJournal is the progress journal of a --resume run, kept next to the SyncFile
+type syncfileJournal struct {
+	path string
+	done map[journalEntry]bool
+}
+
+// syncfileJournalPath returns the journal kept for the SyncFile at path
+func syncfileJournalPath(path string) string {
+	return path + ".journal"
+}
+
+// openSyncfileJournal loads the journal at path, or starts an empty one if it doesn't exist
+// or restart is set
+func openSyncfileJournal(path string, restart bool) (*syncfileJournal, error) {
+	journal := &syncfileJournal{path: path, done: map[journalEntry]bool{}}
+	if restart {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error removing SyncFile journal: %w", err)
+		}
+		return journal, nil
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return journal, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading SyncFile journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// A line cut short by a crash is an operation that wasn't recorded
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		journal.done[journalKey(entry.Operation, entry.Source, entry.Dest)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading SyncFile journal: %w", err)
+	}
+	return journal, nil
+}
+
+// journalKey identifies an operation in the done set, leaving out when it completed
+func journalKey(operation int, source, dest string) journalEntry {
+	return journalEntry{Operation: operation, Source: source, Dest: dest}
+}
+
+// completed reports whether the journal records operation (1-based) as done
+func (j *syncfileJournal) completed(operation int, opts *rsync.Options) bool {
+	return j.done[journalKey(operation, opts.Source, opts.Dest)]
+}
+
+// record appends operation as done, syncing the file so the entry survives a crash
+func (j *syncfileJournal) record(operation int, opts *rsync.Options) error {
+	data, err := json.Marshal(journalEntry{Operation: operation, Source: opts.Source, Dest: opts.Dest, Completed: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening SyncFile journal: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing SyncFile journal: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("error writing SyncFile journal: %w", err)
+	}
+	j.done[journalKey(operation, opts.Source, opts.Dest)] = true
+	return nil
+}
+
+// remove deletes the journal once every operation has finished, so the next run starts over
+func (j *syncfileJournal) remove() error {
+	if err := os.Remove(j.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing SyncFile journal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"sync"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// watchPollInterval is how often each watched source is rescanned for changes
+const watchPollInterval = 500 * time.Millisecond
+
+// watchSyncfile re-runs the SyncFile operations marked WATCH true whenever their source
+// changes, until ctx is cancelled. Operations sharing a source are debounced together, and
+// runs are serialized so two sources feeding one dest never sync at the same time.
+func watchSyncfile(ctx context.Context, logger logging.Logger, optsList []*rsync.Options, debounce time.Duration) error {
+	bySource := map[string][]int{}
+	var sources []string
+	for i, opts := range optsList {
+		if !opts.Watch {
+			continue
+		}
+		if rsync.IsRemotePath(opts.Source) {
+			return fmt.Errorf("sync operation %d: can't watch remote source %s", i+1, opts.Source)
+		}
+		if _, ok := bySource[opts.Source]; !ok {
+			sources = append(sources, opts.Source)
+		}
+		bySource[opts.Source] = append(bySource[opts.Source], i)
+	}
+	if len(sources) == 0 {
+		return fmt.Errorf("--watch needs at least one SYNC operation with WATCH true")
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, source := range sources {
+		logger.Infof("Watching %s (operations %v)", source, operationNumbers(bySource[source]))
+		wg.Add(1)
+		go func(source string) {
+			defer wg.Done()
+			ops := bySource[source]
+			watchSource(ctx, logger, source, watchedViews(optsList, ops), debounce, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				for _, i := range ops {
+					runWatchedOperation(ctx, logger, i, optsList[i])
+				}
+			})
+		}(source)
+	}
+	logger.Info("Watching for changes; press Ctrl-C to stop")
+
+	wg.Wait()
+	logger.Info("Stopped watching")
+	return nil
+}
+
+// watchSource polls source and calls onChange once it has been quiet for debounce after a
+// change to what views would sync
+func watchSource(ctx context.Context, logger logging.Logger, source string, views []*rsync.Options, debounce time.Duration, onChange func()) {
+	runner := rsync.NewRunner(logger)
+	last, err := treeFingerprint(runner, views)
+	if err != nil {
+		logger.Warnf("Error scanning %s: %v", source, err)
+	}
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current, err := treeFingerprint(runner, views)
+		if err != nil {
+			logger.Warnf("Error scanning %s: %v", source, err)
+			continue
+		}
+		if current != last {
+			last = current
+			changedAt = time.Now()
+			continue
+		}
+		if !changedAt.IsZero() && time.Since(changedAt) >= debounce {
+			changedAt = time.Time{}
+			logger.Infof("Change detected in %s", source)
+			onChange()
+			// Two-way syncs write back to the source; don't treat that as a new change
+			if current, err := treeFingerprint(runner, views); err == nil {
+				last = current
+			}
+		}
+	}
+}
+
+// runWatchedOperation re-runs one operation, logging rather than returning failures so
+// watching continues
+func runWatchedOperation(ctx context.Context, logger logging.Logger, i int, opts *rsync.Options) {
+	if ctx.Err() != nil {
+		return
+	}
+	opCtx, cancel := withTimeout(ctx, flagSyncfileTimeout, fmt.Sprintf("operation %d", i+1))
+	defer cancel()
+
+	runner := rsync.NewRunner(logger)
+	if err := runner.SyncContext(opCtx, opts); err != nil {
+		logger.Errorf("Sync operation %d failed: %v", i+1, err)
+		return
+	}
+	stats := runner.Stats()
+	logger.Infof("Sync operation %d: %d created, %d updated, %d deleted",
+		i+1, stats.FilesCreated, stats.FilesUpdated, stats.FilesDeleted)
+}
+
+// watchedViews returns the options of the watched operations ops, whose filters decide
+// which changes to their shared source count
+func watchedViews(optsList []*rsync.Options, ops []int) []*rsync.Options {
+	views := make([]*rsync.Options, len(ops))
+	for j, i := range ops {
+		views[j] = optsList[i]
+	}
+	return views
+}
+
+// treeFingerprint hashes the path, size, and modification time of everything views would
+// sync, so any create, delete, rename, or write of a synced path changes the result, while
+// writes under .git/, ignored build outputs, and other excluded paths don't
+func treeFingerprint(runner *rsync.Runner, views []*rsync.Options) (uint64, error) {
+	h := fnv.New64a()
+	for _, opts := range views {
+		err := runner.WalkIncluded(opts, func(relPath string, d fs.DirEntry) error {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "%s\x00%d\x00%d\x00", relPath, info.Size(), info.ModTime().UnixNano())
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		h.Write([]byte{0xff})
+	}
+	return h.Sum64(), nil
+}
+
+// operationNumbers converts operation indexes to the 1-based numbers shown in logs
+func operationNumbers(ops []int) []int {
+	numbers := make([]int, len(ops))
+	for i, op := range ops {
+		numbers[i] = op + 1
+	}
+	return numbers
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+	"github.com/spf13/cobra"
+)
+
+// undoCmd restores conflict copies over the files a two-way sync replaced
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore conflict backups in a destination to their original names",
+	Long: `Restore the <file>.conflict-<timestamp> copies a two-way sync keeps when it
+replaces a conflicting destination file, putting each file back as it was before
+the conflict was resolved. If the sync named its copies with --conflict-suffix,
+pass the same template here.
+
+When a file has several conflict copies, the oldest one made since --since is
+restored. Pass --keep-redo to rename the current files to <file>.redo-<timestamp>
+instead of overwriting them, so the undo itself can be reversed.
+
+Examples:
+  sync-tools undo --dest ./backup --dry-run
+  sync-tools undo --dest ./backup --since 2026-10-16T09:00:00Z --keep-redo`,
+	RunE: runUndo,
+}
+
+var (
+	flagUndoDest     string
+	flagUndoSince    string
+	flagUndoDryRun   bool
+	flagUndoKeepRedo bool
+	flagUndoYes      bool
+	flagUndoSuffix   string
+)
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().StringVar(&flagUndoDest, "dest", "", "Destination directory holding the conflict backups")
+	undoCmd.Flags().StringVar(&flagUndoSince, "since", "", "Only restore backups made at or after this time (RFC 3339, YYYY-MM-DD, or unix seconds)")
+	undoCmd.Flags().BoolVar(&flagUndoDryRun, "dry-run", false, "List the backups that would be restored without changing anything")
+	undoCmd.Flags().BoolVar(&flagUndoKeepRedo, "keep-redo", false, "Keep the replaced files as <file>.redo-<timestamp>")
+	undoCmd.Flags().BoolVarP(&flagUndoYes, "yes", "y", false, "Restore without asking for confirmation")
+	undoCmd.Flags().StringVar(&flagUndoSuffix, "conflict-suffix", "", "Template the sync named its conflict copies with (default \""+rsync.DefaultConflictSuffix+"\")")
+	undoCmd.MarkFlagRequired("dest")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	verbosity, _ := cmd.Flags().GetCount("verbose")
+	logger, err := logging.Setup("INFO", "", "text", verbosity)
+	if err != nil {
+		return fmt.Errorf("error setting up logging: %w", err)
+	}
+
+	since, err := parseSince(flagUndoSince)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(flagUndoDest); err != nil {
+		return fmt.Errorf("destination directory does not exist: %s", flagUndoDest)
+	}
+
+	names, err := rsync.ParseConflictSuffix(flagUndoSuffix)
+	if err != nil {
+		return err
+	}
+	backups, err := rsync.FindConflictBackups(flagUndoDest, names, since)
+	if err != nil {
+		return fmt.Errorf("error scanning for conflict backups: %w", err)
+	}
+	if len(backups) == 0 {
+		logger.Info("No conflict backups to restore")
+		return nil
+	}
+
+	for _, backup := range backups {
+		logger.Infof("Conflict backup for %s: %s (%s)", backup.Original, backup.Path, backup.Created.Format(time.RFC3339))
+	}
+	if flagUndoDryRun {
+		return nil
+	}
+
+	if !flagUndoYes && !confirmUndo(len(backups)) {
+		return fmt.Errorf("undo cancelled; re-run with --yes to restore without prompting")
+	}
+
+	for _, backup := range backups {
+		redoPath, err := rsync.RestoreConflictBackup(flagUndoDest, backup, flagUndoKeepRedo)
+		if err != nil {
+			return err
+		}
+		if redoPath != "" {
+			logger.Infof("Kept the replaced %s as %s", backup.Original, redoPath)
+		}
+		logger.Infof("Restored %s", backup.Original)
+	}
+	logger.Infof("Restored %d files", len(backups))
+	return nil
+}
+
+// parseSince accepts an RFC 3339 time, a YYYY-MM-DD date, or unix seconds; empty means no limit
+func parseSince(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since: %s (use RFC 3339, YYYY-MM-DD, or unix seconds)", value)
+}
+
+// confirmUndo asks before restoring, answering no when stdin isn't a terminal
+func confirmUndo(count int) bool {
+	if info, err := os.Stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	fmt.Printf("\nRestore %d files from their conflict backups? [y/N]: ", count)
+
+	var response string
+	if _, err := fmt.Scanln(&response); err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, set at build time with -ldflags (see the Makefile), e.g.
+//
+//	-X github.com/DamianReeves/sync-tools/internal/cmd.commit=$(git rev-parse --short HEAD)
+var (
+	version   = "0.2.0" // Incremented from Python version
+	commit    = ""
+	buildDate = ""
+)
+
+var flagVersionJSON bool
+
+// versionCmd prints the version with the details needed to identify a build
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Show the version, commit, build date, and Go version",
+	Long: `Show the version of sync-tools with the git commit and date it was built
+from, the Go version, and the platform. Include this output in bug reports.
+
+--json prints the same details as one JSON object for scripts.`,
+	Args: cobra.NoArgs,
+	RunE: runVersion,
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().BoolVar(&flagVersionJSON, "json", false, "Print the build details as JSON")
+}
+
+// buildInfo describes the running binary
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// currentBuildInfo returns the details set with -ldflags. Without them it falls back to the
+// revision go build stamps when building inside a checkout, with the commit's time as the date.
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		// A stamped commit of a dirty checkout isn't the whole story
+		if modified && commit == "" && info.Commit != "" {
+			info.Commit += "-dirty"
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := currentBuildInfo()
+	out := cmd.OutOrStdout()
+	if flagVersionJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	}
+
+	fmt.Fprintf(out, "sync-tools %s\n", info.Version)
+	fmt.Fprintf(out, "  commit:    %s\n", info.Commit)
+	fmt.Fprintf(out, "  built:     %s\n", info.BuildDate)
+	fmt.Fprintf(out, "  go:        %s\n", info.GoVersion)
+	fmt.Fprintf(out, "  platform:  %s\n", info.Platform)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/BurntSushi/toml"
 )
@@ -15,8 +16,14 @@ type Config struct {
 	Mode                string   `toml:"mode"`
 	DryRun              bool     `toml:"dry_run"`
 	UseSourceGitignore  bool     `toml:"use_source_gitignore"`
+	UseGlobalGitignore  bool     `toml:"use_global_gitignore"`
 	ExcludeHiddenDirs   bool     `toml:"exclude_hidden_dirs"`
+	ExcludeVCS          bool     `toml:"exclude_vcs"`
+	IncludeGit          bool     `toml:"include_git"`
+	ExcludeIfPresent    []string `toml:"exclude_if_present"`
+	FilterRules         []string `toml:"filter_rules"`
 	OnlySyncignore      bool     `toml:"only_syncignore"`
+	NoNestedSyncignore  bool     `toml:"no_nested_syncignore"`
 	IgnoreSrc           []string `toml:"ignore_src"`
 	IgnoreDest          []string `toml:"ignore_dest"`
 	Only                []string `toml:"only"`
@@ -24,14 +31,120 @@ type Config struct {
 	LogFile             string   `toml:"log_file"`
 	LogFormat           string   `toml:"log_format"`
 	Report              string   `toml:"report"`
+	Patch               string   `toml:"patch"`
+	ApplyPatch          bool     `toml:"apply_patch"`
+	Preview             bool     `toml:"preview"`
+	PreviewFormat       string   `toml:"preview_format"`
+	SafeMode            bool     `toml:"safe_mode"`
+	RsyncBinary         string   `toml:"rsync_binary"`
+	RsyncPath           string   `toml:"rsync_path"`
+	PasswordFile        string   `toml:"password_file"`
+	RsyncLogFile        string   `toml:"rsync_log_file"`
+	RsyncLogFormat      string   `toml:"rsync_log_format"`
+	RsyncExtraArgs      string   `toml:"rsync_extra_args"`
+	ChecksumChoice      string   `toml:"checksum_choice"`
+	SizeOnly            bool     `toml:"size_only"`
+	Dedupe              bool     `toml:"dedupe"`
+	NoArchive           bool     `toml:"no_archive"`
+	IncludeUnchanged    bool     `toml:"include_unchanged"`
+	Partial             bool     `toml:"partial"`
+	PartialDir          string   `toml:"partial_dir"`
+	IgnoreTimes         bool     `toml:"ignore_times"`
+	IgnoreErrors        bool     `toml:"ignore_errors"`
+	Relative            bool     `toml:"relative"`
+	PruneEmptyDirs      bool     `toml:"prune_empty_dirs"`
+	Super               bool     `toml:"super"`
+	FakeSuper           bool     `toml:"fake_super"`
+	ConflictSuffix      string   `toml:"conflict_suffix"`
 }
 
 // LoadConfig loads configuration from a TOML file
 // If configPath is empty, it will look for common config files
 func LoadConfig(configPath string) (*Config, error) {
+	return LoadLayeredConfig(configPath, "", false)
+}
+
+// LoadLayeredConfig loads the global config (unless useGlobal is false) and merges the
+// project config over it. globalDir overrides the global config directory when set.
+//
+// Project values replace global ones, except ignore_src and ignore_dest, which are appended
+// to the global lists so personal ignores (editor swap files, .DS_Store) always apply.
+// The only whitelist is project-specific, so it is replaced like any other key.
+func LoadLayeredConfig(configPath, globalDir string, useGlobal bool) (*Config, error) {
 	var config Config
 
-	// If no config path specified, look for common config files
+	if useGlobal {
+		globalPath, err := GlobalConfigPath(globalDir)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(globalPath); err == nil {
+			if _, err := toml.DecodeFile(globalPath, &config); err != nil {
+				return nil, fmt.Errorf("error reading global config %s: %w", globalPath, err)
+			}
+		}
+	}
+
+	projectPath, err := findConfigPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if projectPath != "" {
+		// The decoder may reuse the existing backing arrays, so keep copies of the global lists
+		globalIgnoreSrc := slices.Clone(config.IgnoreSrc)
+		globalIgnoreDest := slices.Clone(config.IgnoreDest)
+
+		// Decoding over the global values only replaces the keys the project file sets
+		meta, err := toml.DecodeFile(projectPath, &config)
+		if err != nil {
+			return nil, err
+		}
+		if meta.IsDefined("ignore_src") {
+			config.IgnoreSrc = append(globalIgnoreSrc, config.IgnoreSrc...)
+		}
+		if meta.IsDefined("ignore_dest") {
+			config.IgnoreDest = append(globalIgnoreDest, config.IgnoreDest...)
+		}
+	}
+
+	// Validate configuration
+	if err := validateConfig(&config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// GlobalConfigPath returns the per-user config file: config.toml in dir if set,
+// otherwise $XDG_CONFIG_HOME/sync-tools or ~/.config/sync-tools
+func GlobalConfigPath(dir string) (string, error) {
+	dir, err := configDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// configDir returns dir if set, otherwise $XDG_CONFIG_HOME/sync-tools or ~/.config/sync-tools
+func configDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("error locating home directory for global config: %w", err)
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "sync-tools"), nil
+}
+
+// findConfigPath returns configPath if it exists, or the first common project config file
+// found when it is empty. An empty result means there is no project config.
+func findConfigPath(configPath string) (string, error) {
 	if configPath == "" {
 		// Look in current directory first
 		candidates := []string{
@@ -49,33 +162,17 @@ func LoadConfig(configPath string) (*Config, error) {
 
 		for _, candidate := range candidates {
 			if _, err := os.Stat(candidate); err == nil {
-				configPath = candidate
-				break
+				return candidate, nil
 			}
 		}
-
-		// If still no config file found, return empty config
-		if configPath == "" {
-			return &config, nil
-		}
+		return "", nil
 	}
 
 	// Check if config file exists
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		return nil, err
+		return "", err
 	}
-
-	// Decode the TOML file
-	if _, err := toml.DecodeFile(configPath, &config); err != nil {
-		return nil, err
-	}
-
-	// Validate configuration
-	if err := validateConfig(&config); err != nil {
-		return nil, err
-	}
-
-	return &config, nil
+	return configPath, nil
 }
 
 // validateConfig validates the configuration values
@@ -105,5 +202,10 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("invalid log format: %s (must be 'text' or 'json')", config.LogFormat)
 	}
 
+	// A patch can only be applied once there is one to apply
+	if config.ApplyPatch && config.Patch == "" {
+		return fmt.Errorf("apply_patch requires patch to name the patch file")
+	}
+
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// lastRunFile is written next to the global config after each successful sync
+const lastRunFile = "last-run.toml"
+
+// LastRun is the part of a successful sync that `sync --repeat` restores: the pair of
+// paths and the options that decide what gets synced. One-off settings such as dry runs,
+// logging, and reports are left out, so a repeat always states them afresh.
+type LastRun struct {
+	Source             string   `toml:"source"`
+	Dest               string   `toml:"dest"`
+	Mode               string   `toml:"mode"`
+	UseSourceGitignore bool     `toml:"use_source_gitignore"`
+	UseGlobalGitignore bool     `toml:"use_global_gitignore"`
+	ExcludeHiddenDirs  bool     `toml:"exclude_hidden_dirs"`
+	ExcludeVCS         bool     `toml:"exclude_vcs"`
+	IncludeGit         bool     `toml:"include_git"`
+	OnlySyncignore     bool     `toml:"only_syncignore"`
+	NoNestedSyncignore bool     `toml:"no_nested_syncignore"`
+	IgnoreSrc          []string `toml:"ignore_src"`
+	IgnoreDest         []string `toml:"ignore_dest"`
+	Only               []string `toml:"only"`
+	RsyncBinary        string   `toml:"rsync_binary"`
+	RsyncPath          string   `toml:"rsync_path"`
+	// PasswordFile is only the path; the password itself is never recorded
+	PasswordFile string `toml:"password_file"`
+}
+
+// LastRunPath returns the last-run state file in dir, or in the default global config directory
+func LastRunPath(dir string) (string, error) {
+	dir, err := configDir(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, lastRunFile), nil
+}
+
+// SaveLastRun records run as the most recent sync, replacing any earlier record
+func SaveLastRun(dir string, run LastRun) error {
+	path, err := LastRunPath(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Written by sync-tools after each successful sync; read by sync --repeat\n")
+	if err := toml.NewEncoder(&buf).Encode(run); err != nil {
+		return fmt.Errorf("error encoding last run: %w", err)
+	}
+
+	// Write then rename, so an interrupted write can't leave a truncated record
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("error writing last run: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing last run: %w", err)
+	}
+	return nil
+}
+
+// LoadLastRun reads the most recent sync recorded by SaveLastRun
+func LoadLastRun(dir string) (*LastRun, error) {
+	path, err := LastRunPath(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no previous sync recorded in %s; run a sync first", filepath.Dir(path))
+	}
+
+	var run LastRun
+	if _, err := toml.DecodeFile(path, &run); err != nil {
+		return nil, fmt.Errorf("error reading last run %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+// ApplyTo replaces the recorded options in cfg, so the last run takes precedence over config
+// files while command-line flags, merged afterwards, still override it
+func (run *LastRun) ApplyTo(cfg *Config) {
+	cfg.Source = run.Source
+	cfg.Dest = run.Dest
+	cfg.Mode = run.Mode
+	cfg.UseSourceGitignore = run.UseSourceGitignore
+	cfg.UseGlobalGitignore = run.UseGlobalGitignore
+	cfg.ExcludeHiddenDirs = run.ExcludeHiddenDirs
+	cfg.ExcludeVCS = run.ExcludeVCS
+	cfg.IncludeGit = run.IncludeGit
+	cfg.OnlySyncignore = run.OnlySyncignore
+	cfg.NoNestedSyncignore = run.NoNestedSyncignore
+	cfg.IgnoreSrc = run.IgnoreSrc
+	cfg.IgnoreDest = run.IgnoreDest
+	cfg.Only = run.Only
+	cfg.RsyncBinary = run.RsyncBinary
+	cfg.RsyncPath = run.RsyncPath
+	cfg.PasswordFile = run.PasswordFile
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+)
+
+// manifestsDir holds the dest manifests recorded by sync --expect-dest-clean, one per dest
+const manifestsDir = "dest-manifests"
+
+// DestManifestPath returns the manifest file for dest in dir, or in the default global config
+// directory. Dests are told apart by a hash of their absolute path.
+func DestManifestPath(dir, dest string) (string, error) {
+	dir, err := configDir(dir)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(dest))
+	return filepath.Join(dir, manifestsDir, hex.EncodeToString(sum[:8])+".json"), nil
+}
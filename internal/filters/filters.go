@@ -1,25 +1,208 @@
 package filters
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// Temp file name prefixes, shared with SweepStaleFiles. Each name goes on with the pid of
+// the process that wrote it (sync-tools-filter-<pid>-<random>.txt).
+const (
+	filterFilePrefix    = "sync-tools-filter-"
+	filesFromFilePrefix = "sync-tools-files-from-"
+)
+
+// VCSExcludeList holds the patterns --exclude-vcs adds, one per line: version control
+// metadata, editor backup and swap files, and OS clutter. Like other ignore patterns,
+// entries without a slash match at any depth.
+const VCSExcludeList = `
+.git/
+.svn/
+.hg/
+.bzr/
+_darcs/
+CVS/
+RCS/
+SCCS/
+*~
+.#*
+#*#
+*.swp
+*.swo
+.DS_Store
+._*
+Thumbs.db
+ehthumbs.db
+desktop.ini
+`
+
+// VCSExcludePatterns returns VCSExcludeList as ignore patterns
+func VCSExcludePatterns() []string {
+	return strings.Fields(VCSExcludeList)
+}
+
+// Filter is a temporary file handed to rsync: a filter file or a --files-from list.
+// A nil Filter means no file was needed, and Close is safe to call on it.
+type Filter struct {
+	path  string
+	lines []string
+}
+
+// Path returns the temp file's path, or "" when there is no file
+func (f *Filter) Path() string {
+	if f == nil {
+		return ""
+	}
+	return f.path
+}
+
+// Close removes the temp file; calling it again is a no-op
+func (f *Filter) Close() error {
+	if f == nil || f.path == "" {
+		return nil
+	}
+	err := os.Remove(f.path)
+	f.path = ""
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Restore rewrites the temp file if it was removed while still in use, e.g. swept by an
+// older sync-tools that doesn't know the pid in its name
+func (f *Filter) Restore() error {
+	if f == nil || f.path == "" {
+		return nil
+	}
+	if _, err := os.Stat(f.path); !os.IsNotExist(err) {
+		return nil
+	}
+	return os.WriteFile(f.path, []byte(strings.Join(f.lines, "\n")+"\n"), 0600)
+}
+
+// SweepStaleFiles removes sync-tools temp files older than maxAge, left behind when an
+// earlier run crashed before cleaning up, and returns how many were removed. Files whose
+// writer is still running are kept, however old, since a long sync may read them again.
+func SweepStaleFiles(maxAge time.Duration) (int, error) {
+	removed := 0
+	cutoff := time.Now().Add(-maxAge)
+	for _, prefix := range []string{filterFilePrefix, filesFromFilePrefix} {
+		matches, err := filepath.Glob(filepath.Join(os.TempDir(), prefix+"*.txt"))
+		if err != nil {
+			return removed, err
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			if pid, ok := ownerPID(filepath.Base(match), prefix); ok && processRunning(pid) {
+				continue
+			}
+			if err := os.Remove(match); err == nil {
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+// ownerPID reads the writer's pid from a temp file name; names from before pids were
+// recorded have none
+func ownerPID(name, prefix string) (int, bool) {
+	digits, _, ok := strings.Cut(strings.TrimPrefix(name, prefix), "-")
+	if !ok {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(digits)
+	return pid, err == nil && pid > 0
+}
+
 // BuildExcludeFilter creates a temporary filter file for exclude patterns
-func BuildExcludeFilter(patterns []string) (string, error) {
+func BuildExcludeFilter(patterns []string) (*Filter, error) {
+	return WriteFilterFile(ExcludeFilterLines(patterns))
+}
+
+// ExcludeFilterLines returns the rsync filter lines for exclude patterns
+func ExcludeFilterLines(patterns []string) []string {
 	if len(patterns) == 0 {
-		return "", nil
+		return nil
 	}
+	return toFilterLines(patterns)
+}
 
-	lines := toFilterLines(patterns)
-	return writeFilterFile(lines)
+// DestFilterLines returns the rsync filter lines for dest-side ignore patterns. There an
+// unignore (!keep.log) protects matching dest files, and everything inside matching
+// directories, from deletion, including dest-only files --delete would otherwise remove.
+// Protect rules follow gitignore anchoring (anchorPattern), and come first so they win.
+func DestFilterLines(patterns []string) []string {
+	var protects, excludes []string
+	for _, pattern := range expandPatterns(patterns) {
+		if !strings.HasPrefix(pattern, "!") {
+			excludes = append(excludes, pattern)
+			continue
+		}
+		base := strings.TrimSuffix(strings.TrimPrefix(pattern, "!"), "/**")
+		base = anchorPattern(strings.TrimSuffix(base, "/"))
+		protects = append(protects, "P "+base, "P "+base+"/**")
+	}
+	return append(dedupeLines(protects), ExcludeFilterLines(excludes)...)
+}
+
+// NestedExcludeLines converts the patterns of an ignore file in dir (slash-separated, relative
+// to the transfer root) into rules that only apply below dir, as git does for a nested
+// .gitignore. A pattern with a slash is relative to dir; one without matches at any depth
+// under it, which takes two rules since rsync's /**/ needs at least one directory between.
+func NestedExcludeLines(dir string, patterns []string) []string {
+	prefix := strings.TrimSuffix(anchoredDir(dir), "/")
+	var rooted []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		negated := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(strings.TrimPrefix(pattern, "!"), "**/")
+		if pattern == "" {
+			continue
+		}
+
+		mark := ""
+		if negated {
+			mark = "!"
+		}
+		// Unignores are anchored, as they are in a top-level .syncignore
+		anchored := anchorPattern(pattern)
+		if strings.HasPrefix(anchored, "/") || negated {
+			rooted = append(rooted, mark+prefix+ensureSlashPrefix(anchored))
+			continue
+		}
+		rooted = append(rooted, mark+prefix+"/"+anchored, mark+prefix+"/**/"+anchored)
+	}
+	return toFilterLines(rooted)
+}
+
+// MaxDepthLines returns rules that skip every path more than depth levels below the transfer
+// root. rsync has no depth limit, but excluding a directory prunes its descent. The matching
+// protect rule keeps --delete-excluded from wiping the deeper dest content.
+func MaxDepthLines(depth int) []string {
+	pattern := "/" + strings.Repeat("*/", depth) + "*"
+	return []string{"- " + pattern, "P " + pattern}
 }
 
 // BuildOnlyFilter creates a temporary filter file for whitelist (only) mode
-func BuildOnlyFilter(onlyPatterns []string) (string, error) {
+func BuildOnlyFilter(onlyPatterns []string) (*Filter, error) {
+	return WriteFilterFile(OnlyFilterLines(onlyPatterns))
+}
+
+// OnlyFilterLines returns the rsync filter lines for whitelist (only) mode
+func OnlyFilterLines(onlyPatterns []string) []string {
 	if len(onlyPatterns) == 0 {
-		return "", nil
+		return nil
 	}
 
 	var lines []string
@@ -28,41 +211,42 @@ func BuildOnlyFilter(onlyPatterns []string) (string, error) {
 	// 1. Include parent directories so rsync can traverse to the target
 	// 2. Include the pattern itself and its recursive contents
 	// 3. Exclude everything else
-	for _, pattern := range onlyPatterns {
-		pattern = strings.TrimSpace(pattern)
-		if pattern == "" {
-			continue
-		}
-
+	for _, pattern := range expandPatterns(onlyPatterns) {
 		// Clean up pattern (remove leading ./ and trailing /**)
 		pattern = strings.TrimPrefix(pattern, "./")
 		pattern = strings.TrimSuffix(pattern, "/**")
 		pattern = strings.TrimSuffix(pattern, "/")
 
-		// Ensure pattern starts with /
-		if !strings.HasPrefix(pattern, "/") {
-			pattern = "/" + pattern
-		}
-
-		// Include root directory
-		lines = append(lines, "+ /")
-
-		// Include parent directories
-		parts := strings.Split(strings.Trim(pattern, "/"), "/")
-		path := ""
-		for _, part := range parts {
-			path += "/" + part
-			lines = append(lines, fmt.Sprintf("+ %s", path))
-		}
-
-		// Include the pattern and all its contents recursively
-		lines = append(lines, fmt.Sprintf("+ %s/**", pattern))
+		lines = append(lines, includeLines(ensureSlashPrefix(pattern))...)
 	}
 
+	// Overlapping patterns (docs/ and docs/api/) share parent includes; emit each line once
+	lines = dedupeLines(lines)
+
 	// Exclude everything else
 	lines = append(lines, "- *")
 
-	return writeFilterFile(lines)
+	return lines
+}
+
+// BuildFilesFromList copies a newline-delimited file list into a temporary file for rsync --files-from
+func BuildFilesFromList(reader io.Reader) (*Filter, error) {
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file list: %w", err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("file list is empty")
+	}
+
+	return writeTempFile(filesFromFilePrefix, lines)
 }
 
 // toFilterLines converts patterns to rsync filter lines
@@ -70,32 +254,13 @@ func toFilterLines(patterns []string) []string {
 	var includes []string
 	var excludes []string
 
-	for _, pattern := range patterns {
-		pattern = strings.TrimSpace(pattern)
-		if pattern == "" {
-			continue
-		}
-
+	for _, pattern := range expandPatterns(patterns) {
 		if strings.HasPrefix(pattern, "!") {
 			// Unignore pattern - convert to include rules
 			base := strings.TrimPrefix(pattern, "!")
 			base = strings.TrimSuffix(base, "/**")
 			base = strings.TrimSuffix(base, "/")
-			base = ensureSlashPrefix(base)
-
-			// Include root directory
-			includes = append(includes, "+ /")
-
-			// Include parent directories
-			parts := strings.Split(strings.Trim(base, "/"), "/")
-			path := ""
-			for _, part := range parts {
-				path += "/" + part
-				includes = append(includes, fmt.Sprintf("+ %s", path))
-			}
-
-			// Include the pattern and all its contents recursively
-			includes = append(includes, fmt.Sprintf("+ %s/**", base))
+			includes = append(includes, includeLines(ensureSlashPrefix(base))...)
 		} else {
 			// Regular exclude pattern
 			excludes = append(excludes, fmt.Sprintf("- %s", pattern))
@@ -104,12 +269,161 @@ func toFilterLines(patterns []string) []string {
 
 	// Combine includes first, then excludes (order matters for rsync)
 	var lines []string
-	lines = append(lines, includes...)
-	lines = append(lines, excludes...)
+	lines = append(lines, dedupeLines(includes)...)
+	lines = append(lines, dedupeLines(excludes)...)
 
 	return lines
 }
 
+// dedupeLines drops repeated filter lines, keeping the first occurrence so rule order is preserved
+func dedupeLines(lines []string) []string {
+	seen := make(map[string]bool, len(lines))
+	var unique []string
+	for _, line := range lines {
+		if !seen[line] {
+			seen[line] = true
+			unique = append(unique, line)
+		}
+	}
+	return unique
+}
+
+// anchorPattern applies gitignore anchoring to an ignore pattern. A pattern with a slash
+// at the start or in the middle (/temp, docs/tmp) is relative to the root, while one without
+// (temp, build/) matches at any depth. Patterns starting with **/ already match anywhere.
+// Top-level excludes don't use it: they keep rsync's meaning, where docs/tmp also matches
+// a/docs/tmp, so existing ignore files match what they always have.
+func anchorPattern(pattern string) string {
+	if strings.HasPrefix(pattern, "/") || strings.HasPrefix(pattern, "**/") {
+		return pattern
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, "/"), "/") {
+		return "/" + pattern
+	}
+	return pattern
+}
+
+// RelativeLines re-anchors filter lines for rsync --relative, which matches anchored rules
+// against the whole recreated path rather than the source root. Rules starting with / are
+// moved under root (a slash-separated path such as data/app), and root and its parent
+// directories are included first so no unanchored rule like "- *" can prune them.
+func RelativeLines(lines []string, root string) []string {
+	if root == "" || len(lines) == 0 {
+		return lines
+	}
+
+	prefix := ""
+	var result []string
+	for _, part := range strings.Split(root, "/") {
+		prefix += "/" + escapeWildcards(part)
+		result = append(result, fmt.Sprintf("+ %s/", prefix))
+	}
+	for _, line := range lines {
+		if len(line) > 2 && line[1] == ' ' && line[2] == '/' {
+			line = line[:2] + prefix + line[2:]
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// KeepDirLines returns rules that stop rsync --prune-empty-dirs from pruning the given
+// directories (slash-separated, relative to the transfer root). rsync honors a protect rule
+// when deciding what to prune.
+func KeepDirLines(dirs []string) []string {
+	lines := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		lines = append(lines, "P "+anchoredDir(dir))
+	}
+	return lines
+}
+
+// ExcludeDirLines returns rules excluding exactly the given directories (slash-separated,
+// relative to the transfer root), however their names would otherwise match as patterns
+func ExcludeDirLines(dirs []string) []string {
+	lines := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		lines = append(lines, "- "+anchoredDir(dir))
+	}
+	return lines
+}
+
+// SkipLines returns rules leaving the given files (slash-separated, relative to the transfer
+// root) out of a transfer entirely: hidden from the sender, and protected on the receiver so
+// --delete-excluded doesn't remove them
+func SkipLines(paths []string) []string {
+	lines := make([]string, 0, 2*len(paths))
+	for _, path := range paths {
+		anchored := strings.TrimSuffix(anchoredDir(path), "/")
+		lines = append(lines, "P "+anchored, "- "+anchored)
+	}
+	return lines
+}
+
+// anchoredDir turns a relative directory path into an anchored rsync pattern matching only it
+func anchoredDir(dir string) string {
+	var escaped []string
+	for _, part := range strings.Split(dir, "/") {
+		escaped = append(escaped, escapeWildcards(part))
+	}
+	return "/" + strings.Join(escaped, "/") + "/"
+}
+
+// escapeWildcards escapes rsync's wildcard characters so name matches literally
+func escapeWildcards(name string) string {
+	var sb strings.Builder
+	for _, c := range name {
+		if c == '*' || c == '?' || c == '[' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// CaseInsensitiveLines rewrites the patterns in "+"/"-" filter lines to match letters in
+// either case. rsync has no case-insensitive matching, so each letter outside a [...] class
+// becomes a class of both cases (*.JPG matches *.jpg as *.[jJ][pP][gG]).
+func CaseInsensitiveLines(lines []string) []string {
+	result := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if len(line) > 2 && (line[0] == '+' || line[0] == '-') && line[1] == ' ' {
+			line = line[:2] + caseInsensitivePattern(line[2:])
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// caseInsensitivePattern expands each letter into a [xX] class, leaving escapes and
+// existing classes as written
+func caseInsensitivePattern(pattern string) string {
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			sb.WriteByte(c)
+			sb.WriteByte(pattern[i+1])
+			i++
+		case c == '[':
+			end := strings.IndexByte(pattern[i+1:], ']')
+			if end < 0 {
+				sb.WriteByte(c)
+				continue
+			}
+			sb.WriteString(pattern[i : i+end+2])
+			i += end + 1
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+			lower, upper := strings.ToLower(string(c)), strings.ToUpper(string(c))
+			sb.WriteString("[" + lower + upper + "]")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
 // ensureSlashPrefix ensures the path starts with /
 func ensureSlashPrefix(path string) string {
 	if !strings.HasPrefix(path, "/") {
@@ -118,16 +432,22 @@ func ensureSlashPrefix(path string) string {
 	return path
 }
 
-// writeFilterFile writes filter lines to a temporary file and returns the filename
-func writeFilterFile(lines []string) (string, error) {
+// WriteFilterFile writes filter lines to a temporary file, returning nil when there are no lines.
+// Callers must Close the returned Filter once rsync has finished with it.
+func WriteFilterFile(lines []string) (*Filter, error) {
+	return writeTempFile(filterFilePrefix, lines)
+}
+
+// writeTempFile writes lines to a temporary file named with prefix and this process's pid
+func writeTempFile(prefix string, lines []string) (*Filter, error) {
 	if len(lines) == 0 {
-		return "", nil
+		return nil, nil
 	}
 
 	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "sync-tools-filter-*.txt")
+	tmpFile, err := os.CreateTemp("", fmt.Sprintf("%s%d-*.txt", prefix, os.Getpid()))
 	if err != nil {
-		return "", fmt.Errorf("failed to create temp filter file: %w", err)
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer tmpFile.Close()
 
@@ -135,9 +455,9 @@ func writeFilterFile(lines []string) (string, error) {
 	for _, line := range lines {
 		if _, err := fmt.Fprintln(tmpFile, line); err != nil {
 			os.Remove(tmpFile.Name()) // Cleanup on error
-			return "", fmt.Errorf("failed to write to temp filter file: %w", err)
+			return nil, fmt.Errorf("failed to write to temp file: %w", err)
 		}
 	}
 
-	return tmpFile.Name(), nil
+	return &Filter{path: tmpFile.Name(), lines: lines}, nil
 }
\ No newline at end of file
@@ -0,0 +1,466 @@
+package filters
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOnlyFilterLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name:     "single directory",
+			patterns: []string{"docs"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "- *"},
+		},
+		{
+			name:     "trailing slash and leading ./ are normalized",
+			patterns: []string{"./docs/"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "- *"},
+		},
+		{
+			name:     "recursive suffix is normalized",
+			patterns: []string{"docs/**"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "- *"},
+		},
+		{
+			name:     "overlapping patterns share parent includes",
+			patterns: []string{"docs/", "docs/api/"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "+ /docs/api", "+ /docs/api/**", "- *"},
+		},
+		{
+			name:     "sibling patterns keep both subtrees",
+			patterns: []string{"src/a", "src/b"},
+			want:     []string{"+ /", "+ /src", "+ /src/a", "+ /src/a/**", "+ /src/b", "+ /src/b/**", "- *"},
+		},
+		{
+			name:     "blank patterns are skipped",
+			patterns: []string{"  ", "docs"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "- *"},
+		},
+		{
+			name:     "no patterns",
+			patterns: nil,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OnlyFilterLines(tt.patterns)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OnlyFilterLines(%q) =\n%s\nwant\n%s", tt.patterns, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestExcludeFilterLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name:     "plain excludes keep their order",
+			patterns: []string{"*.tmp", "build/"},
+			want:     []string{"- *.tmp", "- build/"},
+		},
+		{
+			name:     "unignore rules become includes ahead of excludes",
+			patterns: []string{"build/", "!build/keep.txt"},
+			want:     []string{"+ /", "+ /build", "+ /build/keep.txt", "+ /build/keep.txt/**", "- build/"},
+		},
+		{
+			name:     "overlapping unignores are deduplicated",
+			patterns: []string{"*", "!docs/", "!docs/api/"},
+			want:     []string{"+ /", "+ /docs", "+ /docs/**", "+ /docs/api", "+ /docs/api/**", "- *"},
+		},
+		{
+			name:     "only a leading slash anchors a pattern",
+			patterns: []string{"docs/tmp", "/temp", "temp", "**/cache/data"},
+			want:     []string{"- docs/tmp", "- /temp", "- temp", "- **/cache/data"},
+		},
+		{
+			name:     "repeated excludes are deduplicated",
+			patterns: []string{"*.log", "*.log"},
+			want:     []string{"- *.log"},
+		},
+		{
+			name:     "no patterns",
+			patterns: nil,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExcludeFilterLines(tt.patterns)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExcludeFilterLines(%q) =\n%s\nwant\n%s", tt.patterns, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestDestFilterLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{
+			name:     "plain dest ignores are excludes",
+			patterns: []string{"cache/", "*.log"},
+			want:     []string{"- cache/", "- *.log"},
+		},
+		{
+			name:     "unignores protect the dest copy ahead of the excludes",
+			patterns: []string{"*.log", "!keep-this.log"},
+			want:     []string{"P keep-this.log", "P keep-this.log/**", "- *.log"},
+		},
+		{
+			name:     "protects follow gitignore anchoring",
+			patterns: []string{"!/local.conf", "!notes/private/", "!{a,b}.txt"},
+			want:     []string{"P /local.conf", "P /local.conf/**", "P /notes/private", "P /notes/private/**", "P a.txt", "P a.txt/**", "P b.txt", "P b.txt/**"},
+		},
+		{
+			name:     "no patterns",
+			patterns: nil,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DestFilterLines(tt.patterns)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("DestFilterLines(%q) =\n%s\nwant\n%s", tt.patterns, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestBuildOnlyFilterWritesExactContents(t *testing.T) {
+	filter, err := BuildOnlyFilter([]string{"docs/", "docs/api/"})
+	if err != nil {
+		t.Fatalf("BuildOnlyFilter: %v", err)
+	}
+	defer filter.Close()
+
+	data, err := os.ReadFile(filter.Path())
+	if err != nil {
+		t.Fatalf("reading filter file: %v", err)
+	}
+
+	want := "+ /\n+ /docs\n+ /docs/**\n+ /docs/api\n+ /docs/api/**\n- *\n"
+	if string(data) != want {
+		t.Errorf("filter file contents =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestOnlyFilterKeepsOverlappingPaths(t *testing.T) {
+	rules := ParseRules(OnlyFilterLines([]string{"docs/", "docs/api/"}))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"docs", true, true},
+		{"docs/readme.md", false, true},
+		{"docs/api", true, true},
+		{"docs/api/index.md", false, true},
+		{"src/main.go", false, false},
+		{"notes.txt", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(rules, tt.path, tt.isDir); got.Included != tt.want {
+			t.Errorf("Match(%q) included = %v, want %v (%s)", tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestExcludeAnchoring(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Without a slash the pattern matches at any depth
+		{"temp", "temp", false},
+		{"temp", "a/temp", false},
+		// A leading slash anchors it to the root
+		{"/temp", "temp", false},
+		{"/temp", "a/temp", true},
+		// A slash in the middle keeps rsync's meaning, so existing ignore files still match
+		// the pattern below the root
+		{"docs/tmp", "docs/tmp", false},
+		{"docs/tmp", "a/docs/tmp", false},
+		{"/docs/tmp", "a/docs/tmp", true},
+	}
+
+	for _, tt := range tests {
+		rules := ParseRules(ExcludeFilterLines([]string{tt.pattern}))
+		if got := Match(rules, tt.path, false); got.Included != tt.want {
+			t.Errorf("pattern %q, Match(%q) included = %v, want %v (%s)", tt.pattern, tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestVCSExcludePatterns(t *testing.T) {
+	rules := ParseRules(ExcludeFilterLines(VCSExcludePatterns()))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{".git", true, false},
+		{"vendor/lib/.git", true, false},
+		{".svn/entries", false, false},
+		{"CVS", true, false},
+		{"notes.txt~", false, false},
+		{"src/.main.go.swp", false, false},
+		{"#draft.md#", false, false},
+		{"photos/.DS_Store", false, false},
+		{"._cover.jpg", false, false},
+		{".gitignore", false, true},
+		{"src/main.go", false, true},
+		// VCS names only match directories
+		{"CVS", false, true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(rules, tt.path, tt.isDir); got.Included != tt.want {
+			t.Errorf("Match(%q) included = %v, want %v (%s)", tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestCaseInsensitiveLines(t *testing.T) {
+	got := CaseInsensitiveLines([]string{"- *.JPG", "+ /docs/**", "- file[0-9].txt", "- a\\*b", "P /*/*"})
+	want := []string{"- *.[jJ][pP][gG]", "+ /[dD][oO][cC][sS]/**", "- [fF][iI][lL][eE][0-9].[tT][xX][tT]", "- [aA]\\*[bB]", "P /*/*"}
+	if !slices.Equal(got, want) {
+		t.Errorf("CaseInsensitiveLines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	rules := ParseRules(CaseInsensitiveLines(ExcludeFilterLines([]string{"*.jpg"})))
+	for _, path := range []string{"photo.jpg", "photo.JPG", "photo.Jpg"} {
+		if Match(rules, path, false).Included {
+			t.Errorf("Match(%q) included, want excluded by the case-insensitive rule", path)
+		}
+	}
+}
+
+func TestRelativeLines(t *testing.T) {
+	got := RelativeLines([]string{"+ /", "+ /docs/**", "- *", "P /*/*"}, "data/app[1]")
+	want := []string{"+ /data/", "+ /data/app\\[1]/", "+ /data/app\\[1]/", "+ /data/app\\[1]/docs/**", "- *", "P /data/app\\[1]/*/*"}
+	if !slices.Equal(got, want) {
+		t.Errorf("RelativeLines =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if got := RelativeLines([]string{"- /.git/"}, ""); !slices.Equal(got, []string{"- /.git/"}) {
+		t.Errorf("RelativeLines with no root = %q, want the lines unchanged", got)
+	}
+
+	// Paths are now matched from the top of the recreated tree
+	rules := ParseRules(RelativeLines(OnlyFilterLines([]string{"docs/"}), "data/app"))
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"data", true, true},
+		{"data/app", true, true},
+		{"data/app/docs/readme.md", false, true},
+		{"data/app/src/main.go", false, false},
+		{"docs/readme.md", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(rules, tt.path, tt.isDir); got.Included != tt.want {
+			t.Errorf("Match(%q) included = %v, want %v (%s)", tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestKeepDirLines(t *testing.T) {
+	got := KeepDirLines([]string{"cache", "logs/app[1]"})
+	want := []string{"P /cache/", "P /logs/app\\[1]/"}
+	if !slices.Equal(got, want) {
+		t.Errorf("KeepDirLines = %q, want %q", got, want)
+	}
+}
+
+func TestExcludeDirLines(t *testing.T) {
+	got := ExcludeDirLines([]string{"scratch", "photos/raw*"})
+	want := []string{"- /scratch/", "- /photos/raw\\*/"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ExcludeDirLines = %q, want %q", got, want)
+	}
+}
+
+func TestNestedExcludeLines(t *testing.T) {
+	got := NestedExcludeLines("docs/drafts", []string{"*.tmp", "/build", "cache/", "img/raw", "**/*.bak", "!keep.tmp"})
+	want := []string{
+		"+ /",
+		"+ /docs",
+		"+ /docs/drafts",
+		"+ /docs/drafts/keep.tmp",
+		"+ /docs/drafts/keep.tmp/**",
+		"- /docs/drafts/*.tmp",
+		"- /docs/drafts/**/*.tmp",
+		"- /docs/drafts/build",
+		"- /docs/drafts/cache/",
+		"- /docs/drafts/**/cache/",
+		"- /docs/drafts/img/raw",
+		"- /docs/drafts/*.bak",
+		"- /docs/drafts/**/*.bak",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("NestedExcludeLines = %q, want %q", got, want)
+	}
+}
+
+func TestSkipLines(t *testing.T) {
+	got := SkipLines([]string{"docs/notes.txt"})
+	want := []string{"P /docs/notes.txt", "- /docs/notes.txt"}
+	if !slices.Equal(got, want) {
+		t.Errorf("SkipLines = %q, want %q", got, want)
+	}
+}
+
+func TestMaxDepthLines(t *testing.T) {
+	if got, want := MaxDepthLines(2), []string{"- /*/*/*", "P /*/*/*"}; !slices.Equal(got, want) {
+		t.Errorf("MaxDepthLines(2) = %q, want %q", got, want)
+	}
+
+	// Depth rules go first so whitelist includes can't pull deeper paths back in
+	rules := ParseRules(append(MaxDepthLines(2), OnlyFilterLines([]string{"docs/"})...))
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"docs", true, true},
+		{"docs/readme.md", false, true},
+		{"docs/api", true, true},
+		{"docs/api/index.md", false, false},
+		{"docs/api/v1/index.md", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(rules, tt.path, tt.isDir); got.Included != tt.want {
+			t.Errorf("Match(%q) included = %v, want %v (%s)", tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestFilterCloseRemovesFile(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	filter, err := WriteFilterFile([]string{"- *.tmp"})
+	if err != nil {
+		t.Fatalf("WriteFilterFile: %v", err)
+	}
+	path := filter.Path()
+
+	if err := filter.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("filter file %s still exists after Close", path)
+	}
+	if err := filter.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
+
+func TestNilFilter(t *testing.T) {
+	filter, err := WriteFilterFile(nil)
+	if err != nil {
+		t.Fatalf("WriteFilterFile: %v", err)
+	}
+	if filter.Path() != "" {
+		t.Errorf("Path() = %q, want empty for no lines", filter.Path())
+	}
+	if err := filter.Close(); err != nil {
+		t.Errorf("Close on nil filter = %v, want nil", err)
+	}
+}
+
+func TestSweepStaleFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	stale := filepath.Join(tmpDir, "sync-tools-filter-stale.txt")
+	// Written by a process that has since exited
+	orphaned := filepath.Join(tmpDir, "sync-tools-filter-99999999-abc.txt")
+	// Still in use by a running sync, however old
+	live := filepath.Join(tmpDir, fmt.Sprintf("sync-tools-files-from-%d-abc.txt", os.Getpid()))
+	fresh := filepath.Join(tmpDir, "sync-tools-files-from-fresh.txt")
+	unrelated := filepath.Join(tmpDir, "other-tool-old.txt")
+	for _, path := range []string{stale, orphaned, live, fresh, unrelated} {
+		if err := os.WriteFile(path, []byte("- *\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	for _, path := range []string{stale, orphaned, live, unrelated} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := SweepStaleFiles(time.Hour)
+	if err != nil {
+		t.Fatalf("SweepStaleFiles: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	for _, path := range []string{stale, orphaned} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", filepath.Base(path))
+		}
+	}
+	for _, path := range []string{live, fresh, unrelated} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should have been kept: %v", filepath.Base(path), err)
+		}
+	}
+}
+
+func TestFilterRestore(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	filter, err := WriteFilterFile([]string{"- *.tmp", "+ /docs/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer filter.Close()
+	if !strings.Contains(filepath.Base(filter.Path()), fmt.Sprintf("-%d-", os.Getpid())) {
+		t.Errorf("filter file %s doesn't carry the pid", filter.Path())
+	}
+
+	if err := os.Remove(filter.Path()); err != nil {
+		t.Fatal(err)
+	}
+	if err := filter.Restore(); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	data, err := os.ReadFile(filter.Path())
+	if err != nil || string(data) != "- *.tmp\n+ /docs/\n" {
+		t.Errorf("restored filter file = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,112 @@
+package filters
+
+import (
+	"strings"
+)
+
+// ExpandBraces expands shell-style brace alternatives, so "{src,test}/*.go" becomes
+// "src/*.go" and "test/*.go". Groups may nest and repeat. rsync has no braces, so patterns
+// are expanded before they become filter rules. A group without a comma, an unclosed brace,
+// and a brace escaped with a backslash are kept as written.
+func ExpandBraces(pattern string) []string {
+	open, close, alternatives := braceGroup(pattern)
+	if open < 0 {
+		return []string{pattern}
+	}
+
+	prefix, suffix := pattern[:open], pattern[close+1:]
+	var expanded []string
+	for _, alternative := range alternatives {
+		expanded = append(expanded, ExpandBraces(prefix+alternative+suffix)...)
+	}
+	return expanded
+}
+
+// braceGroup finds the first brace group with a top-level comma, returning the positions of
+// its braces and its alternatives, or -1 when there is none
+func braceGroup(pattern string) (int, int, []string) {
+	for start := 0; start < len(pattern); start++ {
+		if pattern[start] == '\\' {
+			start++
+			continue
+		}
+		if pattern[start] != '{' {
+			continue
+		}
+
+		// {a} and unclosed braces are literal, but a group inside them may still expand
+		depth, last := 1, start+1
+		var alternatives []string
+	scan:
+		for i := start + 1; i < len(pattern); i++ {
+			switch pattern[i] {
+			case '\\':
+				i++
+			case '{':
+				depth++
+			case ',':
+				if depth == 1 {
+					alternatives = append(alternatives, pattern[last:i])
+					last = i + 1
+				}
+			case '}':
+				if depth--; depth == 0 {
+					if alternatives != nil {
+						return start, i, append(alternatives, pattern[last:i])
+					}
+					break scan
+				}
+			}
+		}
+	}
+	return -1, -1, nil
+}
+
+// hasWildcard reports whether pattern uses any of rsync's wildcards
+func hasWildcard(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// includeLines returns the rules including an anchored path (/docs) or glob (/src/**/*.go)
+// and everything inside what it matches, plus the directories leading to it, so rsync can
+// reach it past a final "- *". A wildcard directory includes every directory it matches,
+// and ** every directory below it; rsync can only prune those that turn out empty with
+// --prune-empty-dirs. Since rsync's /**/ needs at least one directory, a pattern using it
+// also gets the variant matching directly in the parent (src/*.go for src/**/*.go).
+func includeLines(pattern string) []string {
+	lines := []string{"+ /"}
+	parts := strings.Split(strings.Trim(pattern, "/"), "/")
+
+	// Directories leading to the final part
+	path := ""
+	for _, part := range parts[:len(parts)-1] {
+		if part == "**" {
+			lines = append(lines, "+ "+path+"/**/")
+			break
+		}
+		path += "/" + part
+		if hasWildcard(part) {
+			lines = append(lines, "+ "+path+"/")
+		} else {
+			lines = append(lines, "+ "+path)
+		}
+	}
+
+	full := "/" + strings.Join(parts, "/")
+	lines = append(lines, "+ "+full, "+ "+full+"/**")
+	if direct := strings.ReplaceAll(full, "/**/", "/"); direct != full {
+		lines = append(lines, "+ "+direct, "+ "+direct+"/**")
+	}
+	return lines
+}
+
+// expandPatterns trims the patterns, drops blank ones, and expands their braces
+func expandPatterns(patterns []string) []string {
+	var expanded []string
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			expanded = append(expanded, ExpandBraces(pattern)...)
+		}
+	}
+	return expanded
+}
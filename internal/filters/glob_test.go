@@ -0,0 +1,101 @@
+package filters
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.go", []string{"*.go"}},
+		{"{a,b}/*.txt", []string{"a/*.txt", "b/*.txt"}},
+		{"*.{jpg,png}", []string{"*.jpg", "*.png"}},
+		{"{a,b}/{x,y}", []string{"a/x", "a/y", "b/x", "b/y"}},
+		{"src/{app,lib/{core,util}}", []string{"src/app", "src/lib/core", "src/lib/util"}},
+		{"{,.}config", []string{"config", ".config"}},
+		{"{solo}/x", []string{"{solo}/x"}},
+		{"{solo,{a,b}}", []string{"solo", "a", "b"}},
+		{"{open/x", []string{"{open/x"}},
+		{`\{a,b}`, []string{`\{a,b}`}},
+	}
+	for _, tt := range tests {
+		if got := ExpandBraces(tt.pattern); !slices.Equal(got, tt.want) {
+			t.Errorf("ExpandBraces(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestOnlyFilterGlobs(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		// Patterns are relative to the source root, so *.go only matches there
+		{"*.go", "main.go", false, true},
+		{"*.go", "README.md", false, false},
+		{"*.go", "cmd/tool.go", false, false},
+
+		// ** matches any number of directories, including none
+		{"src/**/*.go", "src/main.go", false, true},
+		{"src/**/*.go", "src/pkg/util/util.go", false, true},
+		{"src/**/*.go", "src/pkg", true, true},
+		{"src/**/*.go", "src/pkg/README.md", false, false},
+		{"src/**/*.go", "src/notes.txt", false, false},
+		{"src/**/*.go", "test/main_test.go", false, false},
+		{"**/*.go", "main.go", false, true},
+		{"**/*.go", "a/b/c.go", false, true},
+		{"**/*.go", "a/b/c.txt", false, false},
+
+		// Braces expand into one pattern per alternative
+		{"{a,b}/*.txt", "a/one.txt", false, true},
+		{"{a,b}/*.txt", "b/two.txt", false, true},
+		{"{a,b}/*.txt", "c/three.txt", false, false},
+		{"{a,b}/*.txt", "a/one.md", false, false},
+		{"{a,b}/*.txt", "a/deeper/one.txt", false, false},
+
+		// A wildcard directory brings its contents, as a plain directory does
+		{"docs/*", "docs/api/index.md", false, true},
+		{"pkg/*/testdata", "pkg/parser/testdata/case1.txt", false, true},
+		{"pkg/*/testdata", "pkg/parser/parser.go", false, false},
+	}
+
+	for _, tt := range tests {
+		rules := ParseRules(OnlyFilterLines([]string{tt.pattern}))
+		if got := Match(rules, tt.path, tt.isDir); got.Included != tt.want {
+			t.Errorf("--only %q: Match(%q) included = %v, want %v (%s)", tt.pattern, tt.path, got.Included, tt.want, got)
+		}
+	}
+}
+
+func TestOnlyFilterGlobLines(t *testing.T) {
+	got := OnlyFilterLines([]string{"src/**/*.go"})
+	want := []string{"+ /", "+ /src", "+ /src/**/", "+ /src/**/*.go", "+ /src/**/*.go/**", "+ /src/*.go", "+ /src/*.go/**", "- *"}
+	if !slices.Equal(got, want) {
+		t.Errorf("OnlyFilterLines(src/**/*.go) = %q, want %q", got, want)
+	}
+}
+
+func TestIgnoreBraces(t *testing.T) {
+	rules := ParseRules(ExcludeFilterLines([]string{"*.{tmp,bak}", "build/*", "!build/{keep,also}.txt"}))
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"notes.tmp", false},
+		{"docs/old.bak", false},
+		{"notes.txt", true},
+		{"build/keep.txt", true},
+		{"build/also.txt", true},
+		{"build/out.o", false},
+	}
+	for _, tt := range tests {
+		if got := Match(rules, tt.path, false); got.Included != tt.want {
+			t.Errorf("Match(%q) included = %v, want %v (%s)", tt.path, got.Included, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package filters
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Rule is a single parsed rsync include/exclude filter rule
+type Rule struct {
+	Include bool
+	Pattern string
+	Line    string
+
+	dirOnly bool
+	regex   *regexp.Regexp
+	useBase bool
+}
+
+// Decision records whether a path is transferred and which rule decided it
+type Decision struct {
+	Included bool
+	// Rule is the deciding rule, or nil when no rule matched (rsync includes by default)
+	Rule *Rule
+	// Parent is set when the path was excluded because an ancestor directory was excluded
+	Parent string
+}
+
+// ParseRules parses "+ pattern" / "- pattern" filter lines, skipping merge and unsupported rules
+func ParseRules(lines []string) []Rule {
+	var rules []Rule
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) < 3 || line[1] != ' ' || (line[0] != '+' && line[0] != '-') {
+			continue
+		}
+		rule := Rule{
+			Include: line[0] == '+',
+			Pattern: line[2:],
+			Line:    line,
+		}
+		if compileRule(&rule) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// compileRule translates the rule's rsync pattern into a regexp, reporting false for empty patterns
+func compileRule(rule *Rule) bool {
+	pattern := rule.Pattern
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		// "+ /" only names the transfer root, which is never tested
+		return false
+	}
+
+	// "dir/***" matches the directory itself as well as everything inside it
+	body := pattern
+	suffix := ""
+	if strings.HasSuffix(body, "/***") {
+		body = strings.TrimSuffix(body, "/***")
+		suffix = "(/.*)?"
+	}
+
+	// Patterns without a slash or ** only match the final path component
+	rule.useBase = !anchored && !strings.Contains(pattern, "/") && !strings.Contains(pattern, "**")
+
+	prefix := "^"
+	if !anchored && !rule.useBase {
+		prefix = "^(?:.*/)?"
+	}
+	rule.regex = regexp.MustCompile(prefix + globToRegexp(body) + suffix + "$")
+	return true
+}
+
+// globToRegexp converts rsync wildcards (*, **, ?, [...]) into regexp syntax
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(regexp.QuoteMeta("["))
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				sb.WriteString(regexp.QuoteMeta(string(glob[i+1])))
+				i++
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// matches reports whether the rule applies to a slash-separated path relative to the transfer root
+func (rule *Rule) matches(relPath string, isDir bool) bool {
+	if rule.dirOnly && !isDir {
+		return false
+	}
+	if rule.useBase {
+		return rule.regex.MatchString(path.Base(relPath))
+	}
+	return rule.regex.MatchString(relPath)
+}
+
+// firstMatch returns the first rule matching the path, mirroring rsync's first-match-wins order
+func firstMatch(rules []Rule, relPath string, isDir bool) *Rule {
+	for i := range rules {
+		if rules[i].matches(relPath, isDir) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// Match decides whether rsync would transfer relPath under the given rules. Like rsync, an
+// excluded directory hides everything beneath it, so each ancestor is checked first.
+func Match(rules []Rule, relPath string, isDir bool) Decision {
+	relPath = strings.Trim(path.Clean("/"+relPath), "/")
+	parts := strings.Split(relPath, "/")
+
+	for i := 1; i < len(parts); i++ {
+		parent := strings.Join(parts[:i], "/")
+		if rule := firstMatch(rules, parent, true); rule != nil && !rule.Include {
+			return Decision{Included: false, Rule: rule, Parent: parent}
+		}
+	}
+
+	rule := firstMatch(rules, relPath, isDir)
+	if rule == nil {
+		return Decision{Included: true}
+	}
+	return Decision{Included: rule.Include, Rule: rule}
+}
+
+// String renders the decision for display, e.g. "excluded by *.tmp"
+func (d Decision) String() string {
+	verb := "included"
+	if !d.Included {
+		verb = "excluded"
+	}
+	if d.Rule == nil {
+		return verb + " (no rule matched)"
+	}
+	if d.Parent != "" {
+		return verb + " by " + d.Rule.Pattern + " (parent directory " + d.Parent + ")"
+	}
+	return verb + " by " + d.Rule.Pattern
+}
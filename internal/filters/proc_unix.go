@@ -0,0 +1,14 @@
+//go:build !windows
+
+package filters
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processRunning reports whether a process with this pid exists; signal 0 only checks
+func processRunning(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,16 @@
+//go:build windows
+
+package filters
+
+import "os"
+
+// processRunning reports whether a process with this pid exists; on Windows finding it
+// opens a handle, which fails once the process is gone
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	p.Release()
+	return true
+}
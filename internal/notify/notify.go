@@ -0,0 +1,129 @@
+// Package notify tells a webhook or the desktop that a sync has finished
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// Desktop is the --notify target that shows a native desktop notification
+const Desktop = "desktop"
+
+// webhookTimeout bounds the POST, so an unreachable endpoint can't hang the run
+const webhookTimeout = 10 * time.Second
+
+// Notification summarizes a finished sync, or every operation of a SyncFile
+type Notification struct {
+	// Text is a one-line summary; Slack-style incoming webhooks display this field
+	Text       string              `json:"text"`
+	Success    bool                `json:"success"`
+	DurationMs int64               `json:"duration_ms"`
+	Runs       []rsync.StatsRecord `json:"runs"`
+}
+
+// New builds the notification for runs that together started at start and ended with runErr.
+// subject names what ran, e.g. "/src -> /dst" or "SyncFile ./SyncFile".
+func New(subject string, runs []rsync.StatsRecord, start time.Time, runErr error) Notification {
+	n := Notification{
+		Success:    runErr == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+		Runs:       runs,
+	}
+	if runs == nil {
+		n.Runs = []rsync.StatsRecord{}
+	}
+
+	elapsed := time.Duration(n.DurationMs) * time.Millisecond
+	if runErr != nil {
+		n.Text = fmt.Sprintf("sync-tools: %s failed after %s: %v", subject, elapsed, runErr)
+		return n
+	}
+
+	var created, updated, deleted int
+	for _, run := range runs {
+		created += run.FilesCreated
+		updated += run.FilesUpdated
+		deleted += run.FilesDeleted
+	}
+	n.Text = fmt.Sprintf("sync-tools: %s finished in %s: %d created, %d updated, %d deleted",
+		subject, elapsed, created, updated, deleted)
+	return n
+}
+
+// Validate checks a --notify target: "desktop" or an http(s) webhook URL
+func Validate(target string) error {
+	if target == "" || target == Desktop {
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid --notify: %s (must be 'desktop' or an http(s) webhook URL)", target)
+	}
+	return nil
+}
+
+// Send delivers n to target, posting it as JSON to a webhook URL or showing it on the desktop
+func Send(target string, n Notification) error {
+	if target == Desktop {
+		return showDesktop("sync-tools", n.Text)
+	}
+	return postWebhook(target, n)
+}
+
+// postWebhook posts the notification as JSON, treating any non-2xx response as a failure
+func postWebhook(target string, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("error encoding notification: %w", err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(target, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error posting notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// showDesktop shells out to the platform's notification tool
+func showDesktop(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "windows":
+		cmd = exec.Command("msg", "*", title+": "+message)
+	default:
+		cmd = exec.Command("notify-send", title, message)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if detail := strings.TrimSpace(string(output)); detail != "" {
+			err = fmt.Errorf("%w: %s", err, detail)
+		}
+		return fmt.Errorf("error showing desktop notification with %s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
+
+// appleScriptString quotes s as an AppleScript string literal
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
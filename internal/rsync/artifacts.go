@@ -0,0 +1,147 @@
+package rsync
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/filters"
+)
+
+const (
+	// partialDirName is the directory rsync keeps partially transferred files in
+	partialDirName = ".rsync-partial"
+	// delayUpdatesDirName is where --delay-updates stages files until the transfer ends
+	delayUpdatesDirName = ".~tmp~"
+)
+
+var (
+	// redoFilePattern matches copies kept by `sync-tools undo --keep-redo` (name.redo-<unix time>)
+	redoFilePattern = regexp.MustCompile(`\.redo-\d+$`)
+	// rsyncTempFilePattern matches rsync's in-flight temp files (.name.XXXXXX)
+	rsyncTempFilePattern = regexp.MustCompile(`^\..+\.([A-Za-z0-9]{6})$`)
+)
+
+// StaleArtifact is a leftover from an earlier interrupted or conflicted run
+type StaleArtifact struct {
+	// Path is relative to the destination root
+	Path string
+	Kind string
+	// Interrupted marks the leftovers of a run that didn't finish, which a new sync
+	// shouldn't start on top of
+	Interrupted bool
+	// Removable is false for entries recognized by name shape alone, such as rsync temp
+	// files, which could be real files and are only ever reported
+	Removable bool
+}
+
+// FindStaleArtifacts walks opts.Dest for conflict copies named by opts.ConflictSuffix, rsync
+// partial dirs and temp files, and leftover sync-tools filter files. Paths the dest-side
+// filter excludes or protects are left out, since the sync doesn't touch them either.
+func FindStaleArtifacts(opts *Options) ([]StaleArtifact, error) {
+	conflicts, err := ParseConflictSuffix(opts.ConflictSuffix)
+	if err != nil {
+		return nil, err
+	}
+	// Protect rules keep paths away from the sync just as excludes do
+	var lines []string
+	for _, line := range destFilterLines(opts) {
+		lines = append(lines, "- "+strings.TrimPrefix(strings.TrimPrefix(line, "- "), "P "))
+	}
+	rules := filters.ParseRules(lines)
+
+	dest := opts.Dest
+	var artifacts []StaleArtifact
+	err = filepath.WalkDir(dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == dest {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dest, path)
+		if err != nil {
+			return err
+		}
+		if !filters.Match(rules, filepath.ToSlash(relPath), d.IsDir()).Included {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if artifact, ok := classifyArtifact(d.Name(), d.IsDir(), conflicts); ok {
+			artifact.Path = relPath
+			artifacts = append(artifacts, artifact)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return artifacts, err
+}
+
+// classifyArtifact describes a dest entry from its name, reporting false for ordinary files
+func classifyArtifact(name string, isDir bool, conflicts *ConflictTemplate) (StaleArtifact, bool) {
+	kind := artifactKind(name, isDir, conflicts)
+	switch kind {
+	case "":
+		return StaleArtifact{}, false
+	case "rsync temp file":
+		return StaleArtifact{Kind: kind}, true
+	case "conflict copy", "undo redo copy":
+		return StaleArtifact{Kind: kind, Removable: true}, true
+	default:
+		return StaleArtifact{Kind: kind, Interrupted: true, Removable: true}, true
+	}
+}
+
+// artifactKind classifies a dest entry by name, returning "" for ordinary files
+func artifactKind(name string, isDir bool, conflicts *ConflictTemplate) string {
+	if isDir {
+		switch name {
+		case partialDirName:
+			return "rsync partial dir"
+		case delayUpdatesDirName:
+			return "rsync delay-updates dir"
+		}
+		return ""
+	}
+
+	if _, _, ok := conflicts.Parse(name); ok {
+		return "conflict copy"
+	}
+	switch {
+	case redoFilePattern.MatchString(name):
+		return "undo redo copy"
+	}
+
+	if m := rsyncTempFilePattern.FindStringSubmatch(name); m != nil && looksRandom(m[1]) {
+		return "rsync temp file"
+	}
+	return ""
+}
+
+// looksRandom reports whether a temp-file suffix mixes in capitals or digits. rsync's random
+// suffixes almost always do, while an all-lowercase one is likely a real dotfile (.eslintrc.config).
+func looksRandom(suffix string) bool {
+	return strings.ToLower(suffix) != suffix || strings.ContainsAny(suffix, "0123456789")
+}
+
+// RemoveStaleArtifacts deletes previously found artifacts from dest, skipping those that
+// aren't Removable
+func RemoveStaleArtifacts(dest string, artifacts []StaleArtifact) error {
+	for _, artifact := range artifacts {
+		if !artifact.Removable {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dest, artifact.Path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
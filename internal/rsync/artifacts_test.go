@@ -0,0 +1,61 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindStaleArtifacts(t *testing.T) {
+	dest := t.TempDir()
+	for _, name := range []string{
+		".rsync-partial/big.iso",
+		".env.Local1",
+		"notes.conflict-1700000000",
+		"notes.redo-1700000000",
+		"keep/.cache.X1y2Z3",
+		"readme.md",
+		// sync-tools' own temp files never land in the dest, so this is the user's
+		"sync-tools-filter-notes.txt",
+	} {
+		path := filepath.Join(dest, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	artifacts, err := FindStaleArtifacts(&Options{Dest: dest, IgnoreDest: []string{"!keep/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]StaleArtifact{
+		".rsync-partial":            {Kind: "rsync partial dir", Interrupted: true, Removable: true},
+		".env.Local1":               {Kind: "rsync temp file"},
+		"notes.conflict-1700000000": {Kind: "conflict copy", Removable: true},
+		"notes.redo-1700000000":     {Kind: "undo redo copy", Removable: true},
+	}
+	if len(artifacts) != len(want) {
+		t.Fatalf("FindStaleArtifacts = %+v, want %d artifacts", artifacts, len(want))
+	}
+	for _, artifact := range artifacts {
+		expected, ok := want[filepath.ToSlash(artifact.Path)]
+		expected.Path = artifact.Path
+		if !ok || artifact != expected {
+			t.Errorf("artifact %+v, want %+v", artifact, expected)
+		}
+	}
+
+	// Only removable artifacts are deleted
+	if err := RemoveStaleArtifacts(dest, artifacts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, ".env.Local1")); err != nil {
+		t.Errorf("rsync temp file lookalike was removed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "notes.conflict-1700000000")); !os.IsNotExist(err) {
+		t.Errorf("conflict copy was not removed: %v", err)
+	}
+}
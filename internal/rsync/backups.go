@@ -0,0 +1,87 @@
+package rsync
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// redoSuffixFormat names the copy of a file kept when undo overwrites it (name.redo-<unix time>)
+const redoSuffixFormat = "%s.redo-%d"
+
+// ConflictBackup is a conflict copy that can be restored over the file it was taken from
+type ConflictBackup struct {
+	// Path and Original are relative to the destination root
+	Path     string
+	Original string
+	Created  time.Time
+}
+
+// FindConflictBackups lists the conflict copies named by names in dest made at or after since
+// (zero for all). When a file has several, only the oldest is kept: it holds the version from
+// before the first conflict in the window, which is what undo should put back.
+func FindConflictBackups(dest string, names *ConflictTemplate, since time.Time) ([]ConflictBackup, error) {
+	oldest := make(map[string]ConflictBackup)
+	err := filepath.WalkDir(dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		original, created, ok := names.Parse(d.Name())
+		if !ok || created.Before(since) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dest, path)
+		if err != nil {
+			return err
+		}
+		backup := ConflictBackup{
+			Path:     relPath,
+			Original: filepath.Join(filepath.Dir(relPath), original),
+			Created:  created,
+		}
+		if existing, ok := oldest[backup.Original]; !ok || backup.Created.Before(existing.Created) {
+			oldest[backup.Original] = backup
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]ConflictBackup, 0, len(oldest))
+	for _, backup := range oldest {
+		backups = append(backups, backup)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].Original < backups[j].Original })
+	return backups, nil
+}
+
+// RestoreConflictBackup moves a conflict copy back to its original name. With keepRedo, the
+// file being replaced is first renamed to name.redo-<unix time> so the undo can be reversed.
+// It returns the redo copy's relative path, or "" when none was made.
+func RestoreConflictBackup(dest string, backup ConflictBackup, keepRedo bool) (string, error) {
+	originalPath := filepath.Join(dest, backup.Original)
+
+	var redoPath string
+	if keepRedo {
+		if _, err := os.Lstat(originalPath); err == nil {
+			redoPath = fmt.Sprintf(redoSuffixFormat, backup.Original, time.Now().Unix())
+			if err := os.Rename(originalPath, filepath.Join(dest, redoPath)); err != nil {
+				return "", fmt.Errorf("error keeping redo copy of %s: %w", backup.Original, err)
+			}
+		}
+	}
+
+	if err := os.Rename(filepath.Join(dest, backup.Path), originalPath); err != nil {
+		return redoPath, fmt.Errorf("error restoring %s: %w", backup.Original, err)
+	}
+	return redoPath, nil
+}
@@ -0,0 +1,124 @@
+package rsync
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"time"
+)
+
+// Comparison is a direction-neutral account of how two trees differ
+type Comparison struct {
+	// OnlyInA and OnlyInB list paths present on one side; a directory stands for everything under it
+	OnlyInA   []ListedEntry   `json:"only_in_a"`
+	OnlyInB   []ListedEntry   `json:"only_in_b"`
+	Differing []DifferingFile `json:"differing"`
+	// Identical counts the files whose size and modification time match
+	Identical int `json:"identical"`
+}
+
+// ComparedSide is one side's view of a path in a comparison
+type ComparedSide struct {
+	IsDir   bool      `json:"is_dir"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// DifferingFile is a path present on both sides with a different size, modification time, or type
+type DifferingFile struct {
+	Path string       `json:"path"`
+	A    ComparedSide `json:"a"`
+	B    ComparedSide `json:"b"`
+}
+
+// CompareTrees compares the local trees a and b under the current filters, each side reading
+// its own .syncignore. Files match when their size and modification time do, as in rsync's
+// quick check.
+func (r *Runner) CompareTrees(opts *Options, a, b string) (Comparison, error) {
+	var cmp Comparison
+	if IsRemotePath(a) || IsRemotePath(b) {
+		return cmp, fmt.Errorf("compare needs two local directories")
+	}
+
+	sideA, err := r.listSide(opts, a)
+	if err != nil {
+		return cmp, err
+	}
+	sideB, err := r.listSide(opts, b)
+	if err != nil {
+		return cmp, err
+	}
+	return compareSides(opts, sideA, sideB), nil
+}
+
+// compareSides compares two listings of paths to their size and modification time
+func compareSides(opts *Options, sideA, sideB map[string]ComparedSide) Comparison {
+	var cmp Comparison
+	paths := make([]string, 0, len(sideA)+len(sideB))
+	for relPath := range sideA {
+		paths = append(paths, relPath)
+	}
+	for relPath := range sideB {
+		if _, ok := sideA[relPath]; !ok {
+			paths = append(paths, relPath)
+		}
+	}
+	sort.Strings(paths)
+
+	// A directory missing on one side is reported once, not with each path under it
+	oneSided := map[string]bool{}
+	for _, relPath := range paths {
+		if oneSided[path.Dir(relPath)] {
+			if sideA[relPath].IsDir || sideB[relPath].IsDir {
+				oneSided[relPath] = true
+			}
+			continue
+		}
+
+		entryA, inA := sideA[relPath]
+		entryB, inB := sideB[relPath]
+		switch {
+		case !inB:
+			cmp.OnlyInA = append(cmp.OnlyInA, listedEntry(relPath, entryA))
+			oneSided[relPath] = entryA.IsDir
+		case !inA:
+			cmp.OnlyInB = append(cmp.OnlyInB, listedEntry(relPath, entryB))
+			oneSided[relPath] = entryB.IsDir
+		case entryA.IsDir && entryB.IsDir:
+		case entryA.IsDir != entryB.IsDir || quickCheckDiffers(opts, entryA.Size, entryA.ModTime, entryB.Size, entryB.ModTime):
+			cmp.Differing = append(cmp.Differing, DifferingFile{Path: relPath, A: entryA, B: entryB})
+		default:
+			cmp.Identical++
+		}
+	}
+	return cmp
+}
+
+// listSide maps the filtered paths under root to their size and modification time
+func (r *Runner) listSide(opts *Options, root string) (map[string]ComparedSide, error) {
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("directory does not exist: %s", root)
+	}
+	sideOpts := *opts
+	sideOpts.Source = root
+	entries, err := r.ListIncluded(&sideOpts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing %s: %w", root, err)
+	}
+
+	side := make(map[string]ComparedSide, len(entries))
+	for _, entry := range entries {
+		info, err := os.Lstat(localPath(root, entry.Path))
+		if err != nil {
+			return nil, err
+		}
+		side[entry.Path] = ComparedSide{IsDir: entry.IsDir, Size: entry.Size, ModTime: info.ModTime()}
+	}
+	return side, nil
+}
+
+// listedEntry turns one side's view of a path back into a listing entry
+func listedEntry(relPath string, side ComparedSide) ListedEntry {
+	return ListedEntry{Path: relPath, IsDir: side.IsDir, Size: side.Size}
+}
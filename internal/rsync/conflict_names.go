@@ -0,0 +1,216 @@
+package rsync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultConflictSuffix names conflict copies <file>.conflict-<unix time>
+const DefaultConflictSuffix = "{name}{ext}.conflict-{time:unix}"
+
+// ConflictTemplate names conflict copies from a template such as
+// "{name}.{time:2006-01-02_1504}{ext}.bak", and recognizes the names it produces
+type ConflictTemplate struct {
+	parts []conflictPart
+	// pattern matches a rendered name, with one group per placeholder in groups
+	pattern *regexp.Regexp
+	groups  []conflictPart
+}
+
+// conflictPart is a literal run of the template, or a placeholder when kind is set
+type conflictPart struct {
+	literal string
+	kind    string
+	layout  string
+}
+
+// ParseConflictSuffix parses a conflict copy template; an empty one is DefaultConflictSuffix.
+// {name} and {ext} are the file name without and with its extension removed, {side} is the
+// side the copy was taken from, and {time:format} is when it was made, as a Go time layout or
+// "unix". The template needs {name}, {ext}, and a time so undo can find the original file
+// and the order the copies were made in.
+func ParseConflictSuffix(template string) (*ConflictTemplate, error) {
+	if template == "" {
+		template = DefaultConflictSuffix
+	}
+
+	t := &ConflictTemplate{}
+	var literal strings.Builder
+	seen := map[string]bool{}
+	for rest := template; rest != ""; {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			literal.WriteString(rest)
+			break
+		}
+		literal.WriteString(rest[:open])
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in conflict suffix %q", template)
+		}
+		placeholder := rest[open+1 : open+end]
+		rest = rest[open+end+1:]
+
+		part := conflictPart{kind: placeholder}
+		switch {
+		case placeholder == "name" || placeholder == "ext" || placeholder == "side":
+		case strings.HasPrefix(placeholder, "time:") && len(placeholder) > len("time:"):
+			part = conflictPart{kind: "time", layout: strings.TrimPrefix(placeholder, "time:")}
+		default:
+			return nil, fmt.Errorf("unknown placeholder {%s} in conflict suffix %q (use {name}, {ext}, {side}, or {time:format})", placeholder, template)
+		}
+		if literal.Len() > 0 {
+			t.parts = append(t.parts, conflictPart{literal: literal.String()})
+			literal.Reset()
+		}
+		t.parts = append(t.parts, part)
+		seen[part.kind] = true
+	}
+	if literal.Len() > 0 {
+		t.parts = append(t.parts, conflictPart{literal: literal.String()})
+	}
+
+	if !seen["name"] || !seen["ext"] || !seen["time"] {
+		return nil, fmt.Errorf("conflict suffix %q must include {name}, {ext}, and {time:format}, so undo can find the original file and when the copy was made", template)
+	}
+	if strings.ContainsAny(template, `/\`) {
+		return nil, fmt.Errorf("conflict suffix %q must not contain path separators", template)
+	}
+
+	// A trailing -N is the counter added when a name is already taken
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, part := range t.parts {
+		switch part.kind {
+		case "":
+			pattern.WriteString(regexp.QuoteMeta(part.literal))
+			continue
+		case "name":
+			pattern.WriteString("(.*)")
+		case "ext":
+			pattern.WriteString(`((?:\.[^.]*)?)`)
+		case "side":
+			pattern.WriteString("(source|dest)")
+		case "time":
+			pattern.WriteString("(" + layoutPattern(part.layout) + ")")
+		}
+		t.groups = append(t.groups, part)
+	}
+	pattern.WriteString(`(?:-\d+)?$`)
+	t.pattern = regexp.MustCompile(pattern.String())
+	return t, nil
+}
+
+// layoutPattern matches the times a Go time layout produces: each run of digits in the
+// layout becomes a run of digits, each run of letters a run of letters (Jan, PM, MST), and
+// anything else must appear as written. unix is a run of digits.
+func layoutPattern(layout string) string {
+	if layout == "unix" {
+		return `\d+`
+	}
+	var sb strings.Builder
+	var last string
+	for _, c := range layout {
+		class := regexp.QuoteMeta(string(c))
+		switch {
+		case c >= '0' && c <= '9':
+			class = `\d+`
+		case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			class = `[A-Za-z]+`
+		}
+		if class != last || (class != `\d+` && class != `[A-Za-z]+`) {
+			sb.WriteString(class)
+		}
+		last = class
+	}
+	return sb.String()
+}
+
+// Render names the conflict copy of file (a base name) taken from side at the given time
+func (t *ConflictTemplate) Render(file, side string, at time.Time) string {
+	ext := filepath.Ext(file)
+	var sb strings.Builder
+	for _, part := range t.parts {
+		switch part.kind {
+		case "":
+			sb.WriteString(part.literal)
+		case "name":
+			sb.WriteString(strings.TrimSuffix(file, ext))
+		case "ext":
+			sb.WriteString(ext)
+		case "side":
+			sb.WriteString(side)
+		case "time":
+			if part.layout == "unix" {
+				sb.WriteString(strconv.FormatInt(at.Unix(), 10))
+			} else {
+				sb.WriteString(at.Format(part.layout))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// Parse reports whether name is a conflict copy made by this template, returning the
+// original file name and when the copy was made
+func (t *ConflictTemplate) Parse(name string) (string, time.Time, bool) {
+	m := t.pattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", time.Time{}, false
+	}
+
+	var base, ext string
+	var created time.Time
+	var haveName, haveExt, haveTime bool
+	for i, part := range t.groups {
+		value := m[i+1]
+		switch part.kind {
+		case "name":
+			if !haveName {
+				base, haveName = value, true
+			}
+		case "ext":
+			if !haveExt {
+				ext, haveExt = value, true
+			}
+		case "time":
+			if haveTime {
+				continue
+			}
+			if part.layout == "unix" {
+				seconds, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return "", time.Time{}, false
+				}
+				created = time.Unix(seconds, 0)
+			} else {
+				parsed, err := time.ParseInLocation(part.layout, value, time.Local)
+				if err != nil {
+					return "", time.Time{}, false
+				}
+				created = parsed
+			}
+			haveTime = true
+		}
+	}
+	if base+ext == "" {
+		return "", time.Time{}, false
+	}
+	return base + ext, created, true
+}
+
+// uniqueConflictName returns name, or name-2, name-3, ... if dir already has a file by that name
+func uniqueConflictName(dir, name string) string {
+	candidate := name
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(filepath.Join(dir, candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", name, n)
+	}
+}
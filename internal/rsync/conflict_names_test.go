@@ -0,0 +1,82 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestConflictTemplateRoundTrip(t *testing.T) {
+	at := time.Date(2025, 9, 1, 14, 33, 0, 0, time.Local)
+	tests := []struct {
+		template string
+		file     string
+		want     string
+	}{
+		{"", "report.txt", "report.txt.conflict-" + strconv.FormatInt(at.Unix(), 10)},
+		{"{name}.{time:2006-01-02_1504}{ext}.bak", "report.txt", "report.2025-09-01_1433.txt.bak"},
+		{"{name}-{side}-{time:unix}{ext}", "Makefile", "Makefile-dest-" + strconv.FormatInt(at.Unix(), 10)},
+		{"{name}{ext}.{time:20060102T150405}", ".bashrc", ".bashrc.20250901T143300"},
+	}
+
+	for _, tt := range tests {
+		names, err := ParseConflictSuffix(tt.template)
+		if err != nil {
+			t.Fatalf("ParseConflictSuffix(%q) returned error: %v", tt.template, err)
+		}
+		got := names.Render(tt.file, "dest", at)
+		if got != tt.want {
+			t.Errorf("Render(%q) with %q = %q, want %q", tt.file, tt.template, got, tt.want)
+		}
+
+		for _, name := range []string{got, got + "-2"} {
+			original, created, ok := names.Parse(name)
+			if !ok || original != tt.file || !created.Equal(at) {
+				t.Errorf("Parse(%q) with %q = %q, %v, %v; want %q, %v", name, tt.template, original, created, ok, tt.file, at)
+			}
+		}
+	}
+}
+
+func TestConflictTemplateIgnoresOtherFiles(t *testing.T) {
+	names, err := ParseConflictSuffix("{name}.{time:2006-01-02_1504}{ext}.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"report.txt", "report.txt.bak", "report.not-a-date.txt.bak", "report.txt.conflict-1700000000"} {
+		if _, _, ok := names.Parse(name); ok {
+			t.Errorf("Parse(%q) matched, want no match", name)
+		}
+	}
+}
+
+func TestParseConflictSuffixRejectsBadTemplates(t *testing.T) {
+	for _, template := range []string{
+		"{name}.bak",
+		"{name}{ext}.{time:unix",
+		"{name}{ext}.{date}",
+		"{name}{ext}.{time:}",
+		"old/{name}{ext}.{time:unix}",
+	} {
+		if _, err := ParseConflictSuffix(template); err == nil {
+			t.Errorf("ParseConflictSuffix(%q) accepted a bad template", template)
+		}
+	}
+}
+
+func TestUniqueConflictName(t *testing.T) {
+	dir := t.TempDir()
+	if got := uniqueConflictName(dir, "a.bak"); got != "a.bak" {
+		t.Errorf("uniqueConflictName with no clash = %q, want a.bak", got)
+	}
+	for _, name := range []string{"a.bak", "a.bak-2"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := uniqueConflictName(dir, "a.bak"); got != "a.bak-3" {
+		t.Errorf("uniqueConflictName with two clashes = %q, want a.bak-3", got)
+	}
+}
@@ -0,0 +1,158 @@
+package rsync
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConflictChoice is how one conflicting file is resolved in a two-way sync
+type ConflictChoice string
+
+const (
+	// KeepSource lets the source version overwrite the dest
+	KeepSource ConflictChoice = "source"
+	// KeepDest copies the dest version over the source, so both sides end up with it
+	KeepDest ConflictChoice = "dest"
+	// KeepBoth saves the dest version as a conflict copy before the source overwrites it
+	KeepBoth ConflictChoice = "both"
+	// SkipConflict leaves the file alone on both sides for this run
+	SkipConflict ConflictChoice = "skip"
+)
+
+// parseConflictChoice reads an answer to the conflict prompt; an empty answer keeps both
+func parseConflictChoice(answer string) (ConflictChoice, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "", "b", "both":
+		return KeepBoth, true
+	case "s", "source":
+		return KeepSource, true
+	case "d", "dest":
+		return KeepDest, true
+	case "k", "skip":
+		return SkipConflict, true
+	}
+	return "", false
+}
+
+// resolveConflicts decides and applies a choice for each conflicting file, returning the
+// files to leave out of the transfer. Without --interactive-conflicts, or with --yes, every
+// conflict keeps both versions, as two-way syncs always have.
+func (r *Runner) resolveConflicts(conflicts []string, opts *Options) ([]string, error) {
+	prompt := opts.InteractiveConflicts && !opts.Yes
+	if opts.InteractiveConflicts && opts.Yes {
+		r.logger.Info("--yes given; keeping both versions of every conflicting file")
+	}
+
+	// One reader for every prompt, so answers typed ahead aren't lost between them
+	stdin := bufio.NewReader(os.Stdin)
+	var keepBoth, skipped []string
+	for _, conflict := range conflicts {
+		choice := KeepBoth
+		if prompt {
+			choice = promptConflict(stdin, conflict)
+		}
+		switch choice {
+		case KeepBoth:
+			keepBoth = append(keepBoth, conflict)
+		case KeepSource:
+			r.logger.Infof("Keeping the source version of %s", conflict)
+		case KeepDest:
+			if err := r.keepDestVersion(conflict, opts); err != nil {
+				return nil, err
+			}
+		case SkipConflict:
+			r.logger.Infof("Skipping %s; both versions are left as they are", conflict)
+			skipped = append(skipped, filepath.ToSlash(conflict))
+		}
+	}
+
+	if len(keepBoth) > 0 {
+		r.logger.Warnf("Preserving destination versions of %d conflicting files as conflict files", len(keepBoth))
+		if err := r.preserveConflicts(keepBoth, opts); err != nil {
+			return nil, fmt.Errorf("error preserving conflicts: %w", err)
+		}
+	}
+	return skipped, nil
+}
+
+// promptConflict asks how to resolve one conflicting file, keeping both versions when
+// stdin can't answer
+func promptConflict(stdin *bufio.Reader, conflict string) ConflictChoice {
+	for {
+		fmt.Printf("\nConflict: %s changed on both sides. Keep [s]ource, [d]est, [b]oth, or s[k]ip? [b]: ", conflict)
+
+		response, err := stdin.ReadString('\n')
+		if err != nil && response == "" {
+			return KeepBoth
+		}
+		if choice, ok := parseConflictChoice(response); ok {
+			return choice
+		}
+		fmt.Println("Please answer s, d, b, or k.")
+	}
+}
+
+// keepDestVersion copies the dest version of a conflicting file over the source one, keeping
+// its modification time so the transfer that follows sees nothing to update
+func (r *Runner) keepDestVersion(conflict string, opts *Options) error {
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		return conflictError(conflict, "keeping the dest version of %s needs a local source and dest", conflict)
+	}
+	if opts.DryRun {
+		r.logger.Infof("Would copy the dest version of %s to the source (dry run)", conflict)
+		return nil
+	}
+
+	if err := copyFileTo(localPath(opts.Dest, conflict), localPath(opts.Source, conflict)); err != nil {
+		return conflictError(conflict, "error keeping the dest version of %s: %w", conflict, err)
+	}
+	r.logger.Infof("Kept the dest version of %s", conflict)
+	return nil
+}
+
+// copyFileTo replaces dst with a copy of src, including its mode and modification time. The
+// copy is written next to dst and renamed over it, so an interrupted copy can't leave dst
+// truncated.
+func copyFileTo(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := out.Name()
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chtimes(tmpPath, info.ModTime(), info.ModTime()); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package rsync
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestPromptConflict(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ConflictChoice
+	}{
+		{"s\n", KeepSource},
+		{"DEST\n", KeepDest},
+		{"\n", KeepBoth},
+		{"maybe\nk\n", SkipConflict},
+		{"", KeepBoth},
+	}
+
+	for _, tt := range tests {
+		if got := promptConflict(bufio.NewReader(strings.NewReader(tt.input)), "notes.txt"); got != tt.want {
+			t.Errorf("promptConflict with input %q = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestKeepDestVersion(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, dest := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, "notes.txt"), []byte("source"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	destFile := filepath.Join(dest, "notes.txt")
+	if err := os.WriteFile(destFile, []byte("dest"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(destFile, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := &Options{Source: source, Dest: dest}
+	if err := NewRunner(logger).keepDestVersion("notes.txt", opts); err != nil {
+		t.Fatalf("keepDestVersion returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(source, "notes.txt"))
+	if err != nil || string(data) != "dest" {
+		t.Errorf("source notes.txt = %q, %v; want the dest version", data, err)
+	}
+	info, err := os.Stat(filepath.Join(source, "notes.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(modTime) {
+		t.Errorf("source notes.txt modified at %v, want the dest's %v", info.ModTime(), modTime)
+	}
+	// The copy is renamed into place, leaving no temp file behind
+	if entries, err := os.ReadDir(source); err != nil || len(entries) != 1 {
+		t.Errorf("source holds %v, %v; want only notes.txt", entries, err)
+	}
+}
+
+// writeAt writes content to root/name with the given modification time
+func writeAt(t *testing.T, root, name, content string, modTime time.Time) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, dest := t.TempDir(), t.TempDir()
+	older := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	newer := older.Add(time.Hour)
+
+	// Edited in the dest after the source: a conflict
+	writeAt(t, source, "edited.txt", "source", older)
+	writeAt(t, dest, "edited.txt", "dest edit", newer)
+	// An older dest copy is just out of date
+	writeAt(t, source, "stale.txt", "source", newer)
+	writeAt(t, dest, "stale.txt", "old", older)
+	// Touched in the dest without changing
+	writeAt(t, source, "touched.txt", "same", older)
+	writeAt(t, dest, "touched.txt", "same", newer)
+	// Only in the dest
+	writeAt(t, dest, "extra.txt", "dest", newer)
+
+	conflicts, err := NewRunner(logger).detectConflicts(&Options{Source: source, Dest: dest})
+	if err != nil {
+		t.Fatalf("detectConflicts returned error: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "edited.txt" {
+		t.Errorf("detectConflicts = %v, want [edited.txt]", conflicts)
+	}
+}
+
+func TestPreserveConflicts(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, dest := t.TempDir(), t.TempDir()
+	writeAt(t, source, "notes.txt", "source", time.Now())
+	writeAt(t, dest, "notes.txt", "dest", time.Now())
+
+	opts := &Options{Source: source, Dest: dest, ConflictSuffix: "{name}.{side}-{time:unix}{ext}"}
+	if err := NewRunner(logger).preserveConflicts([]string{"notes.txt"}, opts); err != nil {
+		t.Fatalf("preserveConflicts returned error: %v", err)
+	}
+
+	copies, err := filepath.Glob(filepath.Join(source, "notes.dest-*.txt"))
+	if err != nil || len(copies) != 1 {
+		t.Fatalf("source conflict copies = %v, %v; want one", copies, err)
+	}
+	if data, err := os.ReadFile(copies[0]); err != nil || string(data) != "dest" {
+		t.Errorf("conflict copy = %q, %v; want the dest version", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(source, "notes.txt")); err != nil || string(data) != "source" {
+		t.Errorf("source notes.txt = %q, %v; want it unchanged", data, err)
+	}
+}
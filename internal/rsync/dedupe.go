@@ -0,0 +1,157 @@
+package rsync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// dedupeDest replaces each file this sync created or updated with a hard link to an identical
+// file elsewhere in the dest, so duplicate content is stored once. rsync has no such option:
+// --link-dest only links against an earlier snapshot. Files are compared by size, then by a
+// streamed SHA-256 of their content. A hard link shares its mode and modification time, so
+// only files that already agree on both are linked; otherwise the next sync would see a
+// change and copy the file again. Failures only cost the saving, so they are logged, and a
+// dest that can't hold hard links ends the pass.
+func (r *Runner) dedupeDest(opts *Options) {
+	var candidates []string
+	sizes := map[int64]bool{}
+	for _, change := range r.changes {
+		if change.IsDir || change.Kind == ChangeDeleted || change.Kind == ChangeUnchanged || change.Size == 0 {
+			continue
+		}
+		candidates = append(candidates, change.Path)
+		sizes[change.Size] = true
+	}
+	if len(candidates) == 0 {
+		return
+	}
+	sort.Strings(candidates)
+
+	bySize, err := r.dedupeIndex(opts, sizes)
+	if err != nil {
+		r.logger.Warnf("Skipping --dedupe: error indexing the dest: %v", err)
+		return
+	}
+
+	hashes := map[string]string{}
+	linked := 0
+	var saved int64
+	for _, candidate := range candidates {
+		path := localPath(opts.Dest, candidate)
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		target, ok := r.findDuplicate(path, info, bySize[info.Size()], hashes)
+		if !ok {
+			continue
+		}
+		if err := replaceWithLink(target, path); err != nil {
+			r.logger.Warnf("Stopping --dedupe: can't hard-link %s in the dest: %v", candidate, err)
+			break
+		}
+		r.logger.Debugf("Deduplicated %s: linked to %s", candidate, target)
+		linked++
+		saved += info.Size()
+	}
+	if linked > 0 {
+		r.logger.Infof("Deduplicated %d files by hard link, saving %d bytes", linked, saved)
+	}
+}
+
+// dedupeIndex maps the sizes a dedupe pass is looking for to the dest's regular files of that
+// size, in walk order. The partial dir is left out, since its files aren't finished.
+func (r *Runner) dedupeIndex(opts *Options, sizes map[int64]bool) (map[int64][]string, error) {
+	bySize := map[int64][]string{}
+	err := filepath.WalkDir(opts.Dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if opts.PartialDir != "" && path != opts.Dest && d.Name() == filepath.Base(opts.PartialDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if sizes[info.Size()] {
+			bySize[info.Size()] = append(bySize[info.Size()], path)
+		}
+		return nil
+	})
+	return bySize, err
+}
+
+// findDuplicate returns the first of others with the same content, mode, and modification time
+// as path. It reports false when path is already linked to one of them.
+func (r *Runner) findDuplicate(path string, info os.FileInfo, others []string, hashes map[string]string) (string, bool) {
+	for _, other := range others {
+		if other == path {
+			continue
+		}
+		otherInfo, err := os.Lstat(other)
+		if err != nil {
+			continue
+		}
+		if os.SameFile(info, otherInfo) {
+			return "", false
+		}
+		if otherInfo.Mode() != info.Mode() || !otherInfo.ModTime().Equal(info.ModTime()) {
+			continue
+		}
+		hash, err := fileHash(path, hashes)
+		if err != nil {
+			r.logger.Debugf("Can't hash %s for --dedupe: %v", path, err)
+			return "", false
+		}
+		if otherHash, err := fileHash(other, hashes); err == nil && otherHash == hash {
+			return other, true
+		}
+	}
+	return "", false
+}
+
+// fileHash returns the SHA-256 of path's content, streamed and cached in hashes
+func fileHash(path string, hashes map[string]string) (string, error) {
+	if hash, ok := hashes[path]; ok {
+		return hash, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	hashes[path] = hash
+	return hash, nil
+}
+
+// replaceWithLink replaces path with a hard link to target, linking to a temporary name
+// first so path is never missing
+func replaceWithLink(target, path string) error {
+	tmpPath := filepath.Join(filepath.Dir(path), ".~dedupe~"+filepath.Base(path))
+	if err := os.Link(target, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
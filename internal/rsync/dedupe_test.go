@@ -0,0 +1,85 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestDedupeDest(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	modTime := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	files := []struct {
+		name    string
+		content string
+		modTime time.Time
+	}{
+		{"albums/photo.jpg", "same pixels", modTime},
+		{"inbox/photo.jpg", "same pixels", modTime},
+		{"inbox/retouched.jpg", "new pixels!", modTime},
+		{"inbox/touched.jpg", "same pixels", modTime.Add(time.Hour)},
+	}
+	for _, f := range files {
+		path := filepath.Join(dest, filepath.FromSlash(f.name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.modTime, f.modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := NewRunner(logger)
+	for _, name := range []string{"inbox/photo.jpg", "inbox/retouched.jpg", "inbox/touched.jpg"} {
+		runner.changes = append(runner.changes, Change{Kind: ChangeCreated, Path: name, Size: 11})
+	}
+	runner.dedupeDest(&Options{Dest: dest})
+
+	original, err := os.Stat(filepath.Join(dest, "albums", "photo.jpg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		linked bool
+	}{
+		{"inbox/photo.jpg", true},
+		{"inbox/retouched.jpg", false}, // same size, different content
+		{"inbox/touched.jpg", false},   // same content, different modification time
+	}
+	for _, tt := range tests {
+		info, err := os.Stat(filepath.Join(dest, filepath.FromSlash(tt.name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := os.SameFile(original, info); got != tt.linked {
+			t.Errorf("%s linked to albums/photo.jpg = %v, want %v", tt.name, got, tt.linked)
+		}
+	}
+}
+
+func TestValidateDedupe(t *testing.T) {
+	for _, opts := range []*Options{
+		{Dedupe: true, Mode: "two-way", Dest: "/backup"},
+		{Dedupe: true, Mode: "one-way", Dest: "host:/backup"},
+		{Dedupe: true, Mode: "one-way", Dest: "/backup", Inplace: true},
+		{Dedupe: true, Mode: "one-way", Dest: "/backup", RsyncExtraArgs: []string{"--inplace"}},
+	} {
+		if err := validateOptions(opts); err == nil {
+			t.Errorf("validateOptions accepted --dedupe with %+v", opts)
+		}
+	}
+	if err := validateOptions(&Options{Dedupe: true, Mode: "one-way", Dest: "/backup"}); err != nil {
+		t.Errorf("validateOptions rejected a local one-way --dedupe: %v", err)
+	}
+}
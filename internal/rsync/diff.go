@@ -0,0 +1,171 @@
+package rsync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// binaryCheckLen is how much of a file is scanned for NUL bytes, matching git's heuristic
+const binaryCheckLen = 8000
+
+// gitAvailable reports whether git is on PATH, so preview and patch modes can pick
+// their code path up front instead of interpreting git failures
+func gitAvailable() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// writeUnifiedDiff writes a unified diff that turns dest into source. Paths use a/ and b/
+// prefixes, so the result applies from the dest directory with `git apply` or `patch -p1`.
+func writeUnifiedDiff(w io.Writer, opts *Options) error {
+	return forEachDiffFile(w, opts, func(relPath, destPath, srcPath string) error {
+		return writeFileDiff(w, relPath, destPath, srcPath)
+	})
+}
+
+// forEachDiffFile calls fn for every regular file in the source or dest, in path order, with
+// its file on each side ("" on the side missing it). Symlinks are noted in w.
+func forEachDiffFile(w io.Writer, opts *Options, fn func(relPath, destPath, srcPath string) error) error {
+	rootDevice, err := sourceDevice(opts)
+	if err != nil {
+		return err
+	}
+
+	sourceFiles, err := listDiffFiles(w, opts.Source, opts.OneFileSystem, rootDevice, opts.IncludeGit)
+	if err != nil {
+		return fmt.Errorf("error listing source files: %w", err)
+	}
+	destFiles, err := listDiffFiles(io.Discard, opts.Dest, false, 0, opts.IncludeGit)
+	if err != nil {
+		return fmt.Errorf("error listing destination files: %w", err)
+	}
+
+	paths := make([]string, 0, len(sourceFiles)+len(destFiles))
+	for relPath := range sourceFiles {
+		paths = append(paths, relPath)
+	}
+	for relPath := range destFiles {
+		if _, ok := sourceFiles[relPath]; !ok {
+			paths = append(paths, relPath)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, relPath := range paths {
+		if err := fn(relPath, destFiles[relPath], sourceFiles[relPath]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listDiffFiles maps slash-separated relative paths to the regular files under root. A missing
+// root lists nothing, and symlinks are noted in w since a text diff can't carry them.
+func listDiffFiles(w io.Writer, root string, oneFileSystem bool, rootDevice uint64, includeGit bool) (map[string]string, error) {
+	files := make(map[string]string)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if d.IsDir() {
+			// The sync itself excludes the top-level .git directory unless --include-git is set
+			if relPath == ".git" && !includeGit {
+				return filepath.SkipDir
+			}
+			if oneFileSystem && path != root {
+				if info, err := d.Info(); err == nil && !sameDevice(rootDevice, info) {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, _ := os.Readlink(path)
+			fmt.Fprintf(w, "# Symlink not included in diff: %s -> %s\n", relPath, target)
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files[relPath] = path
+		}
+		return nil
+	})
+	return files, err
+}
+
+// writeFileDiff writes one file's hunks. An empty destPath or srcPath marks the file as
+// created or deleted, which is spelled /dev/null on that side of the diff.
+func writeFileDiff(w io.Writer, relPath, destPath, srcPath string) error {
+	oldText, err := readDiffSide(destPath)
+	if err != nil {
+		return err
+	}
+	newText, err := readDiffSide(srcPath)
+	if err != nil {
+		return err
+	}
+	if oldText == newText && destPath != "" && srcPath != "" {
+		return nil
+	}
+
+	if isBinary(oldText) || isBinary(newText) {
+		fmt.Fprintf(w, "# Binary files differ: %s\n", relPath)
+		return nil
+	}
+
+	oldLabel, newLabel := "a/"+relPath, "b/"+relPath
+	if destPath == "" {
+		oldLabel = "/dev/null"
+	}
+	if srcPath == "" {
+		newLabel = "/dev/null"
+	}
+
+	diff := udiff.Unified(oldLabel, newLabel, oldText, newText)
+	if diff == "" {
+		// Creating or deleting an empty file has no hunks to express it
+		fmt.Fprintf(w, "# Empty file not included in diff: %s\n", relPath)
+		return nil
+	}
+	_, err = io.WriteString(w, diff)
+	return err
+}
+
+// readDiffSide returns a file's contents, or "" for the missing side of a created or deleted file
+func readDiffSide(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// isBinary reports whether text looks binary, i.e. has a NUL byte near the start
+func isBinary(text string) bool {
+	head := text
+	if len(head) > binaryCheckLen {
+		head = head[:binaryCheckLen]
+	}
+	return bytes.IndexByte([]byte(head), 0) >= 0
+}
@@ -0,0 +1,76 @@
+package rsync
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// ErrSourceNotFound is returned, wrapped with the path, when a local source doesn't exist
+var ErrSourceNotFound = errors.New("source directory does not exist")
+
+// ErrConflictUnresolved matches any ConflictError with errors.Is
+var ErrConflictUnresolved = errors.New("conflict could not be resolved")
+
+// RsyncError is returned when rsync ran but exited with a failure status. Code is rsync's
+// exit status, or -1 when it was killed by a signal.
+type RsyncError struct {
+	Code int
+	Err  error
+}
+
+func (e *RsyncError) Error() string { return "rsync command failed: " + e.Err.Error() }
+func (e *RsyncError) Unwrap() error { return e.Err }
+
+// newRsyncError wraps the error of a finished rsync, taking the code from its exit status
+func newRsyncError(err error) *RsyncError {
+	code := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code = exitErr.ExitCode()
+	}
+	return &RsyncError{Code: code, Err: err}
+}
+
+// rsyncExitReasons are what rsync's exit codes mean, from its man page
+var rsyncExitReasons = map[int]string{
+	1:  "syntax or usage error",
+	2:  "protocol incompatibility",
+	3:  "errors selecting input/output files or directories",
+	4:  "requested action not supported",
+	5:  "error starting client-server protocol",
+	10: "error in socket I/O",
+	11: "error in file I/O",
+	12: "error in rsync protocol data stream",
+	13: "errors with program diagnostics",
+	14: "error in IPC code",
+	20: "received SIGUSR1 or SIGINT",
+	21: "some error returned by waitpid()",
+	22: "error allocating core memory buffers",
+	23: "partial transfer due to error",
+	24: "partial transfer due to vanished source files",
+	25: "the --max-delete limit stopped deletions",
+	30: "timeout in data send/receive",
+	35: "timeout waiting for daemon connection",
+}
+
+// Reason describes the exit code in words, or "" for a code rsync doesn't document
+func (e *RsyncError) Reason() string {
+	return rsyncExitReasons[e.Code]
+}
+
+// ConflictError is returned when a file changed on both sides couldn't be resolved the way
+// it was asked. It reads as the underlying error and matches ErrConflictUnresolved.
+type ConflictError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConflictError) Error() string        { return e.Err.Error() }
+func (e *ConflictError) Unwrap() error        { return e.Err }
+func (e *ConflictError) Is(target error) bool { return target == ErrConflictUnresolved }
+
+// conflictError wraps a failure resolving path as a ConflictError
+func conflictError(path string, format string, args ...any) error {
+	return &ConflictError{Path: path, Err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,83 @@
+package rsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestRsyncError(t *testing.T) {
+	runErr := exec.Command("sh", "-c", "exit 23").Run()
+	err := fmt.Errorf("sync failed: %w", newRsyncError(runErr))
+
+	var rsyncErr *RsyncError
+	if !errors.As(err, &rsyncErr) {
+		t.Fatalf("errors.As(%v, *RsyncError) = false", err)
+	}
+	if rsyncErr.Code != 23 {
+		t.Errorf("Code = %d, want 23", rsyncErr.Code)
+	}
+	if got, want := rsyncErr.Reason(), "partial transfer due to error"; got != want {
+		t.Errorf("Reason() = %q, want %q", got, want)
+	}
+	if got, want := rsyncErr.Error(), "rsync command failed: exit status 23"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	// The exit status stays reachable for callers that look for it directly
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 23 {
+		t.Errorf("errors.As(%v, *exec.ExitError) didn't find exit status 23", err)
+	}
+
+	if got := newRsyncError(errors.New("signal: killed")).Code; got != -1 {
+		t.Errorf("Code without an exit status = %d, want -1", got)
+	}
+	if got := (&RsyncError{Code: 99}).Reason(); got != "" {
+		t.Errorf("Reason() for an undocumented code = %q, want empty", got)
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	err := fmt.Errorf("resolving conflicts: %w", conflictError("a.txt", "error keeping the dest version of %s", "a.txt"))
+
+	if !errors.Is(err, ErrConflictUnresolved) {
+		t.Errorf("errors.Is(%v, ErrConflictUnresolved) = false", err)
+	}
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("errors.As(%v, *ConflictError) = false", err)
+	}
+	if conflictErr.Path != "a.txt" {
+		t.Errorf("Path = %q, want a.txt", conflictErr.Path)
+	}
+	if got, want := conflictErr.Error(), "error keeping the dest version of a.txt"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestSyncMissingSource(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(t.TempDir(), "missing")
+	err = NewRunner(logger).SyncContext(context.Background(), &Options{Source: source, Dest: t.TempDir(), Mode: "one-way"})
+
+	if !errors.Is(err, ErrSourceNotFound) {
+		t.Fatalf("SyncContext error = %v, want ErrSourceNotFound", err)
+	}
+	var rsyncErr *RsyncError
+	if errors.As(err, &rsyncErr) {
+		t.Errorf("SyncContext ran rsync for a missing source: %v", err)
+	}
+	if !strings.Contains(err.Error(), source) {
+		t.Errorf("SyncContext error = %q, want it to name %s", err, source)
+	}
+}
@@ -0,0 +1,118 @@
+package rsync
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Estimate is the projected size of a one-way sync, computed by comparing the trees directly
+type Estimate struct {
+	FilesCreated int
+	FilesUpdated int
+	FilesDeleted int
+	// TotalSize is the bytes to transfer: the full source size of each created or updated
+	// file, since that is what rsync sends when copying whole files locally
+	TotalSize int64
+}
+
+// Transfers is the number of files that would be copied
+func (e Estimate) Transfers() int {
+	return e.FilesCreated + e.FilesUpdated
+}
+
+// quickCheckDiffers reports whether rsync would update a file: by default when its size or
+// modification time differs, only on a size difference with SizeOnly, and always with IgnoreTimes
+func quickCheckDiffers(opts *Options, srcSize int64, srcModTime time.Time, destSize int64, destModTime time.Time) bool {
+	switch {
+	case opts.IgnoreTimes:
+		return true
+	case opts.SizeOnly:
+		return srcSize != destSize
+	default:
+		return srcSize != destSize || !srcModTime.Equal(destModTime)
+	}
+}
+
+// EstimateSync walks the local source and dest and projects what a one-way sync would do,
+// without spawning rsync. A file is an update when rsync's quick check says so (see
+// quickCheckDiffers); dest files outside the filtered source would be deleted.
+func (r *Runner) EstimateSync(opts *Options) (Estimate, error) {
+	var est Estimate
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		return est, fmt.Errorf("estimates need a local source and dest")
+	}
+
+	entries, err := r.ListIncluded(opts)
+	if err != nil {
+		return est, fmt.Errorf("error listing source: %w", err)
+	}
+
+	included := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		included[entry.Path] = true
+		if entry.IsDir {
+			continue
+		}
+
+		srcInfo, err := os.Stat(localPath(opts.Source, entry.Path))
+		if err != nil {
+			return est, err
+		}
+		destInfo, err := os.Stat(localPath(opts.Dest, entry.Path))
+		switch {
+		case os.IsNotExist(err) || err == nil && destInfo.IsDir():
+			est.FilesCreated++
+		case err != nil:
+			return est, err
+		case quickCheckDiffers(opts, srcInfo.Size(), srcInfo.ModTime(), destInfo.Size(), destInfo.ModTime()):
+			est.FilesUpdated++
+		default:
+			continue
+		}
+		est.TotalSize += srcInfo.Size()
+	}
+
+	// A missing dest just means everything is created
+	if _, err := os.Stat(opts.Dest); os.IsNotExist(err) {
+		return est, nil
+	}
+	err = filepath.WalkDir(opts.Dest, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == opts.Dest {
+			return nil
+		}
+		relPath, err := filepath.Rel(opts.Dest, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if included[relPath] {
+			return nil
+		}
+		if d.IsDir() {
+			// Everything under a directory that isn't being synced goes with it
+			est.FilesDeleted += countFiles(path)
+			return filepath.SkipDir
+		}
+		est.FilesDeleted++
+		return nil
+	})
+	return est, err
+}
+
+// countFiles counts the non-directory entries under root, ignoring unreadable subtrees
+func countFiles(root string) int {
+	count := 0
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
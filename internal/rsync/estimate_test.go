@@ -0,0 +1,30 @@
+package rsync
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuickCheckDiffers(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+	tests := []struct {
+		name     string
+		opts     Options
+		destSize int64
+		destTime time.Time
+		want     bool
+	}{
+		{"default, identical", Options{}, 10, now, false},
+		{"default, older", Options{}, 10, earlier, true},
+		{"default, resized", Options{}, 12, now, true},
+		{"size-only, older", Options{SizeOnly: true}, 10, earlier, false},
+		{"size-only, resized", Options{SizeOnly: true}, 12, now, true},
+		{"ignore-times, identical", Options{IgnoreTimes: true}, 10, now, true},
+	}
+	for _, tt := range tests {
+		if got := quickCheckDiffers(&tt.opts, 10, now, tt.destSize, tt.destTime); got != tt.want {
+			t.Errorf("%s: quickCheckDiffers = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
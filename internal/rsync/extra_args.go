@@ -0,0 +1,88 @@
+package rsync
+
+import (
+	"errors"
+	"strings"
+)
+
+// managedFlags are the rsync options sync-tools sets itself; passing them through
+// --rsync-extra-args can contradict what the sync mode, filters, or change tracking expect
+var managedFlags = []string{
+	"--archive", "--verbose", "--delete", "--delete-excluded", "--out-format",
+	"--dry-run", "--filter", "--exclude", "--include", "--files-from",
+	"--password-file", "--rsync-path",
+}
+
+// SplitArgs splits a command-line string into arguments the way a POSIX shell would,
+// honoring single quotes, double quotes, and backslash escapes, so
+// `--chmod=D755 --rsh="ssh -p 2222"` yields two arguments
+func SplitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, c := range s {
+		switch {
+		case escaped:
+			current.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\\':
+			escaped = true
+			inArg = true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inArg = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("trailing backslash in rsync arguments")
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in rsync arguments")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// managedFlagConflicts returns the extra arguments that repeat or override a flag sync-tools manages
+func managedFlagConflicts(extra []string) []string {
+	var conflicts []string
+	for _, arg := range extra {
+		name, _, _ := strings.Cut(arg, "=")
+		for _, flag := range managedFlags {
+			if name == flag || name == "--no-"+strings.TrimPrefix(flag, "--") {
+				conflicts = append(conflicts, arg)
+				break
+			}
+		}
+	}
+	return conflicts
+}
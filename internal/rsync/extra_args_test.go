@@ -0,0 +1,46 @@
+package rsync
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"--chmod=D755", []string{"--chmod=D755"}},
+		{"  --chmod=D755   --bwlimit=1000 ", []string{"--chmod=D755", "--bwlimit=1000"}},
+		{`--rsh="ssh -p 2222" --partial`, []string{"--rsh=ssh -p 2222", "--partial"}},
+		{`--rsh='ssh -o "StrictHostKeyChecking no"'`, []string{`--rsh=ssh -o "StrictHostKeyChecking no"`}},
+		{`--exclude=a\ b`, []string{"--exclude=a b"}},
+		{`""`, []string{""}},
+	}
+
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.input)
+		if err != nil {
+			t.Errorf("SplitArgs(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{`--rsh="ssh`, `--rsh='ssh`, `--partial\`} {
+		if _, err := SplitArgs(input); err == nil {
+			t.Errorf("SplitArgs(%q) accepted malformed input", input)
+		}
+	}
+}
+
+func TestManagedFlagConflicts(t *testing.T) {
+	got := managedFlagConflicts([]string{"--chmod=D755", "--delete", "--no-delete-excluded", "--out-format=%n", "--deleted"})
+	want := []string{"--delete", "--no-delete-excluded", "--out-format=%n"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("managedFlagConflicts = %q, want %q", got, want)
+	}
+}
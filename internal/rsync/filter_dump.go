@@ -0,0 +1,141 @@
+package rsync
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DamianReeves/sync-tools/internal/filters"
+)
+
+// filterRule is a generated rsync filter rule and the setting or file it came from, so
+// --dump-filters can say why a rule is there
+type filterRule struct {
+	line   string
+	origin string
+}
+
+// annotateRules attributes every line to origin
+func annotateRules(origin string, lines []string) []filterRule {
+	rules := make([]filterRule, 0, len(lines))
+	for _, line := range lines {
+		rules = append(rules, filterRule{line: line, origin: origin})
+	}
+	return rules
+}
+
+// ruleLines returns the rules as the lines of a filter file
+func ruleLines(rules []filterRule) []string {
+	var lines []string
+	for _, rule := range rules {
+		lines = append(lines, rule.line)
+	}
+	return lines
+}
+
+// rewriteRules applies a line-for-line rewrite such as filters.CaseInsensitiveLines,
+// keeping each rule's origin
+func rewriteRules(rules []filterRule, rewrite func([]string) []string) []filterRule {
+	lines := rewrite(ruleLines(rules))
+	rewritten := make([]filterRule, len(rules))
+	for i, rule := range rules {
+		rewritten[i] = filterRule{line: lines[i], origin: rule.origin}
+	}
+	return rewritten
+}
+
+// ignoreSource is the ignore patterns of one file or setting
+type ignoreSource struct {
+	origin   string
+	patterns []string
+}
+
+// excludeRules converts the ignore patterns of every source together, as a single ignore
+// list with unignores first, then attributes each line to the first source producing it
+func excludeRules(sources []ignoreSource) []filterRule {
+	var patterns []string
+	origins := map[string]string{}
+	for _, source := range sources {
+		patterns = append(patterns, source.patterns...)
+		for _, line := range filters.ExcludeFilterLines(source.patterns) {
+			if _, ok := origins[line]; !ok {
+				origins[line] = source.origin
+			}
+		}
+	}
+
+	var rules []filterRule
+	for _, line := range filters.ExcludeFilterLines(patterns) {
+		rules = append(rules, filterRule{line: line, origin: origins[line]})
+	}
+	return rules
+}
+
+// relativeRules re-anchors the rules for rsync --relative; see filters.RelativeLines
+func relativeRules(rules []filterRule, root string) []filterRule {
+	lines := filters.RelativeLines(ruleLines(rules), root)
+	added := len(lines) - len(rules)
+	relative := annotateRules("--relative", lines[:added])
+	for i, rule := range rules {
+		relative = append(relative, filterRule{line: lines[added+i], origin: rule.origin})
+	}
+	return relative
+}
+
+// dumpFilters writes the filter rules rsync is given to opts.DumpFilters, or to stderr for
+// "-". The dump is itself a valid filter file: each run of rules from the same origin is
+// introduced by a comment naming it.
+func (r *Runner) dumpFilters(opts *Options) error {
+	var source []filterRule
+	if opts.FilesFrom == "" {
+		var err error
+		if source, err = r.sourceFileRules(opts); err != nil {
+			return fmt.Errorf("error building source filter: %w", err)
+		}
+	}
+	dest := destFilterRules(opts)
+
+	w := io.Writer(os.Stderr)
+	if opts.DumpFilters != "-" {
+		file, err := os.Create(opts.DumpFilters)
+		if err != nil {
+			return fmt.Errorf("error writing filter dump: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	fmt.Fprintf(w, "# sync-tools filter rules: %s -> %s\n", opts.Source, opts.Dest)
+	fmt.Fprintln(w, "# rsync applies the first rule matching a path: source rules, then dest rules")
+	fmt.Fprintln(w, "\n# === source filter ===")
+	switch {
+	case opts.FilesFrom != "":
+		fmt.Fprintln(w, "# none: --files-from lists the files instead")
+	case len(source) == 0:
+		fmt.Fprintln(w, "# none")
+	}
+	writeFilterRules(w, source)
+
+	fmt.Fprintln(w, "\n# === dest filter ===")
+	if len(dest) == 0 {
+		fmt.Fprintln(w, "# none")
+	}
+	writeFilterRules(w, dest)
+
+	if opts.DumpFilters != "-" {
+		r.logger.Infof("Filter rules written to %s", opts.DumpFilters)
+	}
+	return nil
+}
+
+// writeFilterRules writes rules one per line, with a comment before each change of origin
+func writeFilterRules(w io.Writer, rules []filterRule) {
+	origin := ""
+	for _, rule := range rules {
+		if rule.origin != origin {
+			origin = rule.origin
+			fmt.Fprintf(w, "# from %s\n", origin)
+		}
+		fmt.Fprintln(w, rule.line)
+	}
+}
@@ -0,0 +1,76 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestDumpFilters(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, ".syncignore"), []byte("*.log\n!keep.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := &Options{
+		Source:      source,
+		Dest:        t.TempDir(),
+		IgnoreSrc:   []string{"*.tmp", "*.log"},
+		IgnoreDest:  []string{"*.bak"},
+		FilterRules: []string{"- *.swp"},
+		MaxDepth:    2,
+		DumpFilters: filepath.Join(t.TempDir(), "filters.txt"),
+	}
+
+	runner := NewRunner(logger)
+	if err := runner.dumpFilters(opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(opts.DumpFilters)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the comments, the dump is exactly what rsync is given
+	var lines []string
+	origins := map[string]string{}
+	origin := ""
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		switch {
+		case strings.HasPrefix(line, "# from "):
+			origin = strings.TrimPrefix(line, "# from ")
+		case line != "" && !strings.HasPrefix(line, "#"):
+			lines = append(lines, line)
+			origins[line] = origin
+		}
+	}
+	sourceLines, err := runner.sourceFilterLines(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := append(sourceLines, destFilterLines(opts)...); !reflect.DeepEqual(lines, want) {
+		t.Errorf("dumped rules = %q, want %q", lines, want)
+	}
+
+	tests := map[string]string{
+		"- /*/*/*":    "--max-depth",
+		"- /.git/":    "default (--include-git drops it)",
+		"+ /keep.log": ".syncignore",
+		"- *.log":     ".syncignore", // .syncignore names it before --ignore-src does
+		"- *.tmp":     "--ignore-src",
+		"- *.swp":     "--filter-rule",
+		"- *.bak":     "--ignore-dest",
+	}
+	for line, want := range tests {
+		if got := origins[line]; got != want {
+			t.Errorf("origin of %q = %q, want %q", line, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package rsync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestFilterRulesComeLast(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := &Options{
+		Source:      t.TempDir(),
+		Dest:        t.TempDir(),
+		IgnoreSrc:   []string{"*.tmp"},
+		IgnoreCase:  true,
+		FilterRules: []string{"+ keep.log", "- *.log"},
+	}
+
+	lines, err := NewRunner(logger).sourceFilterLines(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) < 3 {
+		t.Fatalf("sourceFilterLines = %q, want the generated rules followed by the raw ones", lines)
+	}
+	// Kept verbatim: --ignore-case must not fold them
+	if got := lines[len(lines)-2:]; !reflect.DeepEqual(got, opts.FilterRules) {
+		t.Errorf("last rules = %q, want %q", got, opts.FilterRules)
+	}
+}
+
+func TestValidateFilterRules(t *testing.T) {
+	for _, rule := range []string{"", "  ", "- a\n+ b"} {
+		if err := validateOptions(&Options{FilterRules: []string{rule}}); err == nil {
+			t.Errorf("validateOptions accepted filter rule %q", rule)
+		}
+	}
+	if err := validateOptions(&Options{FilterRules: []string{": .rsync-filter"}}); err != nil {
+		t.Errorf("validateOptions rejected a merge rule: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package rsync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// globalGitignorePath returns the user's global gitignore: git's core.excludesFile when it is
+// set, otherwise git's default of $XDG_CONFIG_HOME/git/ignore (~/.config/git/ignore). It
+// returns "" when there is none, including when git isn't installed and the default is missing.
+func globalGitignorePath() string {
+	path := ""
+	if gitAvailable() {
+		if output, err := exec.Command("git", "config", "--get", "core.excludesFile").Output(); err == nil {
+			path = expandHome(strings.TrimSpace(string(output)))
+		}
+	}
+
+	if path == "" {
+		base := os.Getenv("XDG_CONFIG_HOME")
+		if base == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return ""
+			}
+			base = filepath.Join(home, ".config")
+		}
+		path = filepath.Join(base, "git", "ignore")
+	}
+
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return ""
+	}
+	return path
+}
+
+// expandHome replaces a leading ~/ with the home directory, as git does for core.excludesFile
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
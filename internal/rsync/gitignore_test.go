@@ -0,0 +1,48 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGlobalGitignorePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	gitConfig := filepath.Join(home, "gitconfig")
+	t.Setenv("GIT_CONFIG_GLOBAL", gitConfig)
+	t.Chdir(home)
+
+	if got := globalGitignorePath(); got != "" {
+		t.Errorf("globalGitignorePath() with no global gitignore = %q, want \"\"", got)
+	}
+
+	// git's default location
+	defaultPath := filepath.Join(home, ".config", "git", "ignore")
+	if err := os.MkdirAll(filepath.Dir(defaultPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(defaultPath, []byte("*.swp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := globalGitignorePath(); got != defaultPath {
+		t.Errorf("globalGitignorePath() = %q, want the default %q", got, defaultPath)
+	}
+
+	// core.excludesFile, with git's ~ expansion, takes precedence
+	if !gitAvailable() {
+		t.Skip("git not installed")
+	}
+	configured := filepath.Join(home, "my-excludes")
+	if err := os.WriteFile(configured, []byte(".idea/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(gitConfig, []byte("[core]\n\texcludesFile = ~/my-excludes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := globalGitignorePath(); got != configured {
+		t.Errorf("globalGitignorePath() = %q, want core.excludesFile %q", got, configured)
+	}
+}
@@ -0,0 +1,71 @@
+package rsync
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/DamianReeves/sync-tools/internal/filters"
+)
+
+// ListedEntry is a source path that survives the filters
+type ListedEntry struct {
+	// Path is slash-separated and relative to the source root
+	Path  string `json:"path"`
+	IsDir bool   `json:"is_dir"`
+	Size  int64  `json:"size"`
+}
+
+// ListIncluded walks the source and returns the entries the current filters would transfer,
+// in walk order. Excluded directories are pruned, as rsync never descends into them.
+func (r *Runner) ListIncluded(opts *Options) ([]ListedEntry, error) {
+	var entries []ListedEntry
+	err := r.WalkIncluded(opts, func(relPath string, d fs.DirEntry) error {
+		entry := ListedEntry{Path: relPath, IsDir: d.IsDir()}
+		if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			entry.Size = info.Size()
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// WalkIncluded calls fn, in walk order, for each source entry the current filters would
+// transfer, with its slash-separated path relative to the source root. Excluded
+// directories are pruned, as rsync never descends into them.
+func (r *Runner) WalkIncluded(opts *Options, fn func(relPath string, d fs.DirEntry) error) error {
+	lines, err := r.sourceFilterLines(opts)
+	if err != nil {
+		return fmt.Errorf("error building source filter: %w", err)
+	}
+	// rsync evaluates the source rules first, then the dest rules
+	rules := filters.ParseRules(append(lines, destFilterLines(opts)...))
+
+	return filepath.WalkDir(opts.Source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == opts.Source {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(opts.Source, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if !filters.Match(rules, relPath, d.IsDir()).Included {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return fn(relPath, d)
+	})
+}
@@ -0,0 +1,81 @@
+package rsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DestManifest records a local dest as a sync left it, so a later sync can tell whether
+// anyone changed it out-of-band in between
+type DestManifest struct {
+	Dest     string    `json:"dest"`
+	Recorded time.Time `json:"recorded"`
+	// Files maps the filtered dest paths to their size and modification time, as compare lists them
+	Files map[string]ComparedSide `json:"files"`
+}
+
+// RecordDestManifest lists opts.Dest under the current filters; paths the filters leave out
+// are never touched by the sync, so changes to them don't matter
+func (r *Runner) RecordDestManifest(opts *Options) (*DestManifest, error) {
+	if IsRemotePath(opts.Dest) {
+		return nil, fmt.Errorf("a dest manifest needs a local dest")
+	}
+	files, err := r.listSide(opts, opts.Dest)
+	if err != nil {
+		return nil, err
+	}
+	return &DestManifest{Dest: opts.Dest, Recorded: time.Now(), Files: files}, nil
+}
+
+// CheckDestManifest compares the dest with manifest. OnlyInA lists paths removed since it was
+// recorded, OnlyInB paths added, and Differing paths modified.
+func (r *Runner) CheckDestManifest(opts *Options, manifest *DestManifest) (Comparison, error) {
+	current, err := r.RecordDestManifest(opts)
+	if err != nil {
+		return Comparison{}, err
+	}
+	// An edit shows up in the size or modification time, whatever --size-only or
+	// --ignore-times say about the transfer itself
+	return compareSides(&Options{}, manifest.Files, current.Files), nil
+}
+
+// SaveDestManifest writes manifest to path, replacing any earlier one
+func SaveDestManifest(path string, manifest *DestManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding dest manifest: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating manifest directory: %w", err)
+	}
+
+	// Write then rename, so an interrupted write can't leave a truncated manifest
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("error writing dest manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing dest manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadDestManifest reads a manifest written by SaveDestManifest, returning nil when there is none
+func LoadDestManifest(path string) (*DestManifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading dest manifest: %w", err)
+	}
+	var manifest DestManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error reading dest manifest %s: %w", path, err)
+	}
+	return &manifest, nil
+}
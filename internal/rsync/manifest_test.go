@@ -0,0 +1,76 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestDestManifestDetectsChanges(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest := t.TempDir()
+	for _, name := range []string{"kept.txt", "edited.txt", "removed.txt"} {
+		if err := os.WriteFile(filepath.Join(dest, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runner := NewRunner(logger)
+	opts := &Options{Dest: dest}
+	recorded, err := runner.RecordDestManifest(opts)
+	if err != nil {
+		t.Fatalf("RecordDestManifest returned error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "manifests", "dest.json")
+	if err := SaveDestManifest(path, recorded); err != nil {
+		t.Fatalf("SaveDestManifest returned error: %v", err)
+	}
+	manifest, err := LoadDestManifest(path)
+	if err != nil || manifest == nil {
+		t.Fatalf("LoadDestManifest = %v, %v; want the saved manifest", manifest, err)
+	}
+
+	cmp, err := runner.CheckDestManifest(opts, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmp.OnlyInA)+len(cmp.OnlyInB)+len(cmp.Differing) != 0 || cmp.Identical != 3 {
+		t.Errorf("CheckDestManifest on an unchanged dest = %+v, want 3 identical files", cmp)
+	}
+
+	if err := os.WriteFile(filepath.Join(dest, "edited.txt"), []byte("hotfix on the mirror"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dest, "removed.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dest, "added.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmp, err = runner.CheckDestManifest(opts, manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmp.OnlyInA) != 1 || cmp.OnlyInA[0].Path != "removed.txt" {
+		t.Errorf("removed paths = %+v, want removed.txt", cmp.OnlyInA)
+	}
+	if len(cmp.OnlyInB) != 1 || cmp.OnlyInB[0].Path != "added.txt" {
+		t.Errorf("added paths = %+v, want added.txt", cmp.OnlyInB)
+	}
+	if len(cmp.Differing) != 1 || cmp.Differing[0].Path != "edited.txt" {
+		t.Errorf("modified paths = %+v, want edited.txt", cmp.Differing)
+	}
+}
+
+func TestLoadDestManifestMissing(t *testing.T) {
+	manifest, err := LoadDestManifest(filepath.Join(t.TempDir(), "none.json"))
+	if manifest != nil || err != nil {
+		t.Errorf("LoadDestManifest of a missing file = %v, %v; want nil, nil", manifest, err)
+	}
+}
@@ -0,0 +1,57 @@
+package rsync
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// markedSourceDirs walks the source for directories holding one of the ExcludeIfPresent
+// marker files, like tar's --exclude-if-present. rsync has no such option, so they become
+// exclude rules; nothing below a marked directory is visited. A marked source root is an
+// error, since it would sync nothing and --delete would empty the dest.
+func (r *Runner) markedSourceDirs(opts *Options) ([]string, error) {
+	if IsRemotePath(opts.Source) {
+		return nil, fmt.Errorf("--exclude-if-present needs a local source to look for marker files")
+	}
+
+	var marked []string
+	err := filepath.WalkDir(opts.Source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		marker, ok := findMarker(path, opts.ExcludeIfPresent)
+		if !ok {
+			return nil
+		}
+		if path == opts.Source {
+			return fmt.Errorf("the source %s holds the marker %s, so --exclude-if-present would exclude everything", opts.Source, marker)
+		}
+
+		relPath, err := filepath.Rel(opts.Source, path)
+		if err != nil {
+			return err
+		}
+		r.logger.Debugf("Excluding %s: it holds %s", relPath, marker)
+		marked = append(marked, filepath.ToSlash(relPath))
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error looking for --exclude-if-present markers: %w", err)
+	}
+	return marked, nil
+}
+
+// findMarker returns the first of markers present in dir
+func findMarker(dir string, markers []string) (string, bool) {
+	for _, marker := range markers {
+		if _, err := os.Lstat(filepath.Join(dir, marker)); err == nil {
+			return marker, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,104 @@
+package rsync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OwnershipMismatch describes a destination entry whose owner differs from the expected owner
+type OwnershipMismatch struct {
+	Path        string
+	UID         int
+	GID         int
+	ExpectedUID int
+	ExpectedGID int
+}
+
+// parseOwner parses an expected owner in "uid:gid" form
+func parseOwner(owner string) (uid, gid int, err error) {
+	parts := strings.SplitN(owner, ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid owner %q (must be uid:gid)", owner)
+	}
+	uid, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid uid in owner %q: %w", owner, err)
+	}
+	gid, err = strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid gid in owner %q: %w", owner, err)
+	}
+	return uid, gid, nil
+}
+
+// findOwnershipMismatches walks the destination and compares each entry's owner against
+// the expected owner, or against the matching source entry when no owner is expected
+func findOwnershipMismatches(opts *Options) ([]OwnershipMismatch, int, error) {
+	var expectedUID, expectedGID int
+	if opts.ExpectedOwner != "" {
+		var err error
+		expectedUID, expectedGID, err = parseOwner(opts.ExpectedOwner)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var mismatches []OwnershipMismatch
+	checked := 0
+	err := filepath.Walk(opts.Dest, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(opts.Dest, path)
+		if err != nil || relPath == "." {
+			return err
+		}
+
+		uid, gid, ok := fileOwner(info)
+		if !ok {
+			return nil
+		}
+
+		wantUID, wantGID := expectedUID, expectedGID
+		if opts.ExpectedOwner == "" {
+			sourceInfo, err := os.Lstat(filepath.Join(opts.Source, relPath))
+			if err != nil {
+				// Dest-only entries have no source owner to compare against
+				return nil
+			}
+			wantUID, wantGID, _ = fileOwner(sourceInfo)
+		}
+
+		checked++
+		if uid != wantUID || gid != wantGID {
+			mismatches = append(mismatches, OwnershipMismatch{
+				Path:        filepath.ToSlash(relPath),
+				UID:         uid,
+				GID:         gid,
+				ExpectedUID: wantUID,
+				ExpectedGID: wantGID,
+			})
+		}
+		return nil
+	})
+
+	return mismatches, checked, err
+}
+
+// reportDestOwnership logs destination entries with unexpected ownership after a sync
+func (r *Runner) reportDestOwnership(opts *Options) error {
+	mismatches, checked, err := findOwnershipMismatches(opts)
+	if err != nil {
+		return fmt.Errorf("error building ownership report: %w", err)
+	}
+
+	for _, m := range mismatches {
+		r.logger.Warnf("Ownership mismatch: %s (uid=%d gid=%d, expected uid=%d gid=%d)",
+			m.Path, m.UID, m.GID, m.ExpectedUID, m.ExpectedGID)
+	}
+	r.logger.Infof("Ownership report: %d of %d entries differ", len(mismatches), checked)
+	return nil
+}
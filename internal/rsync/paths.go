@@ -0,0 +1,112 @@
+package rsync
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsRemotePath reports whether path uses rsync's remote host:path syntax, which also covers
+// daemon targets (rsync://host/module, host::module). Windows drive-letter paths such as
+// C:\proj or C:/proj are local even though they contain a colon.
+func IsRemotePath(path string) bool {
+	if isWindowsDrivePath(path) {
+		return false
+	}
+
+	colon := strings.Index(path, ":")
+	if colon <= 0 {
+		return false
+	}
+
+	// A separator before the colon means the colon is part of a local file name (./a:b)
+	return !strings.ContainsAny(path[:colon], `/\`)
+}
+
+// IsDaemonPath reports whether path names an rsync daemon module, either as an
+// rsync://host[:port]/module/path URL or in host::module/path form
+func IsDaemonPath(path string) bool {
+	if strings.HasPrefix(strings.ToLower(path), "rsync://") {
+		return true
+	}
+	if !IsRemotePath(path) {
+		return false
+	}
+	host, rest, _ := strings.Cut(path, ":")
+	return host != "" && strings.HasPrefix(rest, ":")
+}
+
+// isWindowsDrivePath reports whether path starts with a drive letter, e.g. C: or C:\
+func isWindowsDrivePath(path string) bool {
+	if len(path) < 2 || path[1] != ':' {
+		return false
+	}
+	letter := path[0]
+	if !(letter >= 'a' && letter <= 'z' || letter >= 'A' && letter <= 'Z') {
+		return false
+	}
+	return len(path) == 2 || path[2] == '\\' || path[2] == '/'
+}
+
+// rsyncPath normalizes a local path to forward slashes, which rsync expects on every platform
+func rsyncPath(path string) string {
+	if IsRemotePath(path) {
+		return path
+	}
+	return filepath.ToSlash(path)
+}
+
+// localPath joins a slash-separated path relative to root, as rsync and the listings report
+// them, onto a local root given in the platform's form, so C:\proj and a/b.txt give
+// C:\proj\a\b.txt on Windows
+func localPath(root, rel string) string {
+	return filepath.Join(root, filepath.FromSlash(rel))
+}
+
+// RelativeSource marks where the part of a local source that --relative recreates under the
+// dest begins, using rsync's /./ marker. A marker already in source is kept; otherwise a
+// relative source keeps its path as given below base, and an absolute one keeps all of it.
+func RelativeSource(source, base string) string {
+	slashed := filepath.ToSlash(source)
+	if before, after, ok := strings.Cut(slashed, "/./"); ok {
+		root := filepath.FromSlash(before)
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(base, root)
+		}
+		return filepath.FromSlash(filepath.ToSlash(root) + "/./" + path.Clean(after))
+	}
+	if filepath.IsAbs(source) {
+		return filepath.Clean(source)
+	}
+	return filepath.FromSlash(filepath.ToSlash(base) + "/./" + path.Clean(slashed))
+}
+
+// relativeRoot returns the slash-separated path --relative recreates under the dest: the part
+// of source after a /./ marker, or else the whole path below the root, host, or daemon module
+func relativeRoot(source string) string {
+	root := rsyncPath(source)
+	switch {
+	case strings.HasPrefix(strings.ToLower(root), "rsync://"):
+		// rsync://host[:port]/module/path
+		root = root[len("rsync://"):]
+		_, root, _ = strings.Cut(root, "/")
+		_, root, _ = strings.Cut(root, "/")
+	case IsDaemonPath(root):
+		// host::module/path
+		_, root, _ = strings.Cut(root, "::")
+		_, root, _ = strings.Cut(root, "/")
+	case IsRemotePath(root):
+		_, root, _ = strings.Cut(root, ":")
+	default:
+		root = strings.TrimPrefix(root, filepath.ToSlash(filepath.VolumeName(source)))
+	}
+
+	if _, after, ok := strings.Cut(root, "/./"); ok {
+		root = after
+	}
+	root = strings.TrimPrefix(path.Clean(root), "/")
+	if root == "." {
+		return ""
+	}
+	return root
+}
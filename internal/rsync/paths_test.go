@@ -0,0 +1,133 @@
+package rsync
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIsRemotePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"user@host:/srv/data", true},
+		{"host:data", true},
+		{"host:", true},
+		{`C:\proj`, false},
+		{"C:/proj", false},
+		{"c:", false},
+		{`\\server\share\dir`, false},
+		{"./a:b", false},
+		{"dir/a:b", false},
+		{"/abs/path", false},
+		{"relative/dir", false},
+		{":data", false},
+		{"rsync://mirror.example.com/module/path", true},
+		{"mirror.example.com::module/path", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsRemotePath(tt.path); got != tt.want {
+			t.Errorf("IsRemotePath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIsDaemonPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"rsync://mirror.example.com/module/path", true},
+		{"RSYNC://mirror.example.com:8873/module", true},
+		{"user@mirror.example.com::module/path", true},
+		{"mirror::module", true},
+		{"user@host:/srv/data", false},
+		{"host:data", false},
+		{"/abs/path", false},
+		{"./a::b", false},
+		{`C:\proj`, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsDaemonPath(tt.path); got != tt.want {
+			t.Errorf("IsDaemonPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRsyncPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"user@host:/srv/data", "user@host:/srv/data"},
+		{"/home/user/project", "/home/user/project"},
+	}
+
+	for _, tt := range tests {
+		if got := rsyncPath(tt.path); got != tt.want {
+			t.Errorf("rsyncPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLocalPath(t *testing.T) {
+	root := filepath.Join("home", "user", "project")
+	tests := []struct {
+		rel  string
+		want string
+	}{
+		{"notes.txt", filepath.Join(root, "notes.txt")},
+		{"docs/guide/intro.md", filepath.Join(root, "docs", "guide", "intro.md")},
+		{"docs/", filepath.Join(root, "docs")},
+	}
+
+	for _, tt := range tests {
+		if got := localPath(root, tt.rel); got != tt.want {
+			t.Errorf("localPath(%q, %q) = %q, want %q", root, tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeSource(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"projects/app", "/work/./projects/app"},
+		{"./projects/app/", "/work/./projects/app"},
+		{"/data/projects/app", "/data/projects/app"},
+		{"/data/./projects/app", "/data/./projects/app"},
+		{"data/./projects/app", "/work/data/./projects/app"},
+	}
+
+	for _, tt := range tests {
+		if got := filepath.ToSlash(RelativeSource(filepath.FromSlash(tt.source), filepath.FromSlash("/work"))); got != tt.want {
+			t.Errorf("RelativeSource(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestRelativeRoot(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"/data/projects/app", "data/projects/app"},
+		{"/data/projects/app/", "data/projects/app"},
+		{"/work/./projects/app", "projects/app"},
+		{"/work/./.", ""},
+		{"/work/./../app", "../app"},
+		{"user@host:/srv/data", "srv/data"},
+		{"host:data", "data"},
+		{"host::module/data/app", "data/app"},
+		{"rsync://host:8873/module/data", "data"},
+	}
+
+	for _, tt := range tests {
+		if got := relativeRoot(tt.source); got != tt.want {
+			t.Errorf("relativeRoot(%q) = %q, want %q", tt.source, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package rsync
+
+import "testing"
+
+func TestRsyncPathWindows(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`C:\proj\src`, "C:/proj/src"},
+		{`C:/proj\mixed`, "C:/proj/mixed"},
+		{"host:/srv/data", "host:/srv/data"},
+	}
+
+	for _, tt := range tests {
+		if got := rsyncPath(tt.path); got != tt.want {
+			t.Errorf("rsyncPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLocalPathWindows(t *testing.T) {
+	tests := []struct {
+		root string
+		rel  string
+		want string
+	}{
+		{`C:\proj`, "docs/guide/intro.md", `C:\proj\docs\guide\intro.md`},
+		{`C:/proj\mixed`, "a/b.txt", `C:\proj\mixed\a\b.txt`},
+		{`\\server\share\proj`, "a/b.txt", `\\server\share\proj\a\b.txt`},
+	}
+
+	for _, tt := range tests {
+		if got := localPath(tt.root, tt.rel); got != tt.want {
+			t.Errorf("localPath(%q, %q) = %q, want %q", tt.root, tt.rel, got, tt.want)
+		}
+	}
+}
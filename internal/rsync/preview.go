@@ -0,0 +1,293 @@
+package rsync
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/go-udiff"
+)
+
+// PreviewFormats are the --preview-format values. git is the default; the others are
+// rendered by the built-in diff and don't need git.
+var PreviewFormats = []string{"git", "unified", "side-by-side", "stat"}
+
+// defaultPreviewWidth is the side-by-side width when $COLUMNS isn't set, as for diff -y
+const defaultPreviewWidth = 130
+
+// statGraphWidth is the most +/- characters a --preview-format stat line draws
+const statGraphWidth = 40
+
+// previewOutput renders the diff from dest to source in format
+func previewOutput(ctx context.Context, opts *Options, format string) ([]byte, error) {
+	if format == "git" {
+		cmd := exec.CommandContext(ctx, "git", "diff", "--no-index", "--no-prefix", "--color=always", opts.Dest, opts.Source)
+		cmd.Dir = filepath.Dir(opts.Source)
+		output, err := cmd.Output()
+		// git diff returns exit code 1 when there are differences, which is expected
+		if exitErr, ok := err.(*exec.ExitError); err != nil && !(ok && exitErr.ExitCode() == 1) {
+			return nil, fmt.Errorf("git diff failed: %w", err)
+		}
+		return output, nil
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "side-by-side":
+		err = writeSideBySideDiff(&buf, opts, previewWidth())
+	case "stat":
+		err = writeDiffStat(&buf, opts)
+	default:
+		err = writeUnifiedDiff(&buf, opts)
+	}
+	return buf.Bytes(), err
+}
+
+// page shows output through less when it's available, or prints it directly
+func (r *Runner) page(output []byte) error {
+	if _, err := exec.LookPath("less"); err != nil {
+		r.logger.Debug("Pager not available, displaying preview directly")
+		_, err := os.Stdout.Write(output)
+		return err
+	}
+
+	// Use less with options similar to git diff
+	lessCmd := exec.Command("less", "-R", "-FX")
+	lessCmd.Stdin = bytes.NewReader(output)
+	lessCmd.Stdout = os.Stdout
+	lessCmd.Stderr = os.Stderr
+
+	r.logger.Debug("Displaying preview with pager (press 'q' to quit)")
+	return lessCmd.Run()
+}
+
+// previewWidth returns the terminal width from $COLUMNS, or defaultPreviewWidth
+func previewWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns >= 40 {
+		return columns
+	}
+	return defaultPreviewWidth
+}
+
+// fileHunks returns the hunks turning destPath's contents into srcPath's. binary is set
+// instead when either side looks binary; both are empty when the files are the same.
+func fileHunks(relPath, destPath, srcPath string) (hunks []*udiff.Hunk, binary bool, err error) {
+	oldText, err := readDiffSide(destPath)
+	if err != nil {
+		return nil, false, err
+	}
+	newText, err := readDiffSide(srcPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if oldText == newText {
+		return nil, false, nil
+	}
+	if isBinary(oldText) || isBinary(newText) {
+		return nil, true, nil
+	}
+
+	diff, err := udiff.ToUnifiedDiff("a/"+relPath, "b/"+relPath, oldText, udiff.Strings(oldText, newText), udiff.DefaultContextLines)
+	if err != nil {
+		return nil, false, fmt.Errorf("error diffing %s: %w", relPath, err)
+	}
+	return diff.Hunks, false, nil
+}
+
+// fileState describes how a file changes, from which sides of the diff it exists on
+func fileState(destPath, srcPath string) string {
+	switch {
+	case destPath == "":
+		return "new file"
+	case srcPath == "":
+		return "deleted"
+	default:
+		return "modified"
+	}
+}
+
+// writeSideBySideDiff writes each changed file with the dest on the left and the source on
+// the right, marking rows as diff -y does: | changed, < only in dest, > only in source
+func writeSideBySideDiff(w io.Writer, opts *Options, width int) error {
+	column := (width - 3) / 2
+	return forEachDiffFile(w, opts, func(relPath, destPath, srcPath string) error {
+		hunks, binary, err := fileHunks(relPath, destPath, srcPath)
+		if err != nil {
+			return err
+		}
+		if binary {
+			fmt.Fprintf(w, "# Binary files differ: %s\n", relPath)
+			return nil
+		}
+		if len(hunks) == 0 {
+			if destPath == "" || srcPath == "" {
+				fmt.Fprintf(w, "=== %s (%s, empty)\n\n", relPath, fileState(destPath, srcPath))
+			}
+			return nil
+		}
+
+		fmt.Fprintf(w, "=== %s (%s)\n", relPath, fileState(destPath, srcPath))
+		fmt.Fprintf(w, "%-*s   %s\n", column, "dest", "source")
+		delta := 0
+		for _, hunk := range hunks {
+			var deleted, inserted []string
+			flush := func() {
+				for i := 0; i < len(deleted) || i < len(inserted); i++ {
+					switch {
+					case i >= len(deleted):
+						writeSideBySideRow(w, column, "", '>', inserted[i])
+					case i >= len(inserted):
+						writeSideBySideRow(w, column, deleted[i], '<', "")
+					default:
+						writeSideBySideRow(w, column, deleted[i], '|', inserted[i])
+					}
+				}
+				deleted, inserted = nil, nil
+			}
+
+			oldCount, newCount := 0, 0
+			for _, line := range hunk.Lines {
+				if line.Kind != udiff.Insert {
+					oldCount++
+				}
+				if line.Kind != udiff.Delete {
+					newCount++
+				}
+			}
+			fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", hunkStart(hunk.FromLine, oldCount), oldCount,
+				hunkStart(hunk.FromLine+delta, newCount), newCount)
+			delta += newCount - oldCount
+
+			for _, line := range hunk.Lines {
+				switch line.Kind {
+				case udiff.Delete:
+					deleted = append(deleted, line.Content)
+				case udiff.Insert:
+					inserted = append(inserted, line.Content)
+				default:
+					flush()
+					writeSideBySideRow(w, column, line.Content, ' ', line.Content)
+				}
+			}
+			flush()
+		}
+		fmt.Fprintln(w)
+		return nil
+	})
+}
+
+// hunkStart returns the line a hunk header gives for one side; as in unified diffs, a side
+// with no lines names the line before the hunk
+func hunkStart(line, count int) int {
+	if count == 0 {
+		return line - 1
+	}
+	return line
+}
+
+// writeSideBySideRow writes one row, fitting each side into column characters
+func writeSideBySideRow(w io.Writer, column int, left string, marker rune, right string) {
+	row := fmt.Sprintf("%-*s %c %s", column, fitColumn(left, column), marker, fitColumn(right, column))
+	fmt.Fprintln(w, strings.TrimRight(row, " "))
+}
+
+// fitColumn drops a line's newline, expands its tabs, and cuts it to width characters
+func fitColumn(line string, width int) string {
+	line = strings.ReplaceAll(strings.TrimRight(line, "\r\n"), "\t", "    ")
+	if runes := []rune(line); len(runes) > width {
+		return string(runes[:width])
+	}
+	return line
+}
+
+// fileStat is one line of a diffstat
+type fileStat struct {
+	path       string
+	insertions int
+	deletions  int
+	binary     bool
+}
+
+// writeDiffStat writes a diffstat like git diff --stat: each changed file with its inserted
+// and deleted line counts, then the totals
+func writeDiffStat(w io.Writer, opts *Options) error {
+	var stats []fileStat
+	err := forEachDiffFile(w, opts, func(relPath, destPath, srcPath string) error {
+		hunks, binary, err := fileHunks(relPath, destPath, srcPath)
+		if err != nil {
+			return err
+		}
+		if !binary && len(hunks) == 0 && destPath != "" && srcPath != "" {
+			return nil
+		}
+
+		stat := fileStat{path: relPath, binary: binary}
+		for _, hunk := range hunks {
+			for _, line := range hunk.Lines {
+				switch line.Kind {
+				case udiff.Insert:
+					stat.insertions++
+				case udiff.Delete:
+					stat.deletions++
+				}
+			}
+		}
+		stats = append(stats, stat)
+		return nil
+	})
+	if err != nil || len(stats) == 0 {
+		return err
+	}
+
+	pathWidth, countWidth, most := 0, 1, 0
+	for _, stat := range stats {
+		pathWidth = max(pathWidth, len(stat.path))
+		countWidth = max(countWidth, len(strconv.Itoa(stat.insertions+stat.deletions)))
+		most = max(most, stat.insertions+stat.deletions)
+	}
+
+	var insertions, deletions int
+	for _, stat := range stats {
+		if stat.binary {
+			fmt.Fprintf(w, " %-*s | %*s\n", pathWidth, stat.path, countWidth, "Bin")
+			continue
+		}
+		plus, minus := stat.insertions, stat.deletions
+		if most > statGraphWidth {
+			plus = scaleStat(plus, most)
+			minus = scaleStat(minus, most)
+		}
+		graph := strings.Repeat("+", plus) + strings.Repeat("-", minus)
+		row := fmt.Sprintf(" %-*s | %*d %s", pathWidth, stat.path, countWidth, stat.insertions+stat.deletions, graph)
+		fmt.Fprintln(w, strings.TrimRight(row, " "))
+		insertions += stat.insertions
+		deletions += stat.deletions
+	}
+
+	fmt.Fprintf(w, " %d %s changed, %d %s(+), %d %s(-)\n", len(stats), plural(len(stats), "file", "files"),
+		insertions, plural(insertions, "insertion", "insertions"), deletions, plural(deletions, "deletion", "deletions"))
+	return nil
+}
+
+// scaleStat scales a line count to the graph width, keeping at least one mark for any change
+func scaleStat(count, most int) int {
+	if count == 0 {
+		return 0
+	}
+	return max(1, count*statGraphWidth/most)
+}
+
+// plural picks the singular or plural word for count
+func plural(count int, one, many string) string {
+	if count == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,99 @@
+package rsync
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePreviewTrees creates a source and dest with a modified, a created, and a deleted file
+func writePreviewTrees(t *testing.T) *Options {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"src/one.txt":     "a\nb\nc\n",
+		"src/sub/new.txt": "new\n",
+		"dst/one.txt":     "a\nB\nc\n",
+		"dst/gone.txt":    "old\nx\n",
+		"src/same.txt":    "same\n",
+		"dst/same.txt":    "same\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &Options{Source: filepath.Join(root, "src"), Dest: filepath.Join(root, "dst")}
+}
+
+func TestWriteDiffStat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeDiffStat(&buf, writePreviewTrees(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		" gone.txt    | 2 --",
+		" one.txt     | 2 +-",
+		" sub/new.txt | 1 +",
+		" 3 files changed, 2 insertions(+), 3 deletions(-)",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeDiffStat() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteSideBySideDiff(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeSideBySideDiff(&buf, writePreviewTrees(t), 23); err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"=== gone.txt (deleted)",
+		"dest         source",
+		"@@ -1,2 +0,0 @@",
+		"old        <",
+		"x          <",
+		"",
+		"=== one.txt (modified)",
+		"dest         source",
+		"@@ -1,3 +1,3 @@",
+		"a            a",
+		"B          | b",
+		"c            c",
+		"",
+		"=== sub/new.txt (new file)",
+		"dest         source",
+		"@@ -0,0 +1,1 @@",
+		"           > new",
+		"",
+	}, "\n") + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("writeSideBySideDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFitColumn(t *testing.T) {
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{"short\n", 10, "short"},
+		{"a\tb\n", 10, "a    b"},
+		{"much too long\n", 8, "much too"},
+		{"héllo wörld\n", 5, "héllo"},
+	}
+	for _, tt := range tests {
+		if got := fitColumn(tt.line, tt.width); got != tt.want {
+			t.Errorf("fitColumn(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package rsync
+
+import (
+	"os"
+	"syscall"
+)
+
+// stopProcess asks a process to exit with SIGTERM, which lets rsync remove its partial temp files
+func stopProcess(p *os.Process) error {
+	return p.Signal(syscall.SIGTERM)
+}
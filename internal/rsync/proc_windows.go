@@ -0,0 +1,10 @@
+//go:build windows
+
+package rsync
+
+import "os"
+
+// stopProcess kills the process outright, as Windows has no SIGTERM to deliver
+func stopProcess(p *os.Process) error {
+	return p.Kill()
+}
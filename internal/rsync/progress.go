@@ -0,0 +1,145 @@
+package rsync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// progressLogInterval is how often progress is logged when it can't be redrawn in place
+var progressLogInterval = 10 * time.Second
+
+// progressLine matches rsync's --info=progress2 status, e.g.
+// "  1,234,567  45%   12.34MB/s    0:00:12 (xfr#3, to-chk=10/20)". sync-tools passes
+// --human-readable, so the byte count is usually in units, e.g. "1.23G".
+var progressLine = regexp.MustCompile(`^([\d,.]+)([KMGTP]?)\s+(\d+)%\s+(\S+/s)\s+(\d+:\d{2}:\d{2})(?:\s+\(.*\))?$`)
+
+// Progress is one --info=progress2 status: the bytes moved so far, as counted and as rsync
+// printed them, the percentage of the whole transfer done, and rsync's current rate and
+// time remaining
+type Progress struct {
+	Bytes       int64
+	Transferred string
+	Percent     int
+	Rate        string
+	ETA         string
+}
+
+// parseProgress reports whether line is a progress2 status line
+func parseProgress(line string) (Progress, bool) {
+	m := progressLine.FindStringSubmatch(line)
+	if m == nil {
+		return Progress{}, false
+	}
+	bytes, ok := parseProgressBytes(m[1], m[2])
+	if !ok {
+		return Progress{}, false
+	}
+	percent, _ := strconv.Atoi(m[3])
+	return Progress{Bytes: bytes, Transferred: m[1] + m[2], Percent: percent, Rate: m[4], ETA: m[5]}, true
+}
+
+// parseProgressBytes reads a progress2 byte count: digits with thousands separators, or
+// with a unit suffix a decimal number of 1000s, as --human-readable prints it
+func parseProgressBytes(number, unit string) (int64, bool) {
+	if unit == "" {
+		n, err := strconv.ParseInt(strings.NewReplacer(",", "", ".", "").Replace(number), 10, 64)
+		return n, err == nil
+	}
+	// Locales with a decimal comma print 1,23G
+	f, err := strconv.ParseFloat(strings.ReplaceAll(number, ",", "."), 64)
+	if err != nil {
+		return 0, false
+	}
+	for range strings.Index("KMGTP", unit) + 1 {
+		f *= 1000
+	}
+	return int64(f), true
+}
+
+// String formats p for a status line, e.g. "45% done, 1.23G at 12.34MB/s, ETA 0:00:12"
+func (p Progress) String() string {
+	return fmt.Sprintf("%d%% done, %s at %s, ETA %s", p.Percent, p.Transferred, p.Rate, p.ETA)
+}
+
+// scanOutputLines splits rsync output at \n or \r, since progress2 redraws its status
+// line with a bare carriage return
+func scanOutputLines(data []byte, atEOF bool) (int, []byte, error) {
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// ShowProgress makes runs with Options.Progress report rsync's progress: redrawn in place on
+// w when it is a terminal, otherwise logged every progressLogInterval. Either way the run
+// ends with a log line giving the average rate.
+func (r *Runner) ShowProgress(w io.Writer, terminal bool) {
+	r.progressOut = w
+	r.progressTerminal = terminal
+}
+
+// showsProgress reports whether this run's progress goes to the ShowProgress writer
+func (r *Runner) showsProgress(opts *Options) bool {
+	return r.progressOut != nil && opts.Progress && !opts.DryRun
+}
+
+// progressReporter turns one rsync run's progress2 lines into status output
+type progressReporter struct {
+	runner *Runner
+	start  time.Time
+	last   Progress
+	seen   bool
+	logged time.Time
+	drawn  int
+}
+
+func (r *Runner) newProgressReporter() *progressReporter {
+	now := time.Now()
+	return &progressReporter{runner: r, start: now, logged: now}
+}
+
+// update shows p, redrawing the status line or logging it once the interval has passed
+func (p *progressReporter) update(progress Progress) {
+	p.last, p.seen = progress, true
+	status := progress.String()
+	if p.runner.progressTerminal {
+		// Pad over the rest of a longer previous status
+		fmt.Fprintf(p.runner.progressOut, "\r%-*s", p.drawn, status)
+		p.drawn = max(p.drawn, len(status))
+		return
+	}
+	if time.Since(p.logged) >= progressLogInterval {
+		p.runner.logger.Infof("Progress: %s", status)
+		p.logged = time.Now()
+	}
+}
+
+// clear blanks the status line so a log line can be written in its place; the next
+// update draws it again
+func (p *progressReporter) clear() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.runner.progressOut, "\r%s\r", strings.Repeat(" ", p.drawn))
+		p.drawn = 0
+	}
+}
+
+// finish ends the status line and logs the run's average rate
+func (p *progressReporter) finish() {
+	if p.drawn > 0 {
+		fmt.Fprintln(p.runner.progressOut)
+	}
+	if !p.seen {
+		return
+	}
+	elapsed := time.Since(p.start)
+	p.runner.logger.Infof("Transferred %d bytes in %s (average %.2f MB/s)",
+		p.last.Bytes, FormatDuration(elapsed), throughput(p.last.Bytes, elapsed))
+}
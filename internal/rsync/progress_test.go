@@ -0,0 +1,46 @@
+package rsync
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		line string
+		want Progress
+	}{
+		{"1,234,567  45%   12.34MB/s    0:00:12", Progress{1234567, "1,234,567", 45, "12.34MB/s", "0:00:12"}},
+		{"1.50G 100%   98.76MB/s    0:00:15 (xfr#20, to-chk=0/20)", Progress{1500000000, "1.50G", 100, "98.76MB/s", "0:00:15"}},
+		{"2,50M  10%    1,00MB/s    0:01:40 (xfr#1, ir-chk=1000/1020)", Progress{2500000, "2,50M", 10, "1,00MB/s", "0:01:40"}},
+	}
+	for _, tt := range tests {
+		got, ok := parseProgress(tt.line)
+		if !ok || got != tt.want {
+			t.Errorf("parseProgress(%q) = %+v, %v; want %+v", tt.line, got, ok, tt.want)
+		}
+	}
+
+	for _, line := range []string{">f+++++++++ 2,000 big.bin", "sending incremental file list", "total size is 0  speedup is 0.00"} {
+		if _, ok := parseProgress(line); ok {
+			t.Errorf("parseProgress(%q) matched, want no match", line)
+		}
+	}
+}
+
+func TestScanOutputLines(t *testing.T) {
+	scanner := bufio.NewScanner(strings.NewReader(">f+++++++++ 10 a.txt\n\r  5  50%  1.00kB/s  0:00:01\r  10 100%  1.00kB/s  0:00:00\nlast"))
+	scanner.Split(scanOutputLines)
+	var lines []string
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{">f+++++++++ 10 a.txt", "5  50%  1.00kB/s  0:00:01", "10 100%  1.00kB/s  0:00:00", "last"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("lines = %q, want %q", lines, want)
+	}
+}
@@ -0,0 +1,76 @@
+package rsync
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// propagateDeletes handles --propagate-deletes for a two-way sync. Without a record of the
+// previous sync, a file present on only one side may be new there or deleted from the other.
+// This option assumes the latter: source files missing from the dest are deleted from the
+// source instead of being copied over. Dest-only files need nothing extra, since the mirror
+// that follows already removes them.
+func (r *Runner) propagateDeletes(opts *Options) error {
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		return fmt.Errorf("--propagate-deletes needs a local source and dest")
+	}
+
+	// An empty or missing dest would make every source file look deleted
+	destEntries, err := os.ReadDir(opts.Dest)
+	if err != nil || len(destEntries) == 0 {
+		r.logger.Warnf("Destination %s is empty or missing; not propagating deletes", opts.Dest)
+		return nil
+	}
+
+	entries, err := r.ListIncluded(opts)
+	if err != nil {
+		return fmt.Errorf("error listing source: %w", err)
+	}
+	var missing []string
+	for _, entry := range entries {
+		if entry.IsDir {
+			continue
+		}
+		if _, err := os.Lstat(localPath(opts.Dest, entry.Path)); os.IsNotExist(err) {
+			missing = append(missing, entry.Path)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	r.logger.Infof("%d source files are missing from the destination and will be treated as deleted:", len(missing))
+	for _, path := range missing {
+		r.logger.Infof("  %s", path)
+	}
+	if opts.DryRun {
+		r.logger.Infof("Would delete %d files from the source (dry run)", len(missing))
+		return nil
+	}
+	if !opts.Yes && !r.confirmPropagateDeletes(len(missing)) {
+		r.logger.Info("Not propagating deletes; the files will be copied to the destination instead")
+		return nil
+	}
+
+	for _, path := range missing {
+		if err := os.Remove(localPath(opts.Source, path)); err != nil {
+			return fmt.Errorf("error deleting %s from source: %w", path, err)
+		}
+		r.logger.Infof("Deleted from source: %s", path)
+		r.stats.FilesDeleted++
+	}
+	return nil
+}
+
+// confirmPropagateDeletes asks before deleting source files, declining when stdin can't answer
+func (r *Runner) confirmPropagateDeletes(count int) bool {
+	fmt.Printf("\nDelete these %d files from the source instead of copying them? [y/N]: ", count)
+
+	var response string
+	if _, err := fmt.Scanln(&response); err != nil {
+		return false
+	}
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
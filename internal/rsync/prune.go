@@ -0,0 +1,39 @@
+package rsync
+
+import (
+	"fmt"
+	"os"
+)
+
+// emptySourceDirs lists the included directories that are empty in the source itself.
+// rsync --prune-empty-dirs would drop them along with the directories the filters emptied,
+// so they are protected to keep intentionally empty directories. A remote source can't be
+// walked, so there every empty directory is pruned.
+func (r *Runner) emptySourceDirs(opts *Options) ([]string, error) {
+	if IsRemotePath(opts.Source) {
+		r.logger.Debug("Remote source; --prune-empty-dirs will also prune directories that are empty in the source")
+		return nil, nil
+	}
+
+	entries, err := r.ListIncluded(opts)
+	if err != nil {
+		return nil, fmt.Errorf("error listing source: %w", err)
+	}
+	var empty []string
+	for _, entry := range entries {
+		if !entry.IsDir {
+			continue
+		}
+		children, err := os.ReadDir(localPath(opts.Source, entry.Path))
+		if err != nil {
+			return nil, err
+		}
+		if len(children) == 0 {
+			empty = append(empty, entry.Path)
+		}
+	}
+	if len(empty) > 0 {
+		r.logger.Debugf("Keeping %d directories that are empty in the source", len(empty))
+	}
+	return empty, nil
+}
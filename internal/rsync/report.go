@@ -0,0 +1,99 @@
+package rsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// IsMarkdownReport reports whether a --report path selects a markdown report (.md or .markdown)
+func IsMarkdownReport(report string) bool {
+	ext := strings.ToLower(filepath.Ext(report))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// plan dry-runs opts and writes the changes it would make to opts.Plan as markdown,
+// so a SyncFile operation can be reviewed instead of executed
+func (r *Runner) plan(ctx context.Context, opts *Options) error {
+	r.logger.Infof("Planning sync: %s -> %s (output: %s)", opts.Source, opts.Dest, opts.Plan)
+	planOpts := *opts
+	planOpts.Plan = ""
+	planOpts.Report = ""
+	planOpts.DryRun = true
+
+	r.keepChanges = true
+	err := r.SyncContext(ctx, &planOpts)
+	r.keepChanges = false
+	if err != nil {
+		return err
+	}
+
+	if err := writeChangeReport(opts.Plan, "Sync Plan", opts, r.stats, r.changes); err != nil {
+		return fmt.Errorf("error writing plan: %w", err)
+	}
+	r.logger.Infof("Plan written to %s; nothing was changed", opts.Plan)
+	return nil
+}
+
+// writeChangeReport writes a markdown report of one run: its endpoints, the change counts,
+// and every path rsync created, updated, or deleted. With IncludeUnchanged it also lists the
+// files that were already up to date, apart from the changes.
+func writeChangeReport(path, title string, opts *Options, stats SyncStats, all []Change) error {
+	var changes, unchanged []Change
+	for _, change := range all {
+		if change.Kind == ChangeUnchanged {
+			unchanged = append(unchanged, change)
+		} else {
+			changes = append(changes, change)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	fmt.Fprintf(&sb, "- **Source:** %s\n", opts.Source)
+	fmt.Fprintf(&sb, "- **Dest:** %s\n", opts.Dest)
+	fmt.Fprintf(&sb, "- **Mode:** %s\n", opts.Mode)
+	fmt.Fprintf(&sb, "- **Dry run:** %v\n", opts.DryRun)
+	fmt.Fprintf(&sb, "- **Generated:** %s\n\n", time.Now().Format(time.RFC3339))
+
+	if opts.IncludeUnchanged {
+		sb.WriteString("| Created | Updated | Deleted | Unchanged | Dirs created | Dirs deleted | Bytes |\n")
+		sb.WriteString("|---------|---------|---------|-----------|--------------|--------------|-------|\n")
+		fmt.Fprintf(&sb, "| %d | %d | %d | %d | %d | %d | %d |\n\n", stats.FilesCreated, stats.FilesUpdated,
+			stats.FilesDeleted, stats.FilesUnchanged, stats.DirsCreated, stats.DirsDeleted, stats.BytesTransferred)
+	} else {
+		sb.WriteString("| Created | Updated | Deleted | Dirs created | Dirs deleted | Bytes |\n")
+		sb.WriteString("|---------|---------|---------|--------------|--------------|-------|\n")
+		fmt.Fprintf(&sb, "| %d | %d | %d | %d | %d | %d |\n\n", stats.FilesCreated, stats.FilesUpdated,
+			stats.FilesDeleted, stats.DirsCreated, stats.DirsDeleted, stats.BytesTransferred)
+	}
+
+	sb.WriteString("## Changes\n\n")
+	if len(changes) == 0 {
+		sb.WriteString("No changes.\n")
+	} else {
+		sb.WriteString("| Change | Path | Size |\n")
+		sb.WriteString("|--------|------|------|\n")
+		for _, change := range changes {
+			fmt.Fprintf(&sb, "| %s | %s | %d |\n", change.Kind, change.Path, change.Size)
+		}
+	}
+
+	if opts.IncludeUnchanged {
+		sb.WriteString("\n## Unchanged\n\n")
+		if len(unchanged) == 0 {
+			sb.WriteString("No unchanged files.\n")
+		} else {
+			sb.WriteString("| Path | Size |\n")
+			sb.WriteString("|------|------|\n")
+			for _, change := range unchanged {
+				fmt.Fprintf(&sb, "| %s | %d |\n", change.Path, change.Size)
+			}
+		}
+	}
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
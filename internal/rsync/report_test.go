@@ -0,0 +1,74 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsMarkdownReport(t *testing.T) {
+	for report, want := range map[string]bool{
+		"report.md": true, "REPORT.MARKDOWN": true, "changes.patch": false, "notes.txt": false, "": false,
+	} {
+		if got := IsMarkdownReport(report); got != want {
+			t.Errorf("IsMarkdownReport(%q) = %v, want %v", report, got, want)
+		}
+	}
+}
+
+func TestWriteChangeReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md")
+	changes := []Change{
+		{Kind: ChangeCreated, Path: "new.txt", Size: 12},
+		{Kind: ChangeDeleted, Path: "old/", IsDir: true},
+	}
+	var stats SyncStats
+	for _, change := range changes {
+		stats.record(change)
+	}
+	opts := &Options{Source: "/src", Dest: "/dest", Mode: "one-way", DryRun: true}
+	if err := writeChangeReport(path, "Sync Plan", opts, stats, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# Sync Plan", "- **Source:** /src", "| 1 | 0 | 0 | 0 | 1 | 12 |", "| created | new.txt | 12 |", "| deleted | old/ | 0 |"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report is missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestWriteChangeReportUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md")
+	changes := []Change{
+		{Kind: ChangeUpdated, Path: "edited.txt", Size: 7},
+		{Kind: ChangeUnchanged, Path: "same.txt", Size: 30},
+	}
+	var stats SyncStats
+	for _, change := range changes {
+		stats.record(change)
+	}
+	opts := &Options{Source: "/src", Dest: "/dest", Mode: "one-way", DryRun: true, IncludeUnchanged: true}
+	if err := writeChangeReport(path, "Sync Plan", opts, stats, changes); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{"| 0 | 1 | 0 | 1 | 0 | 0 | 7 |", "| updated | edited.txt | 7 |", "## Unchanged", "| same.txt | 30 |"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "| unchanged |") {
+		t.Errorf("report lists an unchanged file among the changes:\n%s", report)
+	}
+}
@@ -0,0 +1,130 @@
+package rsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/filters"
+)
+
+// quotedPathPattern captures the first quoted path in an rsync error line
+var quotedPathPattern = regexp.MustCompile(`"([^"]+)"`)
+
+// parseFailedFile returns the source-relative path named by an rsync per-file error line, e.g.
+// rsync: [sender] send_files failed to open "/src/a.txt": Permission denied (13)
+// file has vanished: "/src/b.txt"
+// It returns "" for lines that don't name a failed file.
+func parseFailedFile(line string, opts *Options) string {
+	line = strings.TrimSpace(line)
+	isFileError := strings.HasPrefix(line, "rsync:") && strings.Contains(line, "failed")
+	if !isFileError && !strings.HasPrefix(line, "file has vanished:") {
+		return ""
+	}
+	// mkstemp errors name receiver temp files rather than the file being transferred
+	if strings.Contains(line, "mkstemp") {
+		return ""
+	}
+	match := quotedPathPattern.FindStringSubmatch(line)
+	if match == nil {
+		return ""
+	}
+
+	path := match[1]
+	for _, root := range []string{opts.Source, opts.Dest} {
+		prefix := strings.TrimSuffix(root, "/") + "/"
+		if strings.HasPrefix(path, prefix) {
+			return strings.TrimPrefix(path, prefix)
+		}
+	}
+	if filepath.IsAbs(path) {
+		return ""
+	}
+	return path
+}
+
+// retryFailedFiles re-runs rsync with --files-from targeting only the files that failed,
+// up to opts.RetryFiles times, and returns any that never succeed
+func (r *Runner) retryFailedFiles(ctx context.Context, opts *Options, sourceFilter, destFilter string, failed []string) ([]string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= opts.RetryFiles; attempt++ {
+		r.logger.Warnf("rsync reported %d failed files, retrying (attempt %d/%d)", len(failed), attempt, opts.RetryFiles)
+
+		listFile, err := filters.BuildFilesFromList(strings.NewReader(strings.Join(failed, "\n")))
+		if err != nil {
+			return nil, fmt.Errorf("error writing retry file list: %w", err)
+		}
+
+		cmd := r.buildRsyncCommand(ctx, opts, sourceFilter, destFilter, listFile.Path())
+		failed, err = r.executeRsync(ctx, cmd, opts)
+		r.closeFilter(listFile)
+		if err == nil {
+			r.logger.Infof("Retry attempt %d transferred all remaining files", attempt)
+			return nil, nil
+		}
+		if len(failed) == 0 {
+			// The failure is not attributable to individual files, so retrying won't help
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	for _, file := range failed {
+		r.logger.Errorf("Failed to transfer after %d retries: %s", opts.RetryFiles, file)
+	}
+	return failed, fmt.Errorf("%d files failed after %d retries: %w", len(failed), opts.RetryFiles, lastErr)
+}
+
+// interruptedTransferCodes are the rsync exit codes for a transfer cut off part way: 10 and
+// 12 for a lost connection, 30 and 35 for timeouts
+var interruptedTransferCodes = []int{10, 12, 30, 35}
+
+// interrupted reports whether err is rsync losing its connection rather than failing outright
+func interrupted(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && slices.Contains(interruptedTransferCodes, exitErr.ExitCode())
+}
+
+// resumeInterrupted re-runs a resumable transfer that was cut off, up to opts.RetryFiles
+// times. --partial kept what each attempt sent, so the next one picks up where it stopped.
+func (r *Runner) resumeInterrupted(ctx context.Context, opts *Options, sourceFilter, destFilter, filesFrom string, err error) ([]string, error) {
+	var failed []string
+	for attempt := 1; attempt <= opts.RetryFiles && err != nil && interrupted(err); attempt++ {
+		if ctx.Err() != nil {
+			break
+		}
+		r.logger.Warnf("Transfer interrupted (%v); resuming from partial files (attempt %d/%d)", err, attempt, opts.RetryFiles)
+		cmd := r.buildRsyncCommand(ctx, opts, sourceFilter, destFilter, filesFrom)
+		failed, err = r.executeRsync(ctx, cmd, opts)
+		if err == nil {
+			r.logger.Infof("Resumed transfer completed on attempt %d", attempt)
+		}
+	}
+	return failed, err
+}
+
+// partialTransferCodes are the rsync exit codes for a run that finished but couldn't
+// transfer some files: 23 for per-file errors such as permission denied, 24 for vanished files
+var partialTransferCodes = []int{23, 24}
+
+// skipFailedFiles turns a partial transfer into a success when opts.IgnoreErrors is set,
+// recording the unreadable files as skipped. Other failures are returned unchanged.
+func (r *Runner) skipFailedFiles(opts *Options, failed []string, err error) error {
+	var exitErr *exec.ExitError
+	if err == nil || !opts.IgnoreErrors || len(failed) == 0 ||
+		!errors.As(err, &exitErr) || !slices.Contains(partialTransferCodes, exitErr.ExitCode()) {
+		return err
+	}
+
+	for _, file := range failed {
+		r.logger.Warnf("Skipped unreadable file: %s", file)
+	}
+	r.logger.Warnf("Skipped %d files that couldn't be transferred (--ignore-errors)", len(failed))
+	r.stats.Skipped = append(r.stats.Skipped, failed...)
+	return nil
+}
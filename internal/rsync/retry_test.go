@@ -0,0 +1,24 @@
+package rsync
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestInterrupted(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh to produce exit statuses")
+	}
+	for code, want := range map[int]bool{12: true, 30: true, 23: false, 1: false} {
+		err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+		if got := interrupted(fmt.Errorf("rsync command failed: %w", err)); got != want {
+			t.Errorf("interrupted(exit %d) = %v, want %v", code, got, want)
+		}
+	}
+	if interrupted(errors.New("rsync stopped")) {
+		t.Error("interrupted accepted an error without an exit status")
+	}
+}
@@ -2,12 +2,15 @@ package rsync
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DamianReeves/sync-tools/internal/filters"
@@ -21,8 +24,19 @@ type Options struct {
 	Mode                string
 	DryRun              bool
 	UseSourceGitignore  bool
+	// UseGlobalGitignore adds the patterns of git's global excludes file (core.excludesFile)
+	UseGlobalGitignore  bool
 	ExcludeHiddenDirs   bool
+	// ExcludeVCS excludes filters.VCSExcludeList; IncludeGit drops the default /.git/ exclusion
+	ExcludeVCS          bool
+	IncludeGit          bool
+	// ExcludeIfPresent names marker files; a source directory holding one is not synced
+	ExcludeIfPresent    []string
+	// FilterRules are raw rsync filter rules, written after the rules sync-tools generates
+	FilterRules         []string
 	OnlySyncignore      bool
+	// NoNestedSyncignore reads only the source root's .syncignore, not those in subdirectories
+	NoNestedSyncignore  bool
 	IgnoreSrc           []string
 	IgnoreDest          []string
 	Only                []string
@@ -30,18 +44,111 @@ type Options struct {
 	LogFile             string
 	LogFormat           string
 	DumpCommands        string
+	// DumpFilters writes the filter rules given to rsync, annotated with their origins, to this
+	// path before the transfer, or to stderr for "-"
+	DumpFilters         string
 	Report              string
+	// Plan dry-runs the sync and writes the changes it would make to this path as markdown
+	Plan                string
 	ListFiltered        string
 	Interactive         bool
 	Patch               string
 	ApplyPatch          bool
 	Yes                 bool
 	Preview             bool
+	// PreviewFormat renders Preview as git (the default), unified, side-by-side, or stat;
+	// all but git use the built-in diff
+	PreviewFormat       string
+	FilesFrom           string
+	OneFileSystem       bool
+	// PruneEmptyDirs skips directories left empty by the filters (rsync --prune-empty-dirs),
+	// while still syncing directories that are empty in a local source
+	PruneEmptyDirs      bool
+	// Super has the receiver attempt super-user activities such as setting ownership (rsync
+	// --super); FakeSuper instead stores privileged attributes in xattrs (rsync --fake-super)
+	Super               bool
+	FakeSuper           bool
+	DestOwnershipReport bool
+	ExpectedOwner       string
+	Links               string
+	RetryFiles          int
+	// PropagateDeletes makes two-way syncs delete source files missing from the dest
+	// instead of copying them, treating them as deleted there
+	PropagateDeletes    bool
+	// ConflictSuffix names conflict copies; see ParseConflictSuffix. Empty uses DefaultConflictSuffix
+	ConflictSuffix      string
+	// InteractiveConflicts asks how to resolve each two-way conflict instead of keeping both versions
+	InteractiveConflicts bool
+	// skipPaths are files a two-way sync leaves alone on both sides, set by runTwoWay
+	skipPaths []string
+	// IgnoreErrors finishes a sync whose only failures are unreadable or vanished files,
+	// recording them in SyncStats.Skipped
+	IgnoreErrors        bool
+	WholeFile           bool
+	NoWholeFile         bool
+	Inplace             bool
+	DelayUpdates        bool
+	// Partial keeps partially transferred files so the next run resumes them (rsync --partial)
+	Partial             bool
+	// PartialDir keeps them in this directory instead, relative to each dest directory unless
+	// absolute (rsync --partial-dir); it implies Partial
+	PartialDir          string
+	// SizeOnly treats files of equal size as unchanged, whatever their mtimes (rsync --size-only)
+	SizeOnly            bool
+	// IgnoreTimes transfers every file, even those matching in size and mtime (rsync --ignore-times)
+	IgnoreTimes         bool
+	// NoArchive drops the implicit --archive for --recursive, so permissions, times, owners,
+	// and devices are only kept when asked for, e.g. through RsyncExtraArgs
+	NoArchive           bool
+	// IncludeUnchanged has rsync also list files already up to date (--info=name2), which
+	// are counted in SyncStats.FilesUnchanged and listed in dry-run output and markdown reports
+	IncludeUnchanged    bool
+	// Dedupe hard-links files this sync wrote to identical files already in the dest; see dedupeDest
+	Dedupe              bool
+	MaxDepth            int
+	// Relative recreates the source path under the dest (rsync --relative), from a /./ marker
+	// in Source if there is one; see RelativeSource
+	Relative            bool
+	IgnoreCase          bool
+	Sparse              bool
+	SafeMode            bool
+	Execute             bool
+	RsyncBinary         string
+	RsyncPath           string
+	PasswordFile        string
+	// RsyncLogFile has rsync write its own transfer log to this path (rsync --log-file),
+	// apart from sync-tools' LogFile; RsyncLogFormat sets its per-file format
+	RsyncLogFile        string
+	RsyncLogFormat      string
+	// ShowThroughput adds the transfer rate to the completion log line
+	ShowThroughput      bool
+	// Progress asks rsync for --info=progress2 and reports its ETA and rate during the run
+	Progress            bool
+	// SummaryOnly drops rsync's --verbose and logs its per-file output at debug level
+	SummaryOnly         bool
+	// ChecksumChoice selects rsync's transfer checksum (--checksum-choice); empty lets rsync pick
+	ChecksumChoice      string
+	// Watch marks a SyncFile operation to re-run when its source changes under syncfile --watch
+	Watch               bool
+	// RsyncExtraArgs are passed to rsync verbatim, just before the source and destination
+	RsyncExtraArgs      []string
 }
 
 // Runner handles rsync operations
 type Runner struct {
-	logger logging.Logger
+	logger   logging.Logger
+	stats    SyncStats
+	onChange func(Change)
+	// lastPath is the most recent path rsync reported, so a timeout can say how far it got
+	lastPath string
+	// dryRunOut receives the DRY RUN banner and itemized changes of dry runs, if set
+	dryRunOut io.Writer
+	// progressOut receives the progress status line, redrawn in place if progressTerminal
+	progressOut      io.Writer
+	progressTerminal bool
+	// changes holds every change of the run when keepChanges is set, for markdown reports
+	changes     []Change
+	keepChanges bool
 }
 
 // NewRunner creates a new rsync runner
@@ -51,70 +158,385 @@ func NewRunner(logger logging.Logger) *Runner {
 	}
 }
 
+// OnChange registers fn to be called with every change rsync reports, as it happens.
+// fn runs on the goroutine reading rsync's output, so it should not block for long.
+func (r *Runner) OnChange(fn func(Change)) {
+	r.onChange = fn
+}
+
+// ShowDryRun makes dry runs print a DRY RUN banner and rsync's itemized list of planned
+// changes to w, whatever the log level, so scripts can rely on seeing them. --summary-only
+// runs still print only their summary line.
+func (r *Runner) ShowDryRun(w io.Writer) {
+	r.dryRunOut = w
+}
+
+// showsDryRun reports whether this run's planned changes go to the ShowDryRun writer
+func (r *Runner) showsDryRun(opts *Options) bool {
+	return r.dryRunOut != nil && opts.DryRun && !opts.SummaryOnly
+}
+
+// recordOutput counts an rsync stdout line in the stats and passes any change to the OnChange hook
+func (r *Runner) recordOutput(line string) {
+	change, ok := parseChange(line)
+	if !ok {
+		return
+	}
+	r.stats.record(change)
+	r.lastPath = change.Path
+	if r.keepChanges {
+		r.changes = append(r.changes, change)
+	}
+	// Unchanged files aren't changes to whoever is watching them happen
+	if r.onChange != nil && change.Kind != ChangeUnchanged {
+		r.onChange(change)
+	}
+}
+
+// Stats returns the changes counted during the last Sync
+func (r *Runner) Stats() SyncStats {
+	return r.stats
+}
+
 // Sync performs the synchronization operation
 func (r *Runner) Sync(opts *Options) error {
+	return r.SyncContext(context.Background(), opts)
+}
+
+// SyncContext performs the synchronization operation, stopping any running rsync
+// when ctx is cancelled or its deadline passes
+func (r *Runner) SyncContext(ctx context.Context, opts *Options) error {
+	r.stats = SyncStats{}
+	r.lastPath = ""
+	r.changes = nil
+
+	start := time.Now()
+	defer func() {
+		r.stats.Duration = time.Since(start)
+	}()
+
+	// Safe mode inverts the default so nothing is written without an explicit --execute
+	if opts.forcedDryRun() {
+		r.logger.Warn("Safe mode is enabled: running as a dry run. Pass --execute to apply changes")
+		opts.DryRun = true
+	}
+
+	// Checked before any mode runs, so plans, previews, and patches are held to them too
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+	if !IsRemotePath(opts.Source) {
+		if _, err := os.Stat(opts.Source); os.IsNotExist(err) {
+			return fmt.Errorf("%w: %s", ErrSourceNotFound, opts.Source)
+		}
+	}
+
+	// A plan is a dry run whose changes are written out rather than applied
+	if opts.Plan != "" {
+		return r.plan(ctx, opts)
+	}
+
 	// Check if preview mode is requested
 	if opts.Preview {
-		return r.showPreview(opts)
+		return r.showPreview(ctx, opts)
 	}
 	
 	// Check if patch mode is requested (either via --patch flag or --report with .patch extension)
 	if opts.Patch != "" {
 		r.logger.Infof("Starting patch generation: %s -> %s (output: %s, dry-run: %v)",
 			opts.Source, opts.Dest, opts.Patch, opts.DryRun)
-		return r.generatePatch(opts)
+		return r.generatePatch(ctx, opts)
 	}
 	
 	// Check if report with patch format is requested (based on file extension)
-	if opts.Report != "" && (strings.HasSuffix(strings.ToLower(opts.Report), ".patch") || strings.HasSuffix(strings.ToLower(opts.Report), ".diff")) {
+	if IsPatchReport(opts.Report) {
 		r.logger.Infof("Starting patch report generation: %s -> %s (output: %s, dry-run: %v)",
 			opts.Source, opts.Dest, opts.Report, opts.DryRun)
 		// Use the report path as patch path
 		patchOpts := *opts
 		patchOpts.Patch = opts.Report
-		return r.generatePatch(&patchOpts)
+		return r.generatePatch(ctx, &patchOpts)
 	}
 
 	r.logger.Infof("Starting sync: %s -> %s (mode: %s, dry-run: %v)",
 		opts.Source, opts.Dest, opts.Mode, opts.DryRun)
+	if r.showsDryRun(opts) {
+		fmt.Fprintf(r.dryRunOut, "DRY RUN: %s -> %s (no changes will be made)\n", opts.Source, opts.Dest)
+	}
+	if opts.MaxDepth > 0 {
+		r.logger.Infof("Limited to %d levels below the source; deeper paths are skipped and left untouched in the dest", opts.MaxDepth)
+	}
+
+	if err := CheckRsync(opts); err != nil {
+		return err
+	}
+
+	if opts.ChecksumChoice != "" {
+		r.checkChecksumChoiceSupport(opts)
+	}
+	if opts.FakeSuper {
+		r.checkFakeSuperSupport(opts)
+	}
+
+	if conflicts := managedFlagConflicts(opts.RsyncExtraArgs); len(conflicts) > 0 {
+		r.logger.Warnf("Extra rsync arguments %s override flags sync-tools manages; the sync may not behave as expected",
+			strings.Join(conflicts, " "))
+	}
+
+	markdownReport := IsMarkdownReport(opts.Report)
+	if markdownReport || opts.Dedupe {
+		r.keepChanges = true
+		defer func() { r.keepChanges = false }()
+	}
 
+	var err error
 	switch opts.Mode {
 	case "one-way":
-		return r.runOneWay(opts)
+		err = r.runOneWay(ctx, opts)
 	case "two-way":
-		return r.runTwoWay(opts)
+		err = r.runTwoWay(ctx, opts)
 	default:
 		return fmt.Errorf("unsupported mode: %s", opts.Mode)
 	}
+	if err != nil {
+		return err
+	}
+
+	if markdownReport {
+		if err := writeChangeReport(opts.Report, "Sync Report", opts, r.stats, r.changes); err != nil {
+			return fmt.Errorf("error writing report: %w", err)
+		}
+		r.logger.Infof("Report written to %s", opts.Report)
+	}
+
+	elapsed := time.Since(start)
+	if opts.ShowThroughput {
+		r.logger.Infof("Sync completed successfully in %s (%d bytes, %.2f MB/s)",
+			FormatDuration(elapsed), r.stats.BytesTransferred, throughput(r.stats.BytesTransferred, elapsed))
+	} else {
+		r.logger.Infof("Sync completed successfully in %s", FormatDuration(elapsed))
+	}
+	return nil
 }
 
-// runOneWay performs one-way synchronization
-func (r *Runner) runOneWay(opts *Options) error {
-	// Build filter files
-	sourceFilter, err := r.buildSourceFilter(opts)
+// checkChecksumChoiceSupport warns when the local rsync predates --checksum-choice's
+// xxhash algorithms, since rsync would then fail on the option
+func (r *Runner) checkChecksumChoiceSupport(opts *Options) {
+	version, err := RsyncVersion(opts)
 	if err != nil {
-		return fmt.Errorf("error building source filter: %w", err)
+		r.logger.Debugf("Could not check rsync's support for --checksum-choice: %v", err)
+		return
+	}
+	if !version.AtLeast(checksumChoiceMinVersion.Major, checksumChoiceMinVersion.Minor) {
+		r.logger.Warnf("--checksum-choice=%s needs rsync %d.%d or newer, but %s is %s; rsync may reject it",
+			opts.ChecksumChoice, checksumChoiceMinVersion.Major, checksumChoiceMinVersion.Minor, rsyncBinary(opts), version)
+	}
+}
+
+// IsPatchReport reports whether a --report path selects patch output (.patch or .diff)
+func IsPatchReport(report string) bool {
+	lower := strings.ToLower(report)
+	return strings.HasSuffix(lower, ".patch") || strings.HasSuffix(lower, ".diff")
+}
+
+// WritesDest reports whether Sync will modify the destination rather than only previewing,
+// generating a patch, or doing a dry run
+func (opts *Options) WritesDest() bool {
+	return !opts.DryRun && !opts.forcedDryRun() && !opts.Preview && opts.Patch == "" && !IsPatchReport(opts.Report)
+}
+
+// resumable reports whether rsync keeps partially transferred files for the next attempt
+func (opts *Options) resumable() bool {
+	return opts.Partial || opts.PartialDir != ""
+}
+
+// forcedDryRun reports whether safe mode turns this run into a dry run because --execute wasn't given
+func (opts *Options) forcedDryRun() bool {
+	return opts.SafeMode && !opts.Execute && !opts.DryRun
+}
+
+// ChecksumChoices are the algorithms rsync 3.2 accepts for --checksum-choice
+var ChecksumChoices = []string{"auto", "xxh128", "xxh3", "xxh64", "xxhash", "md5", "md4", "sha1", "none"}
+
+// checksumChoiceMinVersion is the first rsync release with xxhash checksums
+var checksumChoiceMinVersion = Version{Major: 3, Minor: 2}
+
+// validateOptions rejects option values that rsync would otherwise fail on mid-run
+func validateOptions(opts *Options) error {
+	if opts.ExpectedOwner != "" {
+		if _, _, err := parseOwner(opts.ExpectedOwner); err != nil {
+			return err
+		}
+	}
+	// The report walks a local dest and compares each entry with the source at the same path
+	if opts.DestOwnershipReport {
+		if IsRemotePath(opts.Dest) || opts.Relative {
+			return fmt.Errorf("--dest-ownership-report needs a local dest and cannot be combined with --relative")
+		}
+		if IsRemotePath(opts.Source) && opts.ExpectedOwner == "" {
+			return fmt.Errorf("--dest-ownership-report needs --expected-owner for a remote source, whose owners it can't read")
+		}
+	} else if opts.ExpectedOwner != "" {
+		return fmt.Errorf("--expected-owner only applies with --dest-ownership-report")
+	}
+
+	switch opts.Links {
+	case "", "preserve", "copy", "safe", "munge":
+	default:
+		return fmt.Errorf("invalid links mode: %s (must be 'preserve', 'copy', 'safe', or 'munge')", opts.Links)
 	}
-	defer r.cleanupTempFile(sourceFilter)
 
-	var destFilter string
-	if len(opts.IgnoreDest) > 0 {
-		destFilter, err = r.buildDestFilter(opts)
+	if opts.WholeFile && opts.NoWholeFile {
+		return fmt.Errorf("--whole-file and --no-whole-file cannot be used together")
+	}
+
+	if _, err := ParseConflictSuffix(opts.ConflictSuffix); err != nil {
+		return err
+	}
+
+	if opts.ChecksumChoice != "" && !slices.Contains(ChecksumChoices, opts.ChecksumChoice) {
+		return fmt.Errorf("invalid checksum choice: %s (must be one of: %s)", opts.ChecksumChoice, strings.Join(ChecksumChoices, ", "))
+	}
+	if opts.MaxDepth < 0 {
+		return fmt.Errorf("invalid max depth: %d (must be 0 for unlimited, or positive)", opts.MaxDepth)
+	}
+
+	if opts.Relative {
+		// These compare the source root with the dest root, which --relative moves
+		if opts.Mode == "two-way" || opts.FilesFrom != "" || opts.Preview || opts.Patch != "" || IsPatchReport(opts.Report) {
+			return fmt.Errorf("--relative cannot be combined with two-way mode, --files-from, --preview, or --patch")
+		}
+		if root := relativeRoot(opts.Source); root == ".." || strings.HasPrefix(root, "../") {
+			return fmt.Errorf("--relative can't recreate %s under the dest because it starts with ..; mark where the kept path starts with /./ instead (e.g. /data/./projects/app)", opts.Source)
+		}
+	}
+
+	for _, marker := range opts.ExcludeIfPresent {
+		if marker == "" || marker == "." || marker == ".." || strings.ContainsAny(marker, `/\`) {
+			return fmt.Errorf("invalid --exclude-if-present marker %q: must be a file name", marker)
+		}
+	}
+
+	for _, rule := range opts.FilterRules {
+		if strings.TrimSpace(rule) == "" || strings.ContainsAny(rule, "\r\n") {
+			return fmt.Errorf("invalid --filter-rule %q: must be a single non-empty rsync filter rule", rule)
+		}
+	}
+
+	if opts.Super && opts.FakeSuper {
+		return fmt.Errorf("--super and --fake-super cannot be used together")
+	}
+
+	if opts.Inplace && opts.DelayUpdates {
+		return fmt.Errorf("--inplace and --delay-updates cannot be used together")
+	}
+	if opts.Inplace && opts.PartialDir != "" {
+		return fmt.Errorf("--inplace and --partial-dir cannot be used together; --inplace already resumes in the dest file")
+	}
+
+	if opts.SizeOnly && opts.IgnoreTimes {
+		return fmt.Errorf("--size-only and --ignore-times cannot be used together")
+	}
+	if opts.SizeOnly || opts.IgnoreTimes {
+		for _, arg := range opts.RsyncExtraArgs {
+			if arg == "--checksum" || arg == "-c" {
+				return fmt.Errorf("--size-only and --ignore-times cannot be combined with rsync's %s", arg)
+			}
+		}
+	}
+
+	if opts.RsyncLogFormat != "" && opts.RsyncLogFile == "" {
+		return fmt.Errorf("--rsync-log-format needs --rsync-log-file")
+	}
+
+	// Linked files share one inode, so writing either in place would change both
+	if opts.Dedupe {
+		if IsRemotePath(opts.Dest) || opts.Mode == "two-way" {
+			return fmt.Errorf("--dedupe needs a local dest and a one-way sync")
+		}
+		if opts.Inplace || slices.Contains(opts.RsyncExtraArgs, "--inplace") {
+			return fmt.Errorf("--dedupe cannot be combined with --inplace, which would write through a hard link into its duplicates")
+		}
+	}
+
+	// rsync ignores a password file for SSH transfers, which would hide a misconfiguration
+	if opts.PasswordFile != "" && !IsDaemonPath(opts.Source) && !IsDaemonPath(opts.Dest) {
+		return fmt.Errorf("--password-file only applies to rsync daemon targets (rsync://host/module or host::module)")
+	}
+
+	return nil
+}
+
+// runOneWay performs one-way synchronization
+func (r *Runner) runOneWay(ctx context.Context, opts *Options) error {
+	// An explicit file list replaces the .syncignore/--only filter machinery
+	var sourceFilter *filters.Filter
+	var err error
+	if opts.FilesFrom == "" {
+		sourceFilter, err = r.buildSourceFilter(opts)
 		if err != nil {
-			return fmt.Errorf("error building dest filter: %w", err)
+			return fmt.Errorf("error building source filter: %w", err)
+		}
+		defer r.closeFilter(sourceFilter)
+	} else {
+		r.logger.Debug("Using --files-from list, skipping source filters")
+	}
+
+	filesFrom, spooled, err := r.resolveFilesFrom(opts)
+	if err != nil {
+		return fmt.Errorf("error reading file list: %w", err)
+	}
+	defer r.closeFilter(spooled)
+
+	destFilter, err := r.buildDestFilter(opts)
+	if err != nil {
+		return fmt.Errorf("error building dest filter: %w", err)
+	}
+	defer r.closeFilter(destFilter)
+
+	if opts.DumpFilters != "" {
+		if err := r.dumpFilters(opts); err != nil {
+			return err
 		}
-		defer r.cleanupTempFile(destFilter)
 	}
 
 	// Build rsync command
-	cmd := r.buildRsyncCommand(opts, sourceFilter, destFilter)
+	cmd := r.buildRsyncCommand(ctx, opts, sourceFilter.Path(), destFilter.Path(), filesFrom)
+
+	// Execute rsync, giving transiently failed files another chance if requested
+	failed, err := r.executeRsync(ctx, cmd, opts)
+	if err != nil && opts.RetryFiles > 0 {
+		// The retries read the temp files again, which something may have removed during a
+		// long transfer
+		for _, tempFile := range []*filters.Filter{sourceFilter, destFilter, spooled} {
+			if restoreErr := tempFile.Restore(); restoreErr != nil {
+				return fmt.Errorf("error restoring temp file for the retry: %w", restoreErr)
+			}
+		}
+	}
+	if err != nil && opts.RetryFiles > 0 && len(failed) > 0 {
+		failed, err = r.retryFailedFiles(ctx, opts, sourceFilter.Path(), destFilter.Path(), failed)
+	}
+	if err != nil && opts.RetryFiles > 0 && len(failed) == 0 && opts.resumable() && interrupted(err) {
+		failed, err = r.resumeInterrupted(ctx, opts, sourceFilter.Path(), destFilter.Path(), filesFrom, err)
+	}
+	if err = r.skipFailedFiles(opts, failed, err); err != nil {
+		return err
+	}
+
+	if opts.Dedupe && !opts.DryRun {
+		r.dedupeDest(opts)
+	}
 
-	// Execute rsync
-	return r.executeRsync(cmd, opts)
+	// Audit ownership only after a real sync has touched the destination
+	if opts.DestOwnershipReport && !opts.DryRun {
+		return r.reportDestOwnership(opts)
+	}
+	return nil
 }
 
 // runTwoWay performs two-way synchronization
-func (r *Runner) runTwoWay(opts *Options) error {
+func (r *Runner) runTwoWay(ctx context.Context, opts *Options) error {
 	// Two-way sync involves conflict detection and resolution
 	r.logger.Info("Performing two-way sync with conflict detection")
 
@@ -124,26 +546,81 @@ func (r *Runner) runTwoWay(opts *Options) error {
 		return fmt.Errorf("error detecting conflicts: %w", err)
 	}
 
+	r.stats.Conflicts = len(conflicts)
+	var skipped []string
 	if len(conflicts) > 0 {
-		r.logger.Warnf("Found %d conflicts, preserving destination versions as conflict files", len(conflicts))
-		if err := r.preserveConflicts(conflicts, opts); err != nil {
-			return fmt.Errorf("error preserving conflicts: %w", err)
+		r.logger.Warnf("Found %d conflicts", len(conflicts))
+		if skipped, err = r.resolveConflicts(conflicts, opts); err != nil {
+			return err
+		}
+	}
+
+	if opts.PropagateDeletes {
+		if err := r.propagateDeletes(opts); err != nil {
+			return err
 		}
 	}
 
-	// Then perform one-way sync
-	return r.runOneWay(opts)
+	// Then perform one-way sync, leaving out the conflicts the user skipped
+	oneWay := *opts
+	oneWay.skipPaths = skipped
+	return r.runOneWay(ctx, &oneWay)
 }
 
 // buildSourceFilter creates the source-side filter file
-func (r *Runner) buildSourceFilter(opts *Options) (string, error) {
-	var patterns []string
+func (r *Runner) buildSourceFilter(opts *Options) (*filters.Filter, error) {
+	rules, err := r.sourceFileRules(opts)
+	if err != nil {
+		return nil, err
+	}
+	return filters.WriteFilterFile(ruleLines(rules))
+}
+
+// sourceFileRules returns the rules of the source filter file: sourceFilterRules plus those
+// only a transfer needs
+func (r *Runner) sourceFileRules(opts *Options) ([]filterRule, error) {
+	rules, err := r.sourceFilterRules(opts)
+	if err != nil {
+		return nil, err
+	}
+	rules = append(annotateRules("two-way conflicts left alone", filters.SkipLines(opts.skipPaths)), rules...)
+	if opts.PruneEmptyDirs {
+		keep, err := r.emptySourceDirs(opts)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(annotateRules("--prune-empty-dirs (empty source directories)", filters.KeepDirLines(keep)), rules...)
+	}
+	// rsync matches anchored rules against the recreated path under --relative
+	if opts.Relative {
+		rules = relativeRules(rules, relativeRoot(opts.Source))
+	}
+	return rules, nil
+}
+
+// sourceFilterLines returns the source-side rsync filter rules
+func (r *Runner) sourceFilterLines(opts *Options) ([]string, error) {
+	rules, err := r.sourceFilterRules(opts)
+	if err != nil {
+		return nil, err
+	}
+	return ruleLines(rules), nil
+}
+
+// sourceFilterRules returns the source-side rsync filter rules with their origins
+func (r *Runner) sourceFilterRules(opts *Options) ([]filterRule, error) {
+	var sources []ignoreSource
 
 	// Add default exclusions
-	patterns = append(patterns, "/.git/")
+	if !opts.IncludeGit {
+		sources = append(sources, ignoreSource{"default (--include-git drops it)", []string{"/.git/"}})
+	}
+	if opts.ExcludeVCS {
+		sources = append(sources, ignoreSource{"--exclude-vcs", filters.VCSExcludePatterns()})
+	}
 
 	if opts.ExcludeHiddenDirs {
-		patterns = append(patterns, "/.*")
+		sources = append(sources, ignoreSource{"--exclude-hidden-dirs", []string{"/.*"}})
 	}
 
 	// Add .syncignore patterns from source
@@ -152,9 +629,23 @@ func (r *Runner) buildSourceFilter(opts *Options) (string, error) {
 		if _, err := os.Stat(syncignoreFile); err == nil {
 			ignorePatterns, err := r.readIgnoreFile(syncignoreFile)
 			if err != nil {
-				return "", err
+				return nil, err
+			}
+			sources = append(sources, ignoreSource{".syncignore", ignorePatterns})
+		}
+
+		// The global gitignore comes before the source's own, as git reads them
+		if opts.UseGlobalGitignore {
+			if globalFile := globalGitignorePath(); globalFile != "" {
+				ignorePatterns, err := r.readIgnoreFile(globalFile)
+				if err != nil {
+					return nil, err
+				}
+				r.logger.Debugf("Using global gitignore %s", globalFile)
+				sources = append(sources, ignoreSource{"global gitignore " + globalFile, ignorePatterns})
+			} else {
+				r.logger.Debug("No global gitignore found")
 			}
-			patterns = append(patterns, ignorePatterns...)
 		}
 
 		// Add .gitignore patterns if requested
@@ -163,42 +654,282 @@ func (r *Runner) buildSourceFilter(opts *Options) (string, error) {
 			if _, err := os.Stat(gitignoreFile); err == nil {
 				ignorePatterns, err := r.readIgnoreFile(gitignoreFile)
 				if err != nil {
-					return "", err
+					return nil, err
 				}
-				patterns = append(patterns, ignorePatterns...)
+				sources = append(sources, ignoreSource{".gitignore", ignorePatterns})
 			}
 		}
 	}
 
 	// Add CLI ignore patterns
-	patterns = append(patterns, opts.IgnoreSrc...)
+	sources = append(sources, ignoreSource{"--ignore-src", opts.IgnoreSrc})
 
 	// Handle whitelist mode
+	var rules []filterRule
 	if len(opts.Only) > 0 {
-		return filters.BuildOnlyFilter(opts.Only)
+		rules = annotateRules("--only", filters.OnlyFilterLines(opts.Only))
+	} else {
+		rules = excludeRules(sources)
+		// Nested .syncignore files sit closer to the paths they name, so their rules come first
+		if !opts.OnlySyncignore && !opts.NoNestedSyncignore && !IsRemotePath(opts.Source) {
+			nested, err := r.nestedSyncignoreRules(opts, ruleLines(rules))
+			if err != nil {
+				return nil, fmt.Errorf("error reading nested .syncignore files: %w", err)
+			}
+			rules = append(nested, rules...)
+		}
+	}
+
+	// First match wins, so the depth limit must precede any include rules
+	if opts.MaxDepth > 0 {
+		rules = append(annotateRules("--max-depth", filters.MaxDepthLines(opts.MaxDepth)), rules...)
+	}
+	if opts.IgnoreCase {
+		rules = rewriteRules(rules, filters.CaseInsensitiveLines)
+	}
+
+	// Raw rules are kept verbatim and come last, so they only decide paths that no
+	// generated rule matched
+	rules = append(rules, annotateRules("--filter-rule", opts.FilterRules)...)
+
+	// Marked directories are named exactly, so they go after the case folding and before
+	// everything else, where no --only rule can bring them back
+	if len(opts.ExcludeIfPresent) > 0 {
+		marked, err := r.markedSourceDirs(opts)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(annotateRules("--exclude-if-present", filters.ExcludeDirLines(marked)), rules...)
+	}
+
+	// rsync keeps a relative partial dir inside each dest directory. sync-tools' filters
+	// would leave it excluded, and so deleted by --delete-excluded, so it is protected first
+	// for the next run to resume from.
+	if opts.PartialDir != "" && !filepath.IsAbs(opts.PartialDir) {
+		dir := strings.Trim(filepath.ToSlash(opts.PartialDir), "/") + "/"
+		rules = append(annotateRules("--partial-dir", []string{"P " + dir, "- " + dir}), rules...)
 	}
+	return rules, nil
+}
+
+// buildDestFilter creates the destination-side filter file, or returns nil without dest ignores
+func (r *Runner) buildDestFilter(opts *Options) (*filters.Filter, error) {
+	return filters.WriteFilterFile(destFilterLines(opts))
+}
 
-	return filters.BuildExcludeFilter(patterns)
+// destFilterLines returns the destination-side rsync filter rules
+func destFilterLines(opts *Options) []string {
+	return ruleLines(destFilterRules(opts))
+}
+
+// destFilterRules returns the destination-side rsync filter rules with their origins
+func destFilterRules(opts *Options) []filterRule {
+	rules := annotateRules("--ignore-dest", filters.DestFilterLines(opts.IgnoreDest))
+	if opts.IgnoreCase {
+		rules = rewriteRules(rules, filters.CaseInsensitiveLines)
+	}
+	return rules
 }
 
-// buildDestFilter creates the destination-side filter file
-func (r *Runner) buildDestFilter(opts *Options) (string, error) {
-	return filters.BuildExcludeFilter(opts.IgnoreDest)
+// CheckFilter reports whether a source-relative path would be transferred under the current
+// filters and which rule decided it, without running rsync
+func (r *Runner) CheckFilter(opts *Options, relPath string) (filters.Decision, error) {
+	lines, err := r.sourceFilterLines(opts)
+	if err != nil {
+		return filters.Decision{}, fmt.Errorf("error building source filter: %w", err)
+	}
+	// rsync evaluates the source rules first, then the dest rules
+	lines = append(lines, destFilterLines(opts)...)
+
+	isDir := strings.HasSuffix(relPath, "/")
+	if info, err := os.Stat(localPath(opts.Source, relPath)); err == nil {
+		isDir = info.IsDir()
+	}
+
+	return filters.Match(filters.ParseRules(lines), filepath.ToSlash(relPath), isDir), nil
+}
+
+// resolveFilesFrom returns the file list path to pass to rsync. For "-" it spools stdin to a
+// temp file and also returns it, so the caller can Close it; a user's own list is never removed.
+func (r *Runner) resolveFilesFrom(opts *Options) (string, *filters.Filter, error) {
+	if opts.FilesFrom != "-" {
+		return opts.FilesFrom, nil, nil
+	}
+	spooled, err := filters.BuildFilesFromList(os.Stdin)
+	if err != nil {
+		return "", nil, err
+	}
+	return spooled.Path(), spooled, nil
 }
 
 // buildRsyncCommand constructs the rsync command
-func (r *Runner) buildRsyncCommand(opts *Options, sourceFilter, destFilter string) *exec.Cmd {
-	args := []string{
-		"--archive",          // -a
-		"--verbose",          // -v
+func (r *Runner) buildRsyncCommand(ctx context.Context, opts *Options, sourceFilter, destFilter, filesFrom string) *exec.Cmd {
+	return rsyncCommand(ctx, opts, r.buildRsyncArgs(opts, sourceFilter, destFilter, filesFrom)...)
+}
+
+// rsyncBinary returns the local rsync executable, defaulting to "rsync" on PATH
+func rsyncBinary(opts *Options) string {
+	if opts.RsyncBinary != "" {
+		return opts.RsyncBinary
+	}
+	return "rsync"
+}
+
+// rsyncStopGrace is how long a cancelled rsync gets to clean up its temp files before it is killed
+const rsyncStopGrace = 5 * time.Second
+
+// rsyncCommand is the single place rsync is invoked, so --rsync-binary applies everywhere.
+// Cancelling ctx asks rsync to stop, then kills it if it hasn't exited after rsyncStopGrace.
+func rsyncCommand(ctx context.Context, opts *Options, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, rsyncBinary(opts), args...)
+	cmd.Cancel = func() error {
+		return stopProcess(cmd.Process)
+	}
+	cmd.WaitDelay = rsyncStopGrace
+	return cmd
+}
+
+// Preflight runs the checks a sync makes before it changes anything: the options are valid
+// and rsync can be found. Callers that touch the dest before syncing run it first.
+func Preflight(opts *Options) error {
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+	return CheckRsync(opts)
+}
+
+// CheckRsync reports a clear error when the configured rsync binary can't be found,
+// instead of the exec failure rsync would otherwise produce mid-run
+func CheckRsync(opts *Options) error {
+	if _, err := exec.LookPath(rsyncBinary(opts)); err != nil {
+		return fmt.Errorf("rsync not found (%s); install it or set --rsync-binary", rsyncBinary(opts))
+	}
+	return nil
+}
+
+// buildRsyncArgs constructs the rsync argument list, ending with source and destination
+func (r *Runner) buildRsyncArgs(opts *Options, sourceFilter, destFilter, filesFrom string) []string {
+	args := []string{"--archive"} // -a
+	if opts.NoArchive {
+		// Only what the options ask for: the tree itself, with symlinks as --links says below
+		args = []string{"--recursive"} // -r
+	}
+	if !opts.SummaryOnly {
+		args = append(args, "--verbose") // -v
+	}
+	args = append(args,
 		"--human-readable",   // -h
 		"--delete",           // Remove files from dest that don't exist in source
 		"--delete-excluded",  // Also delete excluded files from dest
-	}
+		"--out-format="+itemizeFormat, // Itemized change lines for SyncStats, printed even without -v
+	)
 
 	if opts.DryRun {
 		args = append(args, "--dry-run")
 	}
+	if opts.IncludeUnchanged {
+		args = append(args, "--info=name2") // Also list files that are already up to date
+	}
+	// A dry run moves no data, so there is no progress to report
+	if opts.Progress && !opts.DryRun {
+		args = append(args, "--info=progress2")
+	}
+
+	if opts.OneFileSystem {
+		args = append(args, "--one-file-system")
+	}
+
+	if opts.Relative {
+		args = append(args, "--relative")
+	}
+	if opts.PruneEmptyDirs {
+		args = append(args, "--prune-empty-dirs")
+	}
+	if opts.Super {
+		args = append(args, "--super")
+	}
+	// --fake-super only affects the side it is given on, so a remote receiver gets it passed on
+	if opts.FakeSuper {
+		if IsRemotePath(opts.Dest) {
+			args = append(args, "--remote-option=--fake-super")
+		} else {
+			args = append(args, "--fake-super")
+		}
+	}
+
+	// --archive preserves symlinks as-is; the other modes change how they are transferred
+	if opts.NoArchive && opts.Links != "copy" {
+		args = append(args, "--links")
+	}
+	switch opts.Links {
+	case "copy":
+		args = append(args, "--copy-links")
+	case "safe":
+		args = append(args, "--safe-links")
+	case "munge":
+		args = append(args, "--munge-links")
+	}
+
+	// Throughput tuning; rsync already defaults to whole-file copies when both sides are local
+	if opts.WholeFile {
+		args = append(args, "--whole-file")
+	}
+	if opts.NoWholeFile {
+		args = append(args, "--no-whole-file")
+	}
+	if opts.Inplace {
+		args = append(args, "--inplace")
+	}
+	if opts.Sparse {
+		args = append(args, "--sparse")
+	}
+	if opts.ChecksumChoice != "" {
+		args = append(args, "--checksum-choice="+opts.ChecksumChoice)
+	}
+
+	// How rsync decides a file needs updating; by default, a size or mtime difference
+	if opts.SizeOnly {
+		args = append(args, "--size-only")
+	}
+	if opts.IgnoreTimes {
+		args = append(args, "--ignore-times")
+	}
+
+	// Stage every update and rename them all into place at the end, so an interrupted
+	// sync doesn't leave the dest half-updated
+	if opts.DelayUpdates {
+		args = append(args, "--delay-updates")
+	}
+
+	// Keep what an interrupted transfer sent, so the next run resumes the file
+	if opts.PartialDir != "" {
+		args = append(args, "--partial-dir="+opts.PartialDir)
+	} else if opts.Partial {
+		args = append(args, "--partial")
+	}
+
+	// The rsync program to run on the remote side of SSH transfers
+	if opts.RsyncPath != "" {
+		args = append(args, "--rsync-path", opts.RsyncPath)
+	}
+
+	// Daemon authentication, instead of rsync prompting for the module password
+	if opts.PasswordFile != "" {
+		args = append(args, "--password-file", opts.PasswordFile)
+	}
+
+	// rsync's own transfer log, which it appends to on every run
+	if opts.RsyncLogFile != "" {
+		args = append(args, "--log-file="+opts.RsyncLogFile)
+	}
+	if opts.RsyncLogFormat != "" {
+		args = append(args, "--log-file-format="+opts.RsyncLogFormat)
+	}
+
+	// rsync implies --relative for --files-from, so listed paths keep their structure
+	if filesFrom != "" {
+		args = append(args, "--files-from", filesFrom)
+	}
 
 	// Add filter files
 	if sourceFilter != "" {
@@ -208,57 +939,133 @@ func (r *Runner) buildRsyncCommand(opts *Options, sourceFilter, destFilter strin
 		args = append(args, "--filter", fmt.Sprintf(". %s", destFilter))
 	}
 
+	// User-supplied escape hatch for rsync options sync-tools doesn't expose
+	args = append(args, opts.RsyncExtraArgs...)
+
 	// Add source and destination
-	// Ensure source path ends with / for proper rsync behavior
-	source := opts.Source
-	if !strings.HasSuffix(source, "/") {
+	// Ensure source path ends with / for proper rsync behavior. --relative recreates the
+	// source path under the dest instead, so the path is passed as given.
+	source := rsyncPath(opts.Source)
+	if !opts.Relative && !strings.HasSuffix(source, "/") {
 		source += "/"
 	}
-	args = append(args, source, opts.Dest)
+	args = append(args, source, rsyncPath(opts.Dest))
 
-	return exec.Command("rsync", args...)
+	return args
 }
 
-// executeRsync runs the rsync command
-func (r *Runner) executeRsync(cmd *exec.Cmd, opts *Options) error {
+// executeRsync runs the rsync command and returns the files rsync reported as failed
+func (r *Runner) executeRsync(ctx context.Context, cmd *exec.Cmd, opts *Options) ([]string, error) {
 	r.logger.Debugf("Executing rsync command: %s", strings.Join(cmd.Args, " "))
 
 	// Set up output capturing
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Start command
 	if err := cmd.Start(); err != nil {
-		return err
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("rsync not started: %w", context.Cause(ctx))
+		}
+		return nil, err
 	}
 
-	// Read and log output
-	go r.logOutput(stdout, "STDOUT")
-	go r.logOutput(stderr, "STDERR")
+	// Read and log output, collecting per-file failures from stderr. Summary-only runs keep
+	// the file list out of the log unless debugging, and shown dry runs print it instead.
+	logStdout := r.logger.Infof
+	onStdout := r.recordOutput
+	if opts.SummaryOnly {
+		logStdout = r.logger.Debugf
+	} else if r.showsDryRun(opts) {
+		logStdout = r.logger.Debugf
+		onStdout = func(line string) {
+			if _, ok := parseChange(line); ok {
+				fmt.Fprintln(r.dryRunOut, line)
+			}
+			r.recordOutput(line)
+		}
+	}
+	if r.showsProgress(opts) {
+		progress := r.newProgressReporter()
+		defer progress.finish()
+		logChange, recordChange := logStdout, onStdout
+		// Progress lines are status, not output: keep them out of the log and the stats
+		logStdout = func(format string, args ...interface{}) {}
+		onStdout = func(line string) {
+			if p, ok := parseProgress(line); ok {
+				progress.update(p)
+				return
+			}
+			progress.clear()
+			logChange("[STDOUT] %s", line)
+			recordChange(line)
+		}
+	}
+	var failed []string
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		r.logOutput(stdout, "STDOUT", logStdout, onStdout)
+	}()
+	go func() {
+		defer wg.Done()
+		r.logOutput(stderr, "STDERR", r.logger.Infof, func(line string) {
+			if file := parseFailedFile(line, opts); file != "" && !seen[file] {
+				seen[file] = true
+				failed = append(failed, file)
+			}
+		})
+	}()
 
-	// Wait for completion
+	// Output must be fully drained before Wait closes the pipes
+	wg.Wait()
 	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("rsync command failed: %w", err)
+		// A stopped rsync's failures are an effect of the stop, so there's nothing to retry
+		if ctx.Err() != nil {
+			r.logStopped(ctx, opts)
+			return nil, fmt.Errorf("rsync stopped: %w", context.Cause(ctx))
+		}
+		return failed, newRsyncError(err)
 	}
 
-	r.logger.Info("Sync completed successfully")
-	return nil
+	r.logger.Debug("rsync exited successfully")
+	return nil, nil
+}
+
+// logStopped records why rsync was stopped and how far it had got. rsync reports each file
+// once it is done, so the work in progress was just after the last reported path.
+func (r *Runner) logStopped(ctx context.Context, opts *Options) {
+	progress := "no files reported yet"
+	if r.lastPath != "" {
+		progress = "last file reported: " + r.lastPath
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		r.logger.Errorf("Timed out syncing %s -> %s (%v); rsync was stopped, %s", opts.Source, opts.Dest, context.Cause(ctx), progress)
+		return
+	}
+	r.logger.Warnf("Cancelled syncing %s -> %s; rsync was stopped, %s", opts.Source, opts.Dest, progress)
 }
 
-// logOutput logs command output line by line
-func (r *Runner) logOutput(reader io.ReadCloser, prefix string) {
+// logOutput logs command output line by line with logf, passing each line to onLine if set
+func (r *Runner) logOutput(reader io.ReadCloser, prefix string, logf func(string, ...interface{}), onLine func(string)) {
 	defer reader.Close()
 	scanner := bufio.NewScanner(reader)
+	scanner.Split(scanOutputLines)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
 		if line != "" {
-			r.logger.Infof("[%s] %s", prefix, line)
+			logf("[%s] %s", prefix, line)
+			if onLine != nil {
+				onLine(line)
+			}
 		}
 	}
 }
@@ -287,49 +1094,92 @@ func (r *Runner) readIgnoreFile(filename string) ([]string, error) {
 	return patterns, nil
 }
 
-// detectConflicts finds files that have changed on both sides
+// detectConflicts finds files that have changed on both sides. There is no record of the
+// previous sync, so a file counts as changed in the dest when the dest copy differs from the
+// source one and was modified after it: the mirror would otherwise discard that newer work.
+// Only local syncs can be checked; remote ones are synced without conflict detection.
 func (r *Runner) detectConflicts(opts *Options) ([]string, error) {
-	// This is a simplified conflict detection
-	// In practice, this would compare file modification times and checksums
-	r.logger.Debug("Detecting conflicts (simplified implementation)")
-	return []string{}, nil
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		r.logger.Warn("Conflict detection needs a local source and dest; syncing without it")
+		return nil, nil
+	}
+	if info, err := os.Stat(opts.Dest); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	cmp, err := r.CompareTrees(opts, opts.Source, opts.Dest)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	hashes := map[string]string{}
+	for _, file := range cmp.Differing {
+		if file.A.IsDir || file.B.IsDir || !file.B.ModTime.After(file.A.ModTime) {
+			continue
+		}
+		// A newer copy with the same content was only touched, not changed
+		if file.A.Size == file.B.Size {
+			sourceHash, err := fileHash(localPath(opts.Source, file.Path), hashes)
+			if err != nil {
+				return nil, err
+			}
+			destHash, err := fileHash(localPath(opts.Dest, file.Path), hashes)
+			if err != nil {
+				return nil, err
+			}
+			if sourceHash == destHash {
+				continue
+			}
+		}
+		conflicts = append(conflicts, file.Path)
+	}
+	return conflicts, nil
 }
 
-// preserveConflicts creates conflict copies of files
+// preserveConflicts saves the dest version of each conflicting file as a conflict copy,
+// named by opts.ConflictSuffix, next to the source file. The sync that follows carries the
+// copy to the dest, so both sides keep both versions and --delete leaves it alone.
 func (r *Runner) preserveConflicts(conflicts []string, opts *Options) error {
-	timestamp := time.Now().Unix()
+	names, err := ParseConflictSuffix(opts.ConflictSuffix)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
 	for _, conflict := range conflicts {
-		conflictName := fmt.Sprintf("%s.conflict-%d", conflict, timestamp)
-		r.logger.Infof("Creating conflict file: %s", conflictName)
-		// Implementation would copy the file with the conflict name
+		dir := filepath.Dir(filepath.FromSlash(conflict))
+		conflictName := uniqueConflictName(filepath.Join(opts.Source, dir), names.Render(filepath.Base(conflict), "dest", now))
+		conflictPath := filepath.Join(dir, conflictName)
+		if opts.DryRun {
+			r.logger.Infof("Would save the dest version of %s as %s (dry run)", conflict, conflictPath)
+			continue
+		}
+		if err := copyFileTo(localPath(opts.Dest, conflict), filepath.Join(opts.Source, conflictPath)); err != nil {
+			return conflictError(conflict, "error saving the dest version of %s: %w", conflict, err)
+		}
+		r.logger.Infof("Saved the dest version of %s as %s", conflict, conflictPath)
 	}
 	return nil
 }
 
-// cleanupTempFile removes temporary filter files
-func (r *Runner) cleanupTempFile(filename string) {
-	if filename != "" {
-		if err := os.Remove(filename); err != nil {
-			r.logger.Debugf("Failed to remove temp file %s: %v", filename, err)
-		}
+// closeFilter removes a temporary filter file, logging rather than failing on errors
+func (r *Runner) closeFilter(filter *filters.Filter) {
+	path := filter.Path()
+	if err := filter.Close(); err != nil {
+		r.logger.Debugf("Failed to remove temp file %s: %v", path, err)
 	}
 }
 
 // generatePatch creates a git patch file instead of syncing
-func (r *Runner) generatePatch(opts *Options) error {
+func (r *Runner) generatePatch(ctx context.Context, opts *Options) error {
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		return fmt.Errorf("patch generation reads both trees directly and needs a local source and dest")
+	}
 	if opts.DryRun {
 		r.logger.Infof("Would generate patch file: %s", opts.Patch)
 		r.logger.Infof("Would include changes from %s to %s", opts.Source, opts.Dest)
 		return nil
 	}
 
-	// Build filter files to respect ignore patterns
-	sourceFilter, err := r.buildSourceFilter(opts)
-	if err != nil {
-		return fmt.Errorf("error building source filter: %w", err)
-	}
-	defer r.cleanupTempFile(sourceFilter)
-
 	// Create the patch file
 	patchFile, err := os.Create(opts.Patch)
 	if err != nil {
@@ -343,25 +1193,16 @@ func (r *Runner) generatePatch(opts *Options) error {
 	fmt.Fprintf(patchFile, "# Destination: %s\n", opts.Dest)
 	fmt.Fprintf(patchFile, "# Generated: %s\n\n", time.Now().Format(time.RFC3339))
 
-	// Use git diff to generate the patch
-	cmd := exec.Command("git", "diff", "--no-index", "--no-prefix", opts.Dest, opts.Source)
-	cmd.Dir = filepath.Dir(opts.Source)
-	
-	output, err := cmd.CombinedOutput()
-	// git diff returns exit code 1 when there are differences, which is expected
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			// This is expected when there are differences
-		} else {
-			r.logger.Debugf("git diff command failed: %v", err)
-			// Fall back to a simple diff if git is not available or fails
-			return r.generateSimplePatch(opts, patchFile)
+	if gitAvailable() {
+		r.logger.Debug("Generating patch with git diff")
+		if err := r.writeGitDiff(ctx, opts, patchFile); err != nil {
+			return err
+		}
+	} else {
+		r.logger.Info("git not found; generating patch with the built-in diff")
+		if err := r.generateSimplePatch(opts, patchFile); err != nil {
+			return err
 		}
-	}
-
-	// Write the diff output to the patch file
-	if _, err := patchFile.Write(output); err != nil {
-		return fmt.Errorf("error writing patch content: %w", err)
 	}
 
 	r.logger.Infof("Patch file generated: %s", opts.Patch)
@@ -374,27 +1215,50 @@ func (r *Runner) generatePatch(opts *Options) error {
 	return nil
 }
 
-// generateSimplePatch creates a basic patch when git is not available
+// writeGitDiff writes `git diff --no-index` output for dest -> source to the patch file
+func (r *Runner) writeGitDiff(ctx context.Context, opts *Options, patchFile *os.File) error {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--no-index", "--no-prefix", opts.Dest, opts.Source)
+	cmd.Dir = filepath.Dir(opts.Source)
+
+	output, err := cmd.Output()
+	// git diff returns exit code 1 when there are differences, which is expected
+	if exitErr, ok := err.(*exec.ExitError); err != nil && !(ok && exitErr.ExitCode() == 1) {
+		return fmt.Errorf("git diff failed: %w", err)
+	}
+
+	if _, err := patchFile.Write(output); err != nil {
+		return fmt.Errorf("error writing patch content: %w", err)
+	}
+	return nil
+}
+
+// generateSimplePatch writes a unified diff with the built-in differ when git is not available
 func (r *Runner) generateSimplePatch(opts *Options, patchFile *os.File) error {
-	fmt.Fprintf(patchFile, "# Simple patch (git not available)\n")
-	fmt.Fprintf(patchFile, "# Files would be synchronized from %s to %s\n", opts.Source, opts.Dest)
-	
-	// Just create a basic listing of files that would be synced
-	err := filepath.Walk(opts.Source, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() {
-			relPath, err := filepath.Rel(opts.Source, path)
-			if err != nil {
-				return err
-			}
-			fmt.Fprintf(patchFile, "# Would sync: %s\n", relPath)
-		}
-		return nil
-	})
-	
-	return err
+	fmt.Fprintf(patchFile, "# Built-in diff (git not available)\n")
+	if err := writeUnifiedDiff(patchFile, opts); err != nil {
+		return fmt.Errorf("error writing patch content: %w", err)
+	}
+	return nil
+}
+
+// sourceDevice returns the device id of the source root when --one-file-system is set
+func sourceDevice(opts *Options) (uint64, error) {
+	if !opts.OneFileSystem {
+		return 0, nil
+	}
+	info, err := os.Stat(opts.Source)
+	if err != nil {
+		return 0, err
+	}
+	return deviceID(info), nil
+}
+
+// sameDevice reports whether info lives on the root device (always true when the device is unknown)
+func sameDevice(rootDevice uint64, info os.FileInfo) bool {
+	if rootDevice == 0 {
+		return true
+	}
+	return deviceID(info) == rootDevice
 }
 
 // applyPatchWithConfirmation applies the generated patch with user confirmation
@@ -412,10 +1276,16 @@ func (r *Runner) applyPatchWithConfirmation(opts *Options) error {
 	
 	// Show patch preview
 	r.logger.Info("Patch contents:")
-	previewCmd := exec.Command("git", "apply", "--stat", patchPath)
-	previewOutput, err := previewCmd.CombinedOutput()
-	if err != nil {
-		r.logger.Warnf("Could not preview patch stats: %v", err)
+	useGit := gitAvailable()
+	var previewOutput []byte
+	var err error
+	if useGit {
+		previewOutput, err = exec.Command("git", "apply", "--stat", patchPath).CombinedOutput()
+		if err != nil {
+			r.logger.Warnf("Could not preview patch stats: %v", err)
+		}
+	}
+	if !useGit || err != nil {
 		// Fallback to showing first few lines of the patch
 		if patchContent, readErr := os.ReadFile(patchPath); readErr == nil {
 			lines := strings.Split(string(patchContent), "\n")
@@ -449,12 +1319,20 @@ func (r *Runner) applyPatchWithConfirmation(opts *Options) error {
 		return fmt.Errorf("error getting absolute path for patch: %w", err)
 	}
 	
-	applyCmd := exec.Command("git", "apply", absPatchPath)
+	var applyCmd *exec.Cmd
+	if useGit {
+		applyCmd = exec.Command("git", "apply", absPatchPath)
+	} else if _, err := exec.LookPath("patch"); err == nil {
+		r.logger.Info("git not found; applying patch with patch -p1")
+		applyCmd = exec.Command("patch", "-p1", "--forward", "-i", absPatchPath)
+	} else {
+		return fmt.Errorf("failed to apply patch: neither git nor patch is installed")
+	}
 	applyCmd.Dir = opts.Dest
-	
+
 	if output, err := applyCmd.CombinedOutput(); err != nil {
 		r.logger.Errorf("Failed to apply patch: %v", err)
-		r.logger.Errorf("Git apply output: %s", string(output))
+		r.logger.Errorf("%s output: %s", filepath.Base(applyCmd.Path), string(output))
 		return fmt.Errorf("failed to apply patch: %w", err)
 	}
 	
@@ -475,25 +1353,30 @@ func (r *Runner) confirmPatchApplication(patchPath string) bool {
 	return response == "y" || response == "yes"
 }
 
-// showPreview generates and displays a colored diff preview
-func (r *Runner) showPreview(opts *Options) error {
+// showPreview generates a diff preview in opts.PreviewFormat and displays it with a pager
+func (r *Runner) showPreview(ctx context.Context, opts *Options) error {
 	r.logger.Infof("Generating preview: %s -> %s",
 		opts.Source, opts.Dest)
 	
-	// Generate diff using git diff with color
-	cmd := exec.Command("git", "diff", "--no-index", "--no-prefix", "--color=always", opts.Dest, opts.Source)
-	cmd.Dir = filepath.Dir(opts.Source)
-	
-	output, err := cmd.CombinedOutput()
-	// git diff returns exit code 1 when there are differences, which is expected
+	// Diffs can only read local trees, so remote and daemon targets use rsync's view
+	if IsRemotePath(opts.Source) || IsRemotePath(opts.Dest) {
+		r.logger.Info("Remote target; showing rsync dry-run preview")
+		return r.showSimplePreview(ctx, opts)
+	}
+
+	format := opts.PreviewFormat
+	if format == "" {
+		format = "git"
+	}
+	if format == "git" && !gitAvailable() {
+		r.logger.Info("git not found; showing the built-in unified diff")
+		format = "unified"
+	}
+	r.logger.Debugf("Generating %s preview", format)
+
+	output, err := previewOutput(ctx, opts, format)
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
-			// This is expected when there are differences
-		} else {
-			r.logger.Debugf("git diff command failed: %v", err)
-			// Fall back to a simple diff if git is not available
-			return r.showSimplePreview(opts)
-		}
+		return err
 	}
 	
 	// If there's no output, there are no differences
@@ -501,73 +1384,34 @@ func (r *Runner) showPreview(opts *Options) error {
 		r.logger.Info("No differences found between source and destination")
 		return nil
 	}
-	
-	// Check if less is available for paging
-	if _, err := exec.LookPath("less"); err == nil {
-		// Use less with options similar to git diff
-		lessCmd := exec.Command("less", "-R", "-FX")
-		lessCmd.Stdin = strings.NewReader(string(output))
-		lessCmd.Stdout = os.Stdout
-		lessCmd.Stderr = os.Stderr
-		
-		r.logger.Debug("Displaying preview with pager (press 'q' to quit)")
-		return lessCmd.Run()
-	} else {
-		// Fall back to direct output if less is not available
-		r.logger.Debug("Pager not available, displaying preview directly")
-		fmt.Print(string(output))
-		return nil
-	}
+	return r.page(output)
 }
 
-// showSimplePreview shows a simple preview when git is not available
-func (r *Runner) showSimplePreview(opts *Options) error {
+// showSimplePreview shows rsync's dry-run itemization, for targets a diff can't read
+func (r *Runner) showSimplePreview(ctx context.Context, opts *Options) error {
 	// Use rsync's dry-run to show what would be changed
-	r.logger.Info("Git not available, showing rsync dry-run preview:")
-	
 	// Build filter files
 	sourceFilter, err := r.buildSourceFilter(opts)
 	if err != nil {
 		return fmt.Errorf("error building source filter: %w", err)
 	}
-	defer r.cleanupTempFile(sourceFilter)
+	defer r.closeFilter(sourceFilter)
 	
-	var destFilter string
-	if len(opts.IgnoreDest) > 0 {
-		destFilter, err = r.buildDestFilter(opts)
-		if err != nil {
-			return fmt.Errorf("error building dest filter: %w", err)
-		}
-		defer r.cleanupTempFile(destFilter)
+	destFilter, err := r.buildDestFilter(opts)
+	if err != nil {
+		return fmt.Errorf("error building dest filter: %w", err)
 	}
+	defer r.closeFilter(destFilter)
 	
 	// Build rsync command with dry-run and itemize changes
-	args := []string{
-		"--archive",
-		"--verbose",
-		"--human-readable",
-		"--delete",
-		"--delete-excluded",
-		"--dry-run",
-		"--itemize-changes",
-	}
+	previewOpts := *opts
+	previewOpts.DryRun = true
+	args := append([]string{"--itemize-changes"}, r.buildRsyncArgs(&previewOpts, sourceFilter.Path(), destFilter.Path(), "")...)
 	
-	// Add filter files
-	if sourceFilter != "" {
-		args = append(args, "--filter", fmt.Sprintf(". %s", sourceFilter))
-	}
-	if destFilter != "" {
-		args = append(args, "--filter", fmt.Sprintf(". %s", destFilter))
-	}
-	
-	// Add source and destination
-	source := opts.Source
-	if !strings.HasSuffix(source, "/") {
-		source += "/"
+	if err := CheckRsync(opts); err != nil {
+		return err
 	}
-	args = append(args, source, opts.Dest)
-	
-	cmd := exec.Command("rsync", args...)
+	cmd := rsyncCommand(ctx, opts, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil && !strings.Contains(err.Error(), "exit status 23") {
 		// Exit status 23 is partial transfer due to error, often from dry-run
@@ -0,0 +1,38 @@
+package rsync
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuildRsyncArgsArchive(t *testing.T) {
+	r := &Runner{}
+	opts := &Options{Source: "/src", Dest: "/dest", Links: "preserve"}
+
+	args := r.buildRsyncArgs(opts, "", "", "")
+	if args[0] != "--archive" {
+		t.Errorf("args start with %q, want --archive", args[0])
+	}
+	for _, unwanted := range []string{"--recursive", "--links"} {
+		if slices.Contains(args, unwanted) {
+			t.Errorf("args %v contain %s, which --archive already implies", args, unwanted)
+		}
+	}
+
+	opts.NoArchive = true
+	args = r.buildRsyncArgs(opts, "", "", "")
+	if slices.Contains(args, "--archive") {
+		t.Errorf("--no-archive args %v still contain --archive", args)
+	}
+	for _, want := range []string{"--recursive", "--links", "--delete"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("--no-archive args %v are missing %s", args, want)
+		}
+	}
+
+	opts.Links = "copy"
+	args = r.buildRsyncArgs(opts, "", "", "")
+	if slices.Contains(args, "--links") || !slices.Contains(args, "--copy-links") {
+		t.Errorf("--no-archive --links copy args = %v, want --copy-links without --links", args)
+	}
+}
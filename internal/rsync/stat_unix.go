@@ -0,0 +1,24 @@
+//go:build !windows
+
+package rsync
+
+import (
+	"os"
+	"syscall"
+)
+
+// deviceID returns the id of the device holding the file, or 0 if unknown
+func deviceID(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Dev)
+	}
+	return 0
+}
+
+// fileOwner returns the uid and gid owning the file
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return int(stat.Uid), int(stat.Gid), true
+	}
+	return 0, 0, false
+}
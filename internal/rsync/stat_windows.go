@@ -0,0 +1,15 @@
+//go:build windows
+
+package rsync
+
+import "os"
+
+// deviceID is unavailable on Windows, so --one-file-system is left to rsync itself
+func deviceID(info os.FileInfo) uint64 {
+	return 0
+}
+
+// fileOwner is unavailable on Windows, which has no uid/gid ownership model
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
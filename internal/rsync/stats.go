@@ -0,0 +1,221 @@
+package rsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// itemizeFormat makes rsync print "<itemize> <length> <name>" for every change, so the
+// fixed-width leading columns can be parsed without splitting names on whitespace
+const itemizeFormat = "%i %l %n"
+
+// SyncStats counts the changes rsync reported during a sync
+type SyncStats struct {
+	FilesCreated     int
+	FilesUpdated     int
+	FilesDeleted     int
+	DirsCreated      int
+	DirsDeleted      int
+	Conflicts        int
+	BytesTransferred int64
+	// FilesUnchanged counts files rsync found already up to date, which it lists only
+	// with Options.IncludeUnchanged
+	FilesUnchanged int
+	// Skipped lists the files --ignore-errors let the sync finish without
+	Skipped []string
+	// Duration is how long the sync took, including filter setup and conflict handling
+	Duration time.Duration
+}
+
+// ChangeKind says what rsync did to a path
+type ChangeKind string
+
+const (
+	ChangeCreated ChangeKind = "created"
+	ChangeUpdated ChangeKind = "updated"
+	ChangeDeleted ChangeKind = "deleted"
+	// ChangeUnchanged is a file rsync left alone because the dest already matched it
+	ChangeUnchanged ChangeKind = "unchanged"
+)
+
+// Change is one file or directory created, updated, or deleted by rsync, or with
+// Options.IncludeUnchanged a file it found up to date
+type Change struct {
+	Kind  ChangeKind
+	Path  string
+	IsDir bool
+	Size  int64
+}
+
+// parseChange parses one line of itemized rsync output. Lines that aren't itemized changes,
+// attribute-only updates, and directory updates are reported as not ok. A bare ".f" or ".L"
+// is a file or symlink listed by --info=name2 that is already up to date.
+func parseChange(line string) (Change, bool) {
+	itemize, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return Change{}, false
+	}
+	sizeField, name, ok := strings.Cut(strings.TrimLeft(rest, " "), " ")
+	if !ok || name == "" {
+		return Change{}, false
+	}
+	size, err := strconv.ParseInt(strings.ReplaceAll(sizeField, ",", ""), 10, 64)
+	if err != nil {
+		return Change{}, false
+	}
+
+	// rsync marks directories with a trailing slash in %n
+	change := Change{Path: name, IsDir: strings.HasSuffix(name, "/"), Size: size}
+	if itemize == "*deleting" {
+		change.Kind = ChangeDeleted
+		return change, true
+	}
+
+	if (itemize == ".f" || itemize == ".L") && !change.IsDir {
+		change.Kind = ChangeUnchanged
+		return change, true
+	}
+	if len(itemize) < 3 || !strings.ContainsRune("<>ch.", rune(itemize[0])) {
+		return Change{}, false
+	}
+	created := strings.Trim(itemize[2:], "+") == ""
+	switch {
+	case created:
+		change.Kind = ChangeCreated
+	case change.IsDir || itemize[0] == '.':
+		// Directory timestamps and attribute-only changes transfer nothing
+		return Change{}, false
+	default:
+		change.Kind = ChangeUpdated
+	}
+	return change, true
+}
+
+// record updates the stats with one change
+func (s *SyncStats) record(change Change) {
+	switch {
+	case change.IsDir && change.Kind == ChangeCreated:
+		s.DirsCreated++
+	case change.IsDir && change.Kind == ChangeDeleted:
+		s.DirsDeleted++
+	case change.Kind == ChangeCreated:
+		s.FilesCreated++
+		s.BytesTransferred += change.Size
+	case change.Kind == ChangeUpdated:
+		s.FilesUpdated++
+		s.BytesTransferred += change.Size
+	case change.Kind == ChangeDeleted:
+		s.FilesDeleted++
+	case change.Kind == ChangeUnchanged:
+		s.FilesUnchanged++
+	}
+}
+
+// Add accumulates another run's stats, e.g. across the operations of a SyncFile
+func (s *SyncStats) Add(other SyncStats) {
+	s.FilesCreated += other.FilesCreated
+	s.FilesUpdated += other.FilesUpdated
+	s.FilesDeleted += other.FilesDeleted
+	s.DirsCreated += other.DirsCreated
+	s.DirsDeleted += other.DirsDeleted
+	s.Conflicts += other.Conflicts
+	s.BytesTransferred += other.BytesTransferred
+	s.FilesUnchanged += other.FilesUnchanged
+	s.Skipped = append(s.Skipped, other.Skipped...)
+	s.Duration += other.Duration
+}
+
+// Changes returns how many files and directories were created, updated, or deleted
+func (s *SyncStats) Changes() int {
+	return s.FilesCreated + s.FilesUpdated + s.FilesDeleted + s.DirsCreated + s.DirsDeleted
+}
+
+// Throughput returns the transfer rate in MB/s (decimal megabytes), or 0 for an instant sync
+func (s *SyncStats) Throughput() float64 {
+	return throughput(s.BytesTransferred, s.Duration)
+}
+
+// throughput converts bytes moved over elapsed into MB/s
+func throughput(bytes int64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(bytes) / 1e6 / elapsed.Seconds()
+}
+
+// FormatDuration rounds d to milliseconds for logs and reports, e.g. 1.234s
+func FormatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
+
+// Badge renders the stats as a single greppable line for CI logs
+func (s *SyncStats) Badge() string {
+	return fmt.Sprintf("SYNC_SUMMARY created=%d updated=%d deleted=%d conflicts=%d bytes=%d",
+		s.FilesCreated, s.FilesUpdated, s.FilesDeleted, s.Conflicts, s.BytesTransferred)
+}
+
+// StatsRecord is one run's entry in a JSON-lines stats log
+type StatsRecord struct {
+	Timestamp        time.Time `json:"timestamp"`
+	Source           string    `json:"source"`
+	Dest             string    `json:"dest"`
+	Mode             string    `json:"mode"`
+	DryRun           bool      `json:"dry_run"`
+	FilesCreated     int       `json:"files_created"`
+	FilesUpdated     int       `json:"files_updated"`
+	FilesDeleted     int       `json:"files_deleted"`
+	Conflicts        int       `json:"conflicts"`
+	BytesTransferred int64     `json:"bytes_transferred"`
+	FilesUnchanged   int       `json:"files_unchanged,omitempty"`
+	FilesSkipped     int       `json:"files_skipped,omitempty"`
+	DurationMs       int64     `json:"duration_ms"`
+	ExitStatus       int       `json:"exit_status"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// NewStatsRecord builds a stats log entry for a run that started at start and ended with runErr
+func NewStatsRecord(opts *Options, stats SyncStats, start time.Time, runErr error) StatsRecord {
+	record := StatsRecord{
+		Timestamp:        start.UTC(),
+		Source:           opts.Source,
+		Dest:             opts.Dest,
+		Mode:             opts.Mode,
+		DryRun:           opts.DryRun,
+		FilesCreated:     stats.FilesCreated,
+		FilesUpdated:     stats.FilesUpdated,
+		FilesDeleted:     stats.FilesDeleted,
+		Conflicts:        stats.Conflicts,
+		BytesTransferred: stats.BytesTransferred,
+		FilesUnchanged:   stats.FilesUnchanged,
+		FilesSkipped:     len(stats.Skipped),
+		DurationMs:       time.Since(start).Milliseconds(),
+	}
+	if runErr != nil {
+		record.ExitStatus = 1
+		record.Error = runErr.Error()
+	}
+	return record
+}
+
+// AppendStatsRecord appends the record as a single JSON line, creating the log if needed
+func AppendStatsRecord(path string, record StatsRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding stats record: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening stats log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing stats log: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package rsync
+
+import "testing"
+
+func TestParseChange(t *testing.T) {
+	tests := []struct {
+		line string
+		want Change
+		ok   bool
+	}{
+		{">f+++++++++ 1,024 docs/new.txt", Change{Kind: ChangeCreated, Path: "docs/new.txt", Size: 1024}, true},
+		{">f.st...... 12 docs/edited.txt", Change{Kind: ChangeUpdated, Path: "docs/edited.txt", Size: 12}, true},
+		{"*deleting   0 old/", Change{Kind: ChangeDeleted, Path: "old/", IsDir: true}, true},
+		{".f          30 docs/same txt", Change{Kind: ChangeUnchanged, Path: "docs/same txt", Size: 30}, true},
+		{">f+++++++++ 12 café/naïve file.txt", Change{Kind: ChangeCreated, Path: "café/naïve file.txt", Size: 12}, true},
+		{"*deleting   0 données v2.d/", Change{Kind: ChangeDeleted, Path: "données v2.d/", IsDir: true}, true},
+		{"*deleting   5 Makefile", Change{Kind: ChangeDeleted, Path: "Makefile", Size: 5}, true},
+		{".L          8 link -> target", Change{Kind: ChangeUnchanged, Path: "link -> target", Size: 8}, true},
+		{".d          4,096 docs/", Change{}, false},
+		{".f...p..... 12 docs/chmodded.txt", Change{}, false},
+		{"sending incremental file list", Change{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseChange(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseChange(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRecordUnchanged(t *testing.T) {
+	var stats SyncStats
+	stats.record(Change{Kind: ChangeUnchanged, Path: "same.txt", Size: 30})
+	if stats.FilesUnchanged != 1 || stats.BytesTransferred != 0 || stats.Changes() != 0 {
+		t.Errorf("stats after an unchanged file = %+v, want one unchanged file and no changes", stats)
+	}
+}
@@ -0,0 +1,69 @@
+package rsync
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/filters"
+)
+
+// nestedSyncignoreRules walks a local source for .syncignore files below its root and turns
+// each into rules scoped to its directory. Deeper files come first, so as with nested
+// .gitignore files the one closest to a path decides it. Directories excluded by the
+// top-level rules aren't visited, since rsync never descends into them, and unreadable ones
+// are left for rsync to report.
+func (r *Runner) nestedSyncignoreRules(opts *Options, topLevel []string) ([]filterRule, error) {
+	type ignoreFile struct {
+		depth int
+		rules []filterRule
+	}
+
+	rules := filters.ParseRules(topLevel)
+	var files []ignoreFile
+	err := filepath.WalkDir(opts.Source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() || path == opts.Source {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(opts.Source, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if !filters.Match(rules, relPath, true).Included {
+			return filepath.SkipDir
+		}
+
+		syncignoreFile := filepath.Join(path, ".syncignore")
+		if _, err := os.Stat(syncignoreFile); err != nil {
+			return nil
+		}
+		patterns, err := r.readIgnoreFile(syncignoreFile)
+		if err != nil {
+			return err
+		}
+		r.logger.Debugf("Applying %s/.syncignore to its subtree", relPath)
+		lines := filters.NestedExcludeLines(relPath, patterns)
+		files = append(files, ignoreFile{depth: strings.Count(relPath, "/"), rules: annotateRules(relPath+"/.syncignore", lines)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].depth > files[j].depth })
+	var nested []filterRule
+	for _, file := range files {
+		nested = append(nested, file.rules...)
+	}
+	return nested, nil
+}
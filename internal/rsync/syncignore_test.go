@@ -0,0 +1,74 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DamianReeves/sync-tools/internal/logging"
+)
+
+func TestNestedSyncignorePrecedence(t *testing.T) {
+	logger, err := logging.Setup("ERROR", "", "text", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := t.TempDir()
+	files := map[string]string{
+		".syncignore":             "*.log\n!drafts/notes.tmp\nexcluded/\n",
+		"logs/.syncignore":        "!debug.log\n",
+		"drafts/.syncignore":      "*.tmp\n",
+		"drafts/deep/.syncignore": "!wip.tmp\n",
+		"vendor/.syncignore":      "*.go\n",
+		"excluded/.syncignore":    "!*.log\n",
+		"logs/debug.log":          "",
+		"logs/error.log":          "",
+		"drafts/notes.tmp":        "",
+		"drafts/deep/wip.tmp":     "",
+		"drafts/deep/old.tmp":     "",
+		"vendor/lib.go":           "",
+		"excluded/app.log":        "",
+		"main.go":                 "",
+	}
+	for name, content := range files {
+		path := filepath.Join(source, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		included bool
+	}{
+		{"logs/debug.log", true},       // the subdirectory's unignore beats the root's *.log
+		{"logs/error.log", false},      // the root's rules still apply below
+		{"drafts/notes.tmp", false},    // the subdirectory's *.tmp beats the root's unignore
+		{"drafts/deep/wip.tmp", true},  // the deepest file wins
+		{"drafts/deep/old.tmp", false}, // unanchored patterns match at any depth
+		{"vendor/lib.go", false},
+		{"main.go", true},           // nested rules stay in their subtree
+		{"excluded/app.log", false}, // an excluded directory's .syncignore isn't read
+	}
+	runner := NewRunner(logger)
+	for _, tt := range tests {
+		decision, err := runner.CheckFilter(&Options{Source: source}, tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decision.Included != tt.included {
+			t.Errorf("CheckFilter(%q) = %s, want included %v", tt.path, decision, tt.included)
+		}
+	}
+
+	decision, err := runner.CheckFilter(&Options{Source: source, NoNestedSyncignore: true}, "logs/debug.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decision.Included {
+		t.Errorf("CheckFilter with NoNestedSyncignore = %s, want only the root's *.log to apply", decision)
+	}
+}
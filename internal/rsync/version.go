@@ -0,0 +1,53 @@
+package rsync
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern finds the release in rsync's banner, e.g. "rsync  version 3.2.7  protocol version 31",
+// or in macOS openrsync's "rsync version 2.6.9 compatible" line, skipping protocol versions
+var versionPattern = regexp.MustCompile(`rsync\s+version\s+v?(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// Version is a parsed rsync release number
+type Version struct {
+	Major, Minor, Patch int
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is major.minor or newer
+func (v Version) AtLeast(major, minor int) bool {
+	return v.Major > major || v.Major == major && v.Minor >= minor
+}
+
+// ParseVersion extracts the version from `rsync --version` output
+func ParseVersion(output string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		firstLine, _, _ := strings.Cut(output, "\n")
+		return Version{}, fmt.Errorf("unrecognized rsync version output: %q", firstLine)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// RsyncVersion runs the configured rsync binary with --version and parses the result
+func RsyncVersion(opts *Options) (Version, error) {
+	output, err := exec.Command(rsyncBinary(opts), "--version").Output()
+	if err != nil {
+		return Version{}, fmt.Errorf("error running %s --version: %w", rsyncBinary(opts), err)
+	}
+	return ParseVersion(string(output))
+}
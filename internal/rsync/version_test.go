@@ -0,0 +1,51 @@
+package rsync
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		output string
+		want   Version
+	}{
+		{"rsync  version 3.2.7  protocol version 31\nCopyright (C) 1996-2022 by Andrew Tridgell", Version{3, 2, 7}},
+		{"rsync  version 2.6.9  protocol version 29", Version{2, 6, 9}},
+		{"openrsync: protocol version 29\nrsync version 2.6.9 compatible", Version{2, 6, 9}},
+		{"rsync  version v3.3.0  protocol version 31", Version{3, 3, 0}},
+		{"rsync version 3.1", Version{3, 1, 0}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.output)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) returned error: %v", tt.output, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+
+	if _, err := ParseVersion("not rsync"); err == nil {
+		t.Error("ParseVersion accepted output without a version")
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		version      Version
+		major, minor int
+		want         bool
+	}{
+		{Version{3, 2, 7}, 3, 1, true},
+		{Version{3, 1, 0}, 3, 1, true},
+		{Version{3, 0, 9}, 3, 1, false},
+		{Version{2, 6, 9}, 3, 1, false},
+		{Version{4, 0, 0}, 3, 1, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.version.AtLeast(tt.major, tt.minor); got != tt.want {
+			t.Errorf("%v.AtLeast(%d, %d) = %v, want %v", tt.version, tt.major, tt.minor, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package rsync
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// checkFakeSuperSupport warns when a local dest can't hold the extended attributes
+// --fake-super stores ownership, permissions, and devices in, since rsync would then fail to
+// record them. The check writes a scratch file, so dry runs skip it. The dest may not exist
+// yet, so its nearest existing parent is checked.
+func (r *Runner) checkFakeSuperSupport(opts *Options) {
+	if IsRemotePath(opts.Dest) || opts.DryRun {
+		r.logger.Debugf("Not checking %s for the extended attribute support --fake-super needs", opts.Dest)
+		return
+	}
+
+	dir := opts.Dest
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+
+	supported, known := xattrSupport(dir)
+	switch {
+	case !known:
+		r.logger.Debugf("Could not check %s for the extended attribute support --fake-super needs", dir)
+	case !supported:
+		r.logger.Warnf("%s does not support extended attributes, so --fake-super can't record ownership there; use a filesystem with user xattrs (e.g. ext4, XFS, Btrfs)", dir)
+	}
+}
@@ -0,0 +1,24 @@
+package rsync
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// xattrSupport reports whether files in dir can carry user extended attributes, by setting
+// one on a scratch file. known is false when the probe itself couldn't run.
+func xattrSupport(dir string) (supported, known bool) {
+	probe, err := os.CreateTemp(dir, ".sync-tools-xattr-*")
+	if err != nil {
+		return false, false
+	}
+	defer os.Remove(probe.Name())
+	probe.Close()
+
+	err = syscall.Setxattr(probe.Name(), "user.sync-tools.probe", []byte("1"), 0)
+	if errors.Is(err, syscall.ENOTSUP) {
+		return false, true
+	}
+	return err == nil, err == nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package rsync
+
+// xattrSupport can't probe extended attributes portably outside Linux, so it reports unknown
+func xattrSupport(dir string) (supported, known bool) {
+	return false, false
+}
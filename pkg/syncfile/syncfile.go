@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -26,18 +29,57 @@ const (
 	InstExclude     InstructionType = "EXCLUDE"     // EXCLUDE pattern
 	InstInclude     InstructionType = "INCLUDE"     // INCLUDE pattern (unignore)
 	InstOnly        InstructionType = "ONLY"        // ONLY pattern (whitelist mode)
+	InstExcludeIfPresent InstructionType = "EXCLUDEIFPRESENT" // EXCLUDEIFPRESENT .nobackup (skip dirs holding this file)
+	InstFilter      InstructionType = "FILTER"      // FILTER - *.tmp (raw rsync filter rule, after the generated ones)
 	
 	// Configuration instructions
 	InstMode        InstructionType = "MODE"        // MODE one-way|two-way
 	InstDryRun      InstructionType = "DRYRUN"      // DRYRUN true|false
 	InstUseGitignore InstructionType = "GITIGNORE"  // GITIGNORE true|false
+	InstUseGlobalGitignore InstructionType = "GLOBALGITIGNORE" // GLOBALGITIGNORE true|false (git's core.excludesFile)
 	InstHiddenDirs  InstructionType = "HIDDENDIRS"  // HIDDENDIRS exclude|include
+	InstRelative    InstructionType = "RELATIVE"    // RELATIVE true|false (recreate the source path under the dest)
+	InstPruneEmptyDirs InstructionType = "PRUNEEMPTYDIRS" // PRUNEEMPTYDIRS true|false (skip dirs the filters leave empty)
+	InstSuper       InstructionType = "SUPER"       // SUPER true|false (rsync --super)
+	InstFakeSuper   InstructionType = "FAKESUPER"   // FAKESUPER true|false (keep ownership in dest xattrs)
+
+	// Performance tuning instructions
+	InstWholeFile   InstructionType = "WHOLEFILE"   // WHOLEFILE true|false
+	InstInplace     InstructionType = "INPLACE"     // INPLACE true|false
+	InstSparse      InstructionType = "SPARSE"      // SPARSE true|false
+	InstDelayUpdates InstructionType = "DELAYUPDATES" // DELAYUPDATES true|false
+	InstSizeOnly    InstructionType = "SIZEONLY"    // SIZEONLY true|false (rsync --size-only)
+	InstPartial     InstructionType = "PARTIAL"     // PARTIAL true|false (keep partial files to resume)
+	InstPartialDir  InstructionType = "PARTIALDIR"  // PARTIALDIR .rsync-partial (rsync --partial-dir)
+	InstIgnoreTimes InstructionType = "IGNORETIMES" // IGNORETIMES true|false (rsync --ignore-times)
+	InstChecksum    InstructionType = "CHECKSUM"    // CHECKSUM xxh128|md5|... (rsync --checksum-choice)
+	InstDedupe      InstructionType = "DEDUPE"      // DEDUPE true|false (hard-link duplicate content in the dest)
+	InstNoArchive   InstructionType = "NOARCHIVE"   // NOARCHIVE true|false (rsync --recursive instead of --archive)
+	InstIncludeUnchanged InstructionType = "INCLUDEUNCHANGED" // INCLUDEUNCHANGED true|false (list and count up-to-date files)
+
+	// Error handling instructions
+	InstIgnoreErrors InstructionType = "IGNOREERRORS" // IGNOREERRORS true|false (skip unreadable files instead of failing)
+
+	// rsync program instructions
+	InstRsyncBin    InstructionType = "RSYNCBIN"    // RSYNCBIN path
+	InstRsyncArgs   InstructionType = "RSYNCARGS"   // RSYNCARGS --chmod=D755 --rsh="ssh -p 2222" (passed to rsync verbatim)
+
+	// Safety instructions
+	InstSafeMode    InstructionType = "SAFEMODE"    // SAFEMODE true|false (applies to every SYNC in the file)
+
+	// Watch instructions
+	InstWatch       InstructionType = "WATCH"       // WATCH true|false (re-run on source changes under syncfile --watch)
 	
 	// Patch instructions
 	InstPatch       InstructionType = "PATCH"       // PATCH filename
 	InstApplyPatch  InstructionType = "APPLYPATCH"  // APPLYPATCH true|false
 	InstPreview     InstructionType = "PREVIEW"     // PREVIEW true|false
+	InstPreviewFormat InstructionType = "PREVIEWFORMAT" // PREVIEWFORMAT git|unified|side-by-side|stat
 	InstAutoConfirm InstructionType = "AUTOCONFIRM" // AUTOCONFIRM true|false (like -y flag)
+
+	// Output instructions
+	InstReport      InstructionType = "REPORT"      // REPORT docs-sync.md (markdown report, or a patch for .patch/.diff)
+	InstPlan        InstructionType = "PLAN"        // PLAN docs-plan.md (write the planned changes instead of syncing)
 	
 	// Variable and environment instructions
 	InstVar         InstructionType = "VAR"         // VAR name=value
@@ -46,6 +88,12 @@ const (
 	// Advanced instructions
 	InstRun         InstructionType = "RUN"         // RUN command (pre/post sync hooks)
 	InstComment     InstructionType = "COMMENT"     // # Comment
+	InstIncludeFile InstructionType = "INCLUDE-FILE" // INCLUDE-FILE path (inlines another SyncFile)
+
+	// Conditional instructions
+	InstIf          InstructionType = "IF"          // IF ${VAR}==value
+	InstIfOS        InstructionType = "IF-OS"       // IF-OS darwin [linux ...]
+	InstEndIf       InstructionType = "ENDIF"       // ENDIF
 )
 
 // Instruction represents a single SyncFile instruction
@@ -56,22 +104,46 @@ type Instruction struct {
 	LineNum  int
 }
 
-// ParseSyncFile parses a SyncFile from the given path
+// ParseSyncFile parses a SyncFile from the given path, inlining any INCLUDE-FILE instructions
 func ParseSyncFile(path string) (*SyncFile, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open SyncFile: %w", err)
-	}
-	defer file.Close()
-
 	sf := &SyncFile{
 		Instructions: make([]Instruction, 0),
 		Variables:    make(map[string]string),
 	}
+	if err := sf.parseFile(path, nil); err != nil {
+		return nil, err
+	}
+	return sf, nil
+}
+
+// parseFile appends the instructions in path to sf. Included files share sf's variables, so
+// a VAR set in one is visible to everything after it. including holds the absolute paths of
+// the files currently being parsed, outermost first, to catch include cycles.
+func (sf *SyncFile) parseFile(path string, including []string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to open SyncFile: %w", err)
+	}
+	for i, parent := range including {
+		if parent == absPath {
+			cycle := append(slices.Clone(including[i:]), absPath)
+			return fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+	including = append(including, absPath)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open SyncFile: %w", err)
+	}
+	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
 
+	// Stack of IF conditions; instructions are only kept while every enclosing condition holds
+	var conditions []bool
+
 	for scanner.Scan() {
 		lineNum++
 		line := strings.TrimSpace(scanner.Text())
@@ -81,6 +153,21 @@ func ParseSyncFile(path string) (*SyncFile, error) {
 			continue
 		}
 
+		// Skip everything but nested IF/ENDIF inside a false branch
+		if !allTrue(conditions) && !strings.HasPrefix(line, "#") {
+			instruction, err := parseInstruction(line, lineNum)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			switch instruction.Type {
+			case InstIf, InstIfOS:
+				conditions = append(conditions, false)
+			case InstEndIf:
+				conditions = conditions[:len(conditions)-1]
+			}
+			continue
+		}
+
 		// Handle comments
 		if strings.HasPrefix(line, "#") {
 			sf.Instructions = append(sf.Instructions, Instruction{
@@ -94,7 +181,32 @@ func ParseSyncFile(path string) (*SyncFile, error) {
 		// Parse instruction
 		instruction, err := parseInstruction(line, lineNum)
 		if err != nil {
-			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		// The included file's instructions take the place of the INCLUDE-FILE line
+		if instruction.Type == InstIncludeFile {
+			includePath := expandVariables(instruction.Args[0], sf.Variables)
+			if !filepath.IsAbs(includePath) {
+				includePath = filepath.Join(filepath.Dir(path), includePath)
+			}
+			if err := sf.parseFile(includePath, including); err != nil {
+				return fmt.Errorf("line %d: INCLUDE-FILE %s: %w", lineNum, instruction.Args[0], err)
+			}
+			continue
+		}
+
+		// Track conditional blocks
+		switch instruction.Type {
+		case InstIf:
+			conditions = append(conditions, evaluateCondition(instruction.Args[0], sf.Variables))
+		case InstIfOS:
+			conditions = append(conditions, slices.Contains(instruction.Args, runtime.GOOS))
+		case InstEndIf:
+			if len(conditions) == 0 {
+				return fmt.Errorf("line %d: ENDIF without matching IF", lineNum)
+			}
+			conditions = conditions[:len(conditions)-1]
 		}
 
 		sf.Instructions = append(sf.Instructions, instruction)
@@ -111,10 +223,44 @@ func ParseSyncFile(path string) (*SyncFile, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading SyncFile: %w", err)
+		return fmt.Errorf("error reading SyncFile: %w", err)
 	}
 
-	return sf, nil
+	if len(conditions) > 0 {
+		return fmt.Errorf("%d IF block(s) missing ENDIF", len(conditions))
+	}
+
+	return nil
+}
+
+// allTrue reports whether every enclosing IF condition holds
+func allTrue(conditions []bool) bool {
+	for _, c := range conditions {
+		if !c {
+			return false
+		}
+	}
+	return true
+}
+
+// knownOSes are the runtime.GOOS values IF-OS accepts, so a typo like "macos" is an error
+// rather than a block that silently never runs
+var knownOSes = []string{"aix", "android", "darwin", "dragonfly", "freebsd", "illumos", "ios",
+	"js", "linux", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows"}
+
+// splitCondition splits an IF condition at its operator, == or a single =
+func splitCondition(condition string) (left, right string, ok bool) {
+	if left, right, ok = strings.Cut(condition, "=="); ok {
+		return left, right, true
+	}
+	return strings.Cut(condition, "=")
+}
+
+// evaluateCondition evaluates a "left==right" or "left=right" equality check after
+// variable expansion
+func evaluateCondition(condition string, vars map[string]string) bool {
+	left, right, _ := splitCondition(condition)
+	return strings.TrimSpace(expandConditionVariables(left, vars)) == strings.TrimSpace(expandConditionVariables(right, vars))
 }
 
 // parseInstruction parses a single instruction line
@@ -133,7 +279,7 @@ func parseInstruction(line string, lineNum int) (Instruction, error) {
 		if len(args) < 2 {
 			return Instruction{}, fmt.Errorf("SYNC requires at least 2 arguments: source dest")
 		}
-	case InstExclude, InstInclude, InstOnly:
+	case InstExclude, InstInclude, InstOnly, InstExcludeIfPresent:
 		if len(args) < 1 {
 			return Instruction{}, fmt.Errorf("%s requires at least 1 argument", instType)
 		}
@@ -141,14 +287,51 @@ func parseInstruction(line string, lineNum int) (Instruction, error) {
 		if len(args) != 1 || (args[0] != "one-way" && args[0] != "two-way") {
 			return Instruction{}, fmt.Errorf("MODE must be 'one-way' or 'two-way'")
 		}
-	case InstDryRun, InstUseGitignore, InstApplyPatch, InstPreview, InstAutoConfirm:
+	case InstDryRun, InstUseGitignore, InstUseGlobalGitignore, InstApplyPatch, InstPreview, InstAutoConfirm, InstWholeFile, InstInplace, InstSparse, InstDelayUpdates, InstSafeMode, InstWatch, InstIgnoreErrors, InstRelative, InstPruneEmptyDirs, InstSuper, InstFakeSuper, InstSizeOnly, InstIgnoreTimes, InstPartial, InstDedupe, InstNoArchive, InstIncludeUnchanged:
 		if len(args) != 1 || (args[0] != "true" && args[0] != "false") {
 			return Instruction{}, fmt.Errorf("%s must be 'true' or 'false'", instType)
 		}
-	case InstPatch:
+	case InstChecksum:
+		if len(args) != 1 || !slices.Contains(rsync.ChecksumChoices, args[0]) {
+			return Instruction{}, fmt.Errorf("CHECKSUM must be one of: %s", strings.Join(rsync.ChecksumChoices, ", "))
+		}
+	case InstPreviewFormat:
+		if len(args) != 1 || !slices.Contains(rsync.PreviewFormats, args[0]) {
+			return Instruction{}, fmt.Errorf("PREVIEWFORMAT must be one of: %s", strings.Join(rsync.PreviewFormats, ", "))
+		}
+	case InstPatch, InstReport, InstPlan:
+		if len(args) != 1 {
+			return Instruction{}, fmt.Errorf("%s requires exactly 1 argument: filename", instType)
+		}
+	case InstPartialDir:
+		if len(args) != 1 {
+			return Instruction{}, fmt.Errorf("PARTIALDIR requires exactly 1 argument: directory")
+		}
+	case InstIncludeFile:
 		if len(args) != 1 {
-			return Instruction{}, fmt.Errorf("PATCH requires exactly 1 argument: filename")
+			return Instruction{}, fmt.Errorf("INCLUDE-FILE requires exactly 1 argument: path")
+		}
+	case InstRsyncBin:
+		if len(args) != 1 {
+			return Instruction{}, fmt.Errorf("RSYNCBIN requires exactly 1 argument: path")
+		}
+	case InstRsyncArgs:
+		// Keep the raw text so quoted arguments survive; it is split after variable expansion
+		raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), parts[0]))
+		if raw == "" {
+			return Instruction{}, fmt.Errorf("RSYNCARGS requires at least 1 argument")
+		}
+		if _, err := rsync.SplitArgs(raw); err != nil {
+			return Instruction{}, fmt.Errorf("RSYNCARGS: %w", err)
 		}
+		args = []string{raw}
+	case InstFilter:
+		// A rule is a prefix and a pattern separated by one space, so keep the line's text as is
+		raw := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), parts[0]))
+		if raw == "" {
+			return Instruction{}, fmt.Errorf("FILTER requires a rule, e.g. FILTER - *.tmp")
+		}
+		args = []string{raw}
 	case InstHiddenDirs:
 		if len(args) != 1 || (args[0] != "exclude" && args[0] != "include") {
 			return Instruction{}, fmt.Errorf("HIDDENDIRS must be 'exclude' or 'include'")
@@ -161,6 +344,26 @@ func parseInstruction(line string, lineNum int) (Instruction, error) {
 		if len(args) < 1 {
 			return Instruction{}, fmt.Errorf("RUN requires at least 1 argument")
 		}
+	case InstIf:
+		// Allow spaces around the operator: IF ${ENV} == prod
+		condition := strings.Join(args, "")
+		if _, _, ok := splitCondition(condition); !ok {
+			return Instruction{}, fmt.Errorf("IF requires format: ${VAR}=value or ${VAR}==value")
+		}
+		args = []string{condition}
+	case InstIfOS:
+		if len(args) == 0 {
+			return Instruction{}, fmt.Errorf("IF-OS requires at least 1 argument: os")
+		}
+		for _, goos := range args {
+			if !slices.Contains(knownOSes, goos) {
+				return Instruction{}, fmt.Errorf("IF-OS: unknown OS %q (use a Go OS name such as darwin, linux, or windows)", goos)
+			}
+		}
+	case InstEndIf:
+		if len(args) != 0 {
+			return Instruction{}, fmt.Errorf("ENDIF takes no arguments")
+		}
 	default:
 		return Instruction{}, fmt.Errorf("unknown instruction: %s", instType)
 	}
@@ -172,6 +375,9 @@ func parseInstruction(line string, lineNum int) (Instruction, error) {
 	}, nil
 }
 
+// envReference matches a ${NAME} or $NAME reference left after the SyncFile's own variables
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
 // expandVariables expands variable references in a string
 func expandVariables(s string, vars map[string]string) string {
 	result := s
@@ -182,10 +388,26 @@ func expandVariables(s string, vars map[string]string) string {
 	return result
 }
 
+// expandConditionVariables expands an IF condition's variable references. The SyncFile's VAR
+// and ENV definitions win; other names come from the process environment, so IF ${ENV}==prod
+// can be decided by the caller. References to names defined in neither are kept as written.
+// Paths and arguments use expandVariables, so a typo there can't pick up an unrelated
+// environment variable.
+func expandConditionVariables(s string, vars map[string]string) string {
+	return envReference.ReplaceAllStringFunc(expandVariables(s, vars), func(ref string) string {
+		name := strings.Trim(ref, "${}")
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		return ref
+	})
+}
+
 // ToRsyncOptions converts a SyncFile to rsync.Options
 func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 	var optsList []*rsync.Options
 	var currentOpts *rsync.Options
+	var safeMode bool
 
 	for _, inst := range sf.Instructions {
 		switch inst.Type {
@@ -244,11 +466,143 @@ func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 				currentOpts.UseSourceGitignore = useGitignore
 			}
 
+		case InstUseGlobalGitignore:
+			if currentOpts != nil {
+				useGlobalGitignore, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.UseGlobalGitignore = useGlobalGitignore
+			}
+
 		case InstHiddenDirs:
 			if currentOpts != nil {
 				currentOpts.ExcludeHiddenDirs = (inst.Args[0] == "exclude")
 			}
 
+		case InstWholeFile:
+			if currentOpts != nil {
+				// false forces the delta algorithm rather than just leaving rsync's default
+				wholeFile, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.WholeFile = wholeFile
+				currentOpts.NoWholeFile = !wholeFile
+			}
+
+		case InstRsyncBin:
+			if currentOpts != nil {
+				currentOpts.RsyncBinary = expandVariables(inst.Args[0], sf.Variables)
+			}
+
+		case InstIgnoreErrors:
+			if currentOpts != nil {
+				ignoreErrors, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.IgnoreErrors = ignoreErrors
+			}
+
+		case InstChecksum:
+			if currentOpts != nil {
+				currentOpts.ChecksumChoice = inst.Args[0]
+			}
+
+		case InstRsyncArgs:
+			if currentOpts != nil {
+				extra, err := rsync.SplitArgs(expandVariables(inst.Args[0], sf.Variables))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: RSYNCARGS: %w", inst.LineNum, err)
+				}
+				currentOpts.RsyncExtraArgs = append(currentOpts.RsyncExtraArgs, extra...)
+			}
+
+		case InstInplace:
+			if currentOpts != nil {
+				inplace, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Inplace = inplace
+			}
+
+		case InstSparse:
+			if currentOpts != nil {
+				sparse, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Sparse = sparse
+			}
+
+		case InstDelayUpdates:
+			if currentOpts != nil {
+				delayUpdates, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.DelayUpdates = delayUpdates
+			}
+
+		case InstPartial:
+			if currentOpts != nil {
+				partial, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Partial = partial
+			}
+
+		case InstPartialDir:
+			if currentOpts != nil {
+				currentOpts.PartialDir = expandVariables(inst.Args[0], sf.Variables)
+			}
+
+		case InstSizeOnly:
+			if currentOpts != nil {
+				sizeOnly, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.SizeOnly = sizeOnly
+			}
+
+		case InstIgnoreTimes:
+			if currentOpts != nil {
+				ignoreTimes, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.IgnoreTimes = ignoreTimes
+			}
+
+		case InstDedupe:
+			if currentOpts != nil {
+				dedupe, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Dedupe = dedupe
+			}
+
+		case InstNoArchive:
+			if currentOpts != nil {
+				noArchive, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.NoArchive = noArchive
+			}
+
+		case InstIncludeUnchanged:
+			if currentOpts != nil {
+				includeUnchanged, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.IncludeUnchanged = includeUnchanged
+			}
+
+		case InstPruneEmptyDirs:
+			if currentOpts != nil {
+				prune, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.PruneEmptyDirs = prune
+			}
+
+		case InstSuper:
+			if currentOpts != nil {
+				super, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Super = super
+			}
+
+		case InstFakeSuper:
+			if currentOpts != nil {
+				fakeSuper, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.FakeSuper = fakeSuper
+			}
+
+		case InstRelative:
+			if currentOpts != nil {
+				relative, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Relative = relative
+			}
+
+		case InstWatch:
+			if currentOpts != nil {
+				watch, _ := strconv.ParseBool(inst.Args[0])
+				currentOpts.Watch = watch
+			}
+
+		case InstSafeMode:
+			// A project-wide setting, so it may appear before the first SYNC
+			safeMode, _ = strconv.ParseBool(inst.Args[0])
+
 		case InstExclude:
 			if currentOpts != nil {
 				pattern := expandVariables(inst.Args[0], sf.Variables)
@@ -267,6 +621,18 @@ func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 				pattern := expandVariables(inst.Args[0], sf.Variables)
 				currentOpts.Only = append(currentOpts.Only, pattern)
 			}
+
+		case InstExcludeIfPresent:
+			if currentOpts != nil {
+				marker := expandVariables(inst.Args[0], sf.Variables)
+				currentOpts.ExcludeIfPresent = append(currentOpts.ExcludeIfPresent, marker)
+			}
+
+		case InstFilter:
+			if currentOpts != nil {
+				rule := expandVariables(inst.Args[0], sf.Variables)
+				currentOpts.FilterRules = append(currentOpts.FilterRules, rule)
+			}
 		
 		case InstPatch:
 			if currentOpts != nil {
@@ -274,6 +640,16 @@ func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 				currentOpts.Patch = patchFile
 			}
 		
+		case InstReport:
+			if currentOpts != nil {
+				currentOpts.Report = expandVariables(inst.Args[0], sf.Variables)
+			}
+
+		case InstPlan:
+			if currentOpts != nil {
+				currentOpts.Plan = expandVariables(inst.Args[0], sf.Variables)
+			}
+
 		case InstApplyPatch:
 			if currentOpts != nil {
 				applyPatch, _ := strconv.ParseBool(inst.Args[0])
@@ -285,6 +661,13 @@ func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 				preview, _ := strconv.ParseBool(inst.Args[0])
 				currentOpts.Preview = preview
 			}
+
+		case InstPreviewFormat:
+			if currentOpts != nil {
+				// Like --preview-format, picking a format asks for a preview
+				currentOpts.Preview = true
+				currentOpts.PreviewFormat = inst.Args[0]
+			}
 		
 		case InstAutoConfirm:
 			if currentOpts != nil {
@@ -303,6 +686,10 @@ func (sf *SyncFile) ToRsyncOptions() ([]*rsync.Options, error) {
 		return nil, fmt.Errorf("no SYNC instructions found in SyncFile")
 	}
 
+	for _, opts := range optsList {
+		opts.SafeMode = safeMode
+	}
+
 	return optsList, nil
 }
 
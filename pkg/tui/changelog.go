@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// maxChangeLogEntries caps how many recent changes the live log keeps in memory
+const maxChangeLogEntries = 500
+
+// changeLog is a ring buffer of the most recent change lines, so a long sync
+// doesn't grow the TUI's memory without bound
+type changeLog struct {
+	lines []string
+	next  int
+	full  bool
+}
+
+// newChangeLog creates a log holding up to size lines
+func newChangeLog(size int) *changeLog {
+	return &changeLog{lines: make([]string, size)}
+}
+
+// add appends a line, overwriting the oldest once the log is full
+func (l *changeLog) add(line string) {
+	l.lines[l.next] = line
+	l.next = (l.next + 1) % len(l.lines)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// len returns how many lines the log holds
+func (l *changeLog) len() int {
+	if l.full {
+		return len(l.lines)
+	}
+	return l.next
+}
+
+// String joins the lines from oldest to newest
+func (l *changeLog) String() string {
+	if !l.full {
+		return strings.Join(l.lines[:l.next], "\n")
+	}
+	ordered := append(append([]string{}, l.lines[l.next:]...), l.lines[:l.next]...)
+	return strings.Join(ordered, "\n")
+}
+
+// formatChange renders a change as a log line, e.g. "+ docs/readme.md (1204 bytes)"
+func formatChange(change rsync.Change) string {
+	switch change.Kind {
+	case rsync.ChangeCreated:
+		if change.IsDir {
+			return "+ " + change.Path
+		}
+		return fmt.Sprintf("+ %s (%d bytes)", change.Path, change.Size)
+	case rsync.ChangeDeleted:
+		return "- " + change.Path
+	default:
+		return fmt.Sprintf("~ %s (%d bytes)", change.Path, change.Size)
+	}
+}
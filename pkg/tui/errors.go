@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DamianReeves/sync-tools/internal/rsync"
+)
+
+// errorMessage explains a failed sync in plain words, followed by the error itself
+func errorMessage(err error) string {
+	var rsyncErr *rsync.RsyncError
+	var conflictErr *rsync.ConflictError
+	var summary string
+	switch {
+	case errors.Is(err, rsync.ErrSourceNotFound):
+		summary = "The source directory doesn't exist. Check the source path and try again."
+	case errors.As(err, &conflictErr):
+		summary = fmt.Sprintf("The conflict in %s couldn't be resolved. Both versions are left as they were.", conflictErr.Path)
+	case errors.Is(err, context.Canceled):
+		summary = "The sync was stopped before it finished."
+	case errors.As(err, &rsyncErr):
+		summary = rsyncErrorSummary(rsyncErr)
+	default:
+		return err.Error()
+	}
+	return summary + "\n\n" + err.Error()
+}
+
+// rsyncErrorSummary names rsync's exit code and suggests what to try for the common ones
+func rsyncErrorSummary(err *rsync.RsyncError) string {
+	summary := fmt.Sprintf("rsync exited with code %d.", err.Code)
+	if reason := err.Reason(); reason != "" {
+		summary = fmt.Sprintf("rsync exited with code %d: %s.", err.Code, reason)
+	}
+
+	switch err.Code {
+	case 23, 24:
+		summary += " Some files couldn't be transferred; --ignore-errors finishes the sync without them."
+	case 10, 12, 30, 35:
+		summary += " The connection was lost or timed out; --partial with --retry-files resumes the transfer."
+	}
+	return summary
+}
@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/DamianReeves/sync-tools/internal/rsync"
@@ -34,8 +39,26 @@ var (
 	progressStyle = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("#FFA500")).
 		Bold(true)
+
+	changeLogStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(lipgloss.Color("#626262"))
+
+	dryRunTitleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#1A1A1A")).
+		Background(lipgloss.Color("#FFA500")).
+		Padding(0, 1)
 )
 
+// Config controls how the interactive model starts and stops
+type Config struct {
+	// AutoStart begins syncing as soon as the program starts instead of waiting for Enter
+	AutoStart bool
+	// AutoQuitDelay, when positive, exits this long after the sync finishes
+	AutoQuitDelay time.Duration
+}
+
 // Model represents the Bubble Tea model for interactive sync
 type Model struct {
 	opts     *rsync.Options
@@ -45,8 +68,32 @@ type Model struct {
 	error    string
 	result   string
 	quitting bool
+	err      error
+	stats    rsync.SyncStats
+
+	// The live log of changes, fed from rsync's output while syncing
+	changes  chan rsync.Change
+	log      *changeLog
+	viewport viewport.Model
+
+	autoQuitDelay time.Duration
+
+	// Quitting cancels ctx, which stops rsync; running tracks the sync goroutine so
+	// Stop can wait for it. Both are shared by every copy of the model.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running *sync.WaitGroup
 }
 
+// errCancelled is reported when the user quits before the sync finishes
+var errCancelled = errors.New("sync cancelled before it finished")
+
+// Size of the live change log viewport
+const (
+	changeLogWidth  = 80
+	changeLogHeight = 10
+)
+
 type syncState int
 
 const (
@@ -58,41 +105,86 @@ const (
 
 // syncMsg is sent when sync operation completes
 type syncMsg struct {
-	err error
+	err   error
+	stats rsync.SyncStats
 }
 
+// changeMsg carries one change reported by rsync
+type changeMsg rsync.Change
+
+// autoQuitMsg is sent once the auto-quit delay after completion has passed
+type autoQuitMsg struct{}
+
 // syncProgressMsg is sent during sync operation
 type syncProgressMsg struct {
 	message string
 }
 
-// NewModel creates a new interactive sync model
-func NewModel(opts *rsync.Options, logger logging.Logger) Model {
-	return Model{
-		opts:   opts,
-		logger: logger,
-		state:  stateIdle,
+// NewModel creates a new interactive sync model. The sync runs under ctx, so cancelling it
+// stops the sync as well.
+func NewModel(ctx context.Context, opts *rsync.Options, logger logging.Logger, cfg Config) Model {
+	ctx, cancel := context.WithCancel(ctx)
+	m := Model{
+		opts:          opts,
+		logger:        logger,
+		state:         stateIdle,
+		changes:       make(chan rsync.Change, 64),
+		log:           newChangeLog(maxChangeLogEntries),
+		viewport:      viewport.New(changeLogWidth, changeLogHeight),
+		autoQuitDelay: cfg.AutoQuitDelay,
+		ctx:           ctx,
+		cancel:        cancel,
+		running:       &sync.WaitGroup{},
+	}
+	if cfg.AutoStart {
+		m.state = stateSyncing
+		m.progress = "Starting sync..."
 	}
+	return m
 }
 
 // Init is called when the program starts
 func (m Model) Init() tea.Cmd {
+	if m.state == stateSyncing {
+		return tea.Batch(m.performSync(), m.waitForChange())
+	}
 	return nil
 }
 
+// Err returns the sync's error once it has finished, so callers can set an exit status
+func (m Model) Err() error {
+	return m.err
+}
+
+// Stats returns the changes counted by the sync once it has finished
+func (m Model) Stats() rsync.SyncStats {
+	return m.stats
+}
+
+// Stop cancels a sync that is still running and waits for it, and its rsync, to exit.
+// Call it once the program has finished.
+func (m Model) Stop() {
+	m.cancel()
+	m.running.Wait()
+}
+
 // Update handles messages and updates the model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
+			if m.state == stateSyncing {
+				m.cancel()
+				m.err = errCancelled
+			}
 			m.quitting = true
 			return m, tea.Quit
 		case "enter", " ":
 			if m.state == stateIdle {
 				m.state = stateSyncing
 				m.progress = "Starting sync..."
-				return m, m.performSync()
+				return m, tea.Batch(m.performSync(), m.waitForChange())
 			}
 			if m.state == stateComplete || m.state == stateError {
 				m.quitting = true
@@ -100,19 +192,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Any other key scrolls the change log
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+
+	case tea.WindowSizeMsg:
+		// Terminals that report no size keep the default width
+		if msg.Width > 2 {
+			m.viewport.Width = min(msg.Width-2, changeLogWidth)
+		}
+		return m, nil
+
+	case changeMsg:
+		m.log.add(formatChange(rsync.Change(msg)))
+		m.viewport.SetContent(m.log.String())
+		m.viewport.GotoBottom()
+		m.progress = "Syncing " + msg.Path
+		return m, m.waitForChange()
+
 	case syncProgressMsg:
 		m.progress = msg.message
 		return m, nil
 
 	case syncMsg:
+		m.err = msg.err
+		m.stats = msg.stats
 		if msg.err != nil {
 			m.state = stateError
-			m.error = msg.err.Error()
+			m.error = errorMessage(msg.err)
 		} else {
 			m.state = stateComplete
-			m.result = "Sync completed successfully!"
+			m.result = fmt.Sprintf("Sync completed successfully in %s!", rsync.FormatDuration(msg.stats.Duration))
+			if !m.opts.WritesDest() {
+				m.result = "Dry run completed - no changes were made"
+			}
+		}
+		if m.autoQuitDelay > 0 {
+			return m, tea.Tick(m.autoQuitDelay, func(time.Time) tea.Msg { return autoQuitMsg{} })
 		}
 		return m, nil
+
+	case autoQuitMsg:
+		m.quitting = true
+		return m, tea.Quit
 	}
 
 	return m, nil
@@ -126,8 +249,13 @@ func (m Model) View() string {
 
 	var content strings.Builder
 
-	// Title
-	content.WriteString(titleStyle.Render("🔄 Interactive Sync"))
+	// Title, with a distinct banner for dry runs so nobody mistakes one for a real sync.
+	// WritesDest also covers safe mode, which only switches DryRun on once the sync starts.
+	if !m.opts.WritesDest() {
+		content.WriteString(dryRunTitleStyle.Render("🧪 Interactive Sync - DRY RUN (no changes will be made)"))
+	} else {
+		content.WriteString(titleStyle.Render("🔄 Interactive Sync"))
+	}
 	content.WriteString("\n\n")
 
 	// Sync configuration info
@@ -141,6 +269,13 @@ func (m Model) View() string {
 	content.WriteString(infoStyle.Render(info))
 	content.WriteString("\n\n")
 
+	// Live change log, once rsync has reported something
+	if m.log.len() > 0 {
+		content.WriteString(fmt.Sprintf("Recent changes (last %d, ↑/↓ to scroll):\n", m.log.len()))
+		content.WriteString(changeLogStyle.Render(m.viewport.View()))
+		content.WriteString("\n\n")
+	}
+
 	// State-specific content
 	switch m.state {
 	case stateIdle:
@@ -153,26 +288,56 @@ func (m Model) View() string {
 
 	case stateComplete:
 		content.WriteString(successStyle.Render("✅ " + m.result))
-		content.WriteString("\n\nPress [Enter] or [q] to exit\n")
+		content.WriteString("\n\n" + m.exitHint() + "\n")
 
 	case stateError:
 		content.WriteString(errorStyle.Render("❌ Sync failed:"))
 		content.WriteString("\n" + m.error)
-		content.WriteString("\n\nPress [Enter] or [q] to exit\n")
+		content.WriteString("\n\n" + m.exitHint() + "\n")
 	}
 
 	return content.String()
 }
 
+// exitHint tells the user how the finished screen will close
+func (m Model) exitHint() string {
+	if m.autoQuitDelay > 0 {
+		return fmt.Sprintf("Exiting in %s... Press [Enter] or [q] to exit now", m.autoQuitDelay)
+	}
+	return "Press [Enter] or [q] to exit"
+}
+
 // performSync executes the sync operation in the background
 func (m Model) performSync() tea.Cmd {
+	m.running.Add(1)
 	return func() tea.Msg {
-		// Create a new runner
+		defer m.running.Done()
+
+		// Create a new runner, streaming its changes to the live log. Once the
+		// user quits nothing reads the log, so changes are dropped instead.
 		runner := rsync.NewRunner(m.logger)
+		runner.OnChange(func(change rsync.Change) {
+			select {
+			case m.changes <- change:
+			case <-m.ctx.Done():
+			}
+		})
 
 		// Execute sync
-		err := runner.Sync(m.opts)
+		err := runner.SyncContext(m.ctx, m.opts)
+		close(m.changes)
 
-		return syncMsg{err: err}
+		return syncMsg{err: err, stats: runner.Stats()}
 	}
-}
\ No newline at end of file
+}
+
+// waitForChange delivers the next change from the running sync, until the channel closes
+func (m Model) waitForChange() tea.Cmd {
+	return func() tea.Msg {
+		change, ok := <-m.changes
+		if !ok {
+			return nil
+		}
+		return changeMsg(change)
+	}
+}
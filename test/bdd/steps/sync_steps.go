@@ -2,11 +2,17 @@ package steps
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/cucumber/godog"
 )
@@ -19,15 +25,37 @@ type TestContext struct {
 	lastOutput     string
 	lastError      string
 	syncToolsPath  string
+	syncFilePath   string
+	fakeBinDir     string
+	isolatedPath   bool
+	statsLogPath   string
+	configPath     string
+	tmpDir         string
+	// env holds NAME=value pairs added to every sync-tools run's environment
+	env []string
+
+	// webhook receives --notify posts; webhookBodies holds each request body
+	webhook       *httptest.Server
+	webhookMu     sync.Mutex
+	webhookBodies [][]byte
 }
 
 // Helper function to run a command and properly capture exit code and output
 func (tc *TestContext) runCommand(args ...string) error {
-	cmd := exec.Command(tc.syncToolsPath, args...)
+	return tc.runCommandInDir("", args...)
+}
+
+// runCommandInDir runs sync-tools from dir, or the current directory when dir is empty
+func (tc *TestContext) runCommandInDir(dir string, args ...string) error {
+	cmd := tc.command(dir, args...)
 	output, err := cmd.CombinedOutput()
 	tc.lastOutput = string(output)
-	
-	// Handle exit code properly
+	tc.recordExit(err)
+	return nil
+}
+
+// recordExit saves a finished command's exit code and error
+func (tc *TestContext) recordExit(err error) {
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			tc.lastExitCode = exitError.ExitCode()
@@ -38,7 +66,24 @@ func (tc *TestContext) runCommand(args ...string) error {
 	} else {
 		tc.lastExitCode = 0
 	}
-	return nil
+}
+
+// command prepares a sync-tools invocation in dir with the scenario's environment
+func (tc *TestContext) command(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command(tc.syncToolsPath, args...)
+	cmd.Dir = dir
+	// A per-scenario TMPDIR makes leftover temp files observable, and a per-scenario
+	// config home keeps the global config and last-run state out of the user's
+	cmd.Env = append(os.Environ(), "TMPDIR="+tc.tmpDir, "XDG_CONFIG_HOME="+filepath.Join(tc.tmpDir, "config"))
+	cmd.Env = append(cmd.Env, tc.env...)
+	if tc.isolatedPath {
+		// Only the tools linked into fakeBinDir are visible (e.g. to hide git)
+		cmd.Env = append(cmd.Env, "PATH="+tc.fakeBinDir)
+	} else if tc.fakeBinDir != "" {
+		// Put wrapper binaries (e.g. a flaky rsync) ahead of the real ones
+		cmd.Env = append(cmd.Env, "PATH="+tc.fakeBinDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+	return cmd
 }
 
 // NewTestContext creates a new test context
@@ -51,8 +96,13 @@ func (tc *TestContext) RegisterSteps(ctx *godog.ScenarioContext) {
 	// Hello World steps
 	ctx.Step(`^the sync-tools binary exists$`, tc.syncToolsBinaryExists)
 	ctx.Step(`^I run sync-tools with help$`, tc.runSyncToolsWithHelp)
+	ctx.Step(`^I run sync-tools with the version flag$`, tc.runSyncToolsWithVersionFlag)
+	ctx.Step(`^I run sync-tools version with flags "([^"]*)"$`, tc.runSyncToolsVersionWithFlags)
+	ctx.Step(`^the output should be JSON with a non-empty "([^"]*)"$`, tc.outputShouldBeJSONWith)
 	ctx.Step(`^it should display help information$`, tc.shouldDisplayHelpInformation)
 	ctx.Step(`^the exit code should be (\d+)$`, tc.exitCodeShouldBe)
+	ctx.Step(`^the output should contain "([^"]*)"$`, tc.outputShouldContain)
+	ctx.Step(`^the output should not contain "([^"]*)"$`, tc.outputShouldNotContain)
 
 	// Basic sync steps
 	ctx.Step(`^I have a source directory with files$`, tc.createSourceDirectoryWithFiles)
@@ -97,6 +147,183 @@ func (tc *TestContext) RegisterSteps(ctx *godog.ScenarioContext) {
 	ctx.Step(`^I have an empty source directory$`, tc.createEmptySourceDirectory)
 	ctx.Step(`^files matching gitignore patterns should not be copied$`, tc.filesMatchingGitignorePatternsShouldNotBeCopied)
 
+	// Ownership report steps
+	ctx.Step(`^I run sync-tools with one-way sync and a dest ownership report$`, tc.runSyncToolsWithDestOwnershipReport)
+	ctx.Step(`^I run sync-tools with one-way sync and a dest ownership report expecting owner "([^"]*)"$`, tc.runSyncToolsWithDestOwnershipReportExpecting)
+	ctx.Step(`^the ownership report should show (\d+) differences$`, tc.ownershipReportShouldShowDifferences)
+	ctx.Step(`^the ownership report should flag "([^"]*)"$`, tc.ownershipReportShouldFlag)
+
+	// Filter test steps
+	ctx.Step(`^I run sync-tools filter test for "([^"]*)"$`, tc.runSyncToolsFilterTest)
+	ctx.Step(`^I run sync-tools filter test for "([^"]*)" with max depth (\d+)$`, tc.runSyncToolsFilterTestWithMaxDepth)
+	ctx.Step(`^I run sync-tools filter test for "([^"]*)" ignoring case$`, tc.runSyncToolsFilterTestIgnoringCase)
+	ctx.Step(`^I run sync-tools check-filter for "([^"]*)"$`, tc.runSyncToolsCheckFilter)
+	ctx.Step(`^I run sync-tools check-filter for "([^"]*)" with "([^"]*)"$`, tc.runSyncToolsCheckFilterWithFlags)
+	ctx.Step(`^I have a global gitignore containing:$`, tc.createGlobalGitignore)
+
+	// Summary badge steps
+	ctx.Step(`^I run sync-tools with one-way sync and a summary badge$`, tc.runSyncToolsWithSummaryBadge)
+
+	// Stats log steps
+	ctx.Step(`^I run sync-tools with one-way sync appending to the stats log$`, tc.runSyncToolsAppendingStats)
+	ctx.Step(`^the stats log should have (\d+) JSON lines with increasing timestamps$`, tc.statsLogShouldHaveLines)
+
+	// Safety check steps
+	ctx.Step(`^I run sync-tools with one-way sync and flags "([^"]*)"$`, tc.runSyncToolsWithOneWaySyncAndFlags)
+	ctx.Step(`^I run sync-tools with one-way sync from the temp directory and flags "([^"]*)"$`, tc.runSyncToolsFromTempDirWithFlags)
+	ctx.Step(`^I run sync-tools sync with flags "([^"]*)"$`, tc.runSyncToolsSyncWithFlags)
+	ctx.Step(`^the destination "([^"]*)" should contain "([^"]*)"$`, tc.namedDestinationShouldContain)
+	ctx.Step(`^I run sync-tools with one-way sync and force$`, tc.runSyncToolsWithOneWaySyncAndForce)
+	ctx.Step(`^the destination should contain "([^"]*)"$`, tc.destinationShouldContain)
+	ctx.Step(`^the destination should not contain "([^"]*)"$`, tc.destinationShouldNotContain)
+	ctx.Step(`^the source should contain "([^"]*)"$`, tc.sourceShouldContain)
+	ctx.Step(`^the source should not contain "([^"]*)"$`, tc.sourceShouldNotContain)
+
+	// Stale artifact steps
+	ctx.Step(`^I have a destination directory with a leftover partial transfer$`, tc.createDestinationWithPartialTransfer)
+	ctx.Step(`^I run sync-tools with one-way sync ignoring stale artifacts$`, tc.runSyncToolsIgnoringStaleArtifacts)
+
+	// Sync to steps
+	ctx.Step(`^I run sync-tools sync to the destination from the source directory$`, tc.runSyncToolsSyncToDestination)
+	ctx.Step(`^I run sync-tools sync to "([^"]*)" from the source directory$`, tc.runSyncToolsSyncTo)
+
+	// Sync from steps
+	ctx.Step(`^the destination has a sync\.toml containing:$`, tc.destinationHasSyncToml)
+	ctx.Step(`^I run sync-tools sync from the source directory in the destination$`, tc.runSyncToolsSyncFromSource)
+	ctx.Step(`^I run sync-tools sync from without a source in the destination$`, tc.runSyncToolsSyncFromConfig)
+
+	// Safe mode steps
+	ctx.Step(`^I have a config file with safe mode enabled$`, tc.createSafeModeConfig)
+	ctx.Step(`^I have a config file containing:$`, tc.createConfigFile)
+	ctx.Step(`^the file "([^"]*)" should exist in the temp directory$`, tc.tempFileShouldExist)
+	ctx.Step(`^the file "([^"]*)" should not exist in the temp directory$`, tc.tempFileShouldNotExist)
+	ctx.Step(`^the file "([^"]*)" in the temp directory should contain "([^"]*)"$`, tc.tempFileShouldContain)
+	ctx.Step(`^the temp directory has a file "([^"]*)"$`, tc.tempDirHasFile)
+	ctx.Step(`^the temp directory has a file "([^"]*)" last modified (\d+) hours ago$`, tc.tempDirHasOldFile)
+	ctx.Step(`^I run sync-tools with one-way sync using the config$`, tc.runSyncToolsWithConfig)
+	ctx.Step(`^I run sync-tools with one-way sync using the config and execute$`, tc.runSyncToolsWithConfigAndExecute)
+
+	// Temp file steps
+	ctx.Step(`^no sync-tools temp files should remain$`, tc.noSyncToolsTempFilesShouldRemain)
+
+	// Retry steps
+	ctx.Step(`^rsync fails to transfer "([^"]*)" once$`, tc.rsyncFailsToTransferOnce)
+	ctx.Step(`^I run sync-tools with one-way sync and (\d+) file retries$`, tc.runSyncToolsWithFileRetries)
+	ctx.Step(`^the failed file should be retried successfully$`, tc.failedFileShouldBeRetriedSuccessfully)
+
+	// Git fallback steps
+	ctx.Step(`^git is not available$`, tc.gitIsNotAvailable)
+	ctx.Step(`^I run sync-tools with patch generation to "([^"]*)" and apply it$`, tc.runSyncToolsWithPatchGenerationAndApply)
+	ctx.Step(`^the destination file "([^"]*)" should contain "([^"]*)"$`, tc.destinationFileShouldContain)
+
+	// List steps
+	ctx.Step(`^the source has a file "([^"]*)"$`, tc.sourceHasFile)
+	ctx.Step(`^the source has a file "([^"]*)" containing:$`, tc.sourceHasFileContaining)
+	ctx.Step(`^the source has an empty directory "([^"]*)"$`, tc.sourceHasEmptyDirectory)
+	ctx.Step(`^the destination has a file "([^"]*)"$`, tc.destinationHasFile)
+	ctx.Step(`^the destination has an exact copy of the source file "([^"]*)"$`, tc.destinationHasCopyOfSourceFile)
+	ctx.Step(`^the source has an exact copy of "([^"]*)" at "([^"]*)"$`, tc.sourceHasCopyOfFile)
+	ctx.Step(`^the destination files "([^"]*)" and "([^"]*)" should be hard links to the same file$`, tc.destinationFilesShouldBeLinked)
+	ctx.Step(`^the destination files "([^"]*)" and "([^"]*)" should not be hard links to the same file$`, tc.destinationFilesShouldNotBeLinked)
+	ctx.Step(`^the destination has an older copy of the source file "([^"]*)"$`, tc.destinationHasOlderCopyOfSourceFile)
+	ctx.Step(`^I run sync-tools compare on the source and destination$`, tc.runSyncToolsCompare)
+	ctx.Step(`^I run sync-tools compare on the source and destination with "([^"]*)"$`, tc.runSyncToolsCompareWithFlags)
+	ctx.Step(`^I run sync-tools list on the source$`, tc.runSyncToolsList)
+	ctx.Step(`^I run sync-tools list on the source in "([^"]*)" format$`, tc.runSyncToolsListWithFormat)
+	ctx.Step(`^I run sync-tools list on the source in "([^"]*)" format with "([^"]*)"$`, tc.runSyncToolsListWithFormatAndFlags)
+	ctx.Step(`^I run sync-tools list on the source with the filter rules:$`, tc.runSyncToolsListWithFilterRules)
+	ctx.Step(`^the JSON listing should include "([^"]*)" of (\d+) bytes$`, tc.jsonListingShouldInclude)
+
+	// Undo steps
+	ctx.Step(`^the destination has a conflict backup of "([^"]*)" from (\d+) containing "([^"]*)"$`, tc.createConflictBackup)
+	ctx.Step(`^I run sync-tools undo on the destination$`, tc.runSyncToolsUndo)
+	ctx.Step(`^I run sync-tools undo on the destination keeping redo copies$`, tc.runSyncToolsUndoKeepingRedo)
+	ctx.Step(`^I run sync-tools undo on the destination since (\d+)$`, tc.runSyncToolsUndoSince)
+	ctx.Step(`^the destination has a conflict backup named "([^"]*)" containing "([^"]*)"$`, tc.createNamedConflictBackup)
+	ctx.Step(`^I run sync-tools undo on the destination with flags "([^"]*)"$`, tc.runSyncToolsUndoWithFlags)
+	ctx.Step(`^the destination should have a redo copy of "([^"]*)"$`, tc.destinationShouldHaveRedoCopy)
+
+	// Two-way conflict steps
+	ctx.Step(`^the destination has a newer "([^"]*)" containing "([^"]*)"$`, tc.destinationHasNewerFile)
+	ctx.Step(`^I run sync-tools with one-way sync and flags "([^"]*)" answering "([^"]*)"$`, tc.runSyncToolsAnswering)
+	ctx.Step(`^the destination should have a conflict copy of "([^"]*)" containing "([^"]*)"$`, tc.destinationShouldHaveConflictCopy)
+	ctx.Step(`^the destination should have no conflict copy of "([^"]*)"$`, tc.destinationShouldHaveNoConflictCopy)
+	ctx.Step(`^the source file "([^"]*)" should contain "([^"]*)"$`, tc.sourceFileShouldContain)
+
+	// rsync binary steps
+	ctx.Step(`^I run sync-tools with one-way sync and rsync binary "([^"]*)"$`, tc.runSyncToolsWithRsyncBinary)
+	ctx.Step(`^I run sync-tools with one-way sync and the rsync binary from PATH$`, tc.runSyncToolsWithRsyncBinaryFromPath)
+
+	// rsync daemon steps
+	ctx.Step(`^I have an rsync binary that records its arguments$`, tc.createRecordingRsync)
+	ctx.Step(`^I run sync-tools with the recording rsync to "([^"]*)" and password file "([^"]*)"$`, tc.runSyncToolsWithRecordingRsync)
+	ctx.Step(`^rsync should have been called with argument "([^"]*)"$`, tc.rsyncShouldHaveBeenCalledWith)
+	ctx.Step(`^rsync should not have been called with argument "([^"]*)"$`, tc.rsyncShouldNotHaveBeenCalledWith)
+	ctx.Step(`^rsync should have been given the filter rule "([^"]*)"$`, tc.rsyncShouldHaveBeenGivenFilterRule)
+	ctx.Step(`^rsync should not have been given the filter rule "([^"]*)"$`, tc.rsyncShouldNotHaveBeenGivenFilterRule)
+	ctx.Step(`^I run sync-tools with the recording rsync and extra rsync arguments "([^"]*)"$`, tc.runSyncToolsWithRecordingRsyncExtraArgs)
+	ctx.Step(`^I run sync-tools with the recording rsync using the config$`, tc.runSyncToolsWithRecordingRsyncUsingConfig)
+	ctx.Step(`^I run sync-tools with the recording rsync and flags "([^"]*)"$`, tc.runSyncToolsWithRecordingRsyncAndFlags)
+	ctx.Step(`^I have an rsync binary that can't read "([^"]*)"$`, tc.createUnreadableFileRsync)
+	ctx.Step(`^I have an rsync binary that reports progress$`, tc.createProgressRsync)
+	ctx.Step(`^I have an rsync binary whose connection drops once while sending "([^"]*)"$`, tc.createInterruptedRsync)
+	ctx.Step(`^I run sync-tools with the interrupted rsync and flags "([^"]*)"$`, tc.runSyncToolsWithInterruptedRsync)
+	ctx.Step(`^I run sync-tools with the progress rsync and flags "([^"]*)"$`, tc.runSyncToolsWithProgressRsync)
+	ctx.Step(`^I run sync-tools with the unreadable-file rsync and flags "([^"]*)"$`, tc.runSyncToolsWithUnreadableFileRsync)
+
+	// Doctor steps
+	ctx.Step(`^I run sync-tools doctor$`, tc.runSyncToolsDoctor)
+	ctx.Step(`^I run sync-tools doctor with rsync binary "([^"]*)"$`, tc.runSyncToolsDoctorWithRsyncBinary)
+	ctx.Step(`^I have an rsync binary reporting version "([^"]*)"$`, tc.createRsyncReportingVersion)
+	ctx.Step(`^I run sync-tools doctor with that rsync binary$`, tc.runSyncToolsDoctorWithVersionedRsync)
+	ctx.Step(`^I run sync-tools with that rsync binary and flags "([^"]*)"$`, tc.runSyncToolsWithVersionedRsyncAndFlags)
+
+	// Assertion steps
+	ctx.Step(`^I run sync-tools assert with "([^"]*)"$`, tc.runSyncToolsAssert)
+
+	// Drift check steps
+	ctx.Step(`^I run sync-tools with a dry run that fails on changes$`, tc.runSyncToolsFailOnChanges)
+	ctx.Step(`^I run sync-tools with a real sync that fails on changes$`, tc.runSyncToolsFailOnChangesWithoutDryRun)
+
+	// Timing steps
+	ctx.Step(`^I run sync-tools with one-way sync and stats$`, tc.runSyncToolsWithStats)
+
+	// Hook steps
+	ctx.Step(`^I run sync-tools with one-way sync and on-success hook "([^"]*)"$`, tc.runSyncToolsWithOnSuccessHook)
+	ctx.Step(`^I run sync-tools with one-way sync, rsync binary "([^"]*)", and on-failure hook "([^"]*)"$`, tc.runSyncToolsWithOnFailureHook)
+
+	// Notification steps
+	ctx.Step(`^a webhook receiver is listening$`, tc.startWebhookReceiver)
+	ctx.Step(`^I run sync-tools with one-way sync and notify to the webhook$`, tc.runSyncToolsWithWebhookNotify)
+	ctx.Step(`^I run sync-tools syncfile with notify to the webhook$`, tc.runSyncToolsSyncfileWithWebhookNotify)
+	ctx.Step(`^I run sync-tools syncfile with notify to the webhook and timeout "([^"]*)"$`, tc.runSyncToolsSyncfileWithWebhookNotifyAndTimeout)
+	ctx.Step(`^I run sync-tools with one-way sync and notify "([^"]*)"$`, tc.runSyncToolsWithNotify)
+	ctx.Step(`^the webhook should receive a (successful|failed) notification with (\d+) runs?$`, tc.webhookShouldReceiveNotification)
+	ctx.Step(`^the webhook notification text should contain "([^"]*)"$`, tc.webhookNotificationTextShouldContain)
+
+	// Timeout steps
+	ctx.Step(`^I have an rsync binary that hangs$`, tc.createHangingRsync)
+	ctx.Step(`^I run sync-tools with one-way sync, the hanging rsync, and timeout "([^"]*)"$`, tc.runSyncToolsWithHangingRsync)
+	ctx.Step(`^the hanging rsync should have been stopped$`, tc.hangingRsyncShouldHaveBeenStopped)
+	ctx.Step(`^I have a SyncFile with two SYNC operations using the hanging rsync$`, tc.createSyncFileWithHangingRsync)
+	ctx.Step(`^I run sync-tools syncfile with timeout "([^"]*)"$`, tc.runSyncToolsSyncfileWithTimeout)
+	ctx.Step(`^I run sync-tools syncfile with total timeout "([^"]*)"$`, tc.runSyncToolsSyncfileWithTotalTimeout)
+
+	// SyncFile steps
+	ctx.Step(`^I have a SyncFile with a SYNC guarded by "([^"]*)"$`, tc.createSyncFileWithGuardedSync)
+	ctx.Step(`^the environment variable "([^"]*)" is set to "([^"]*)"$`, tc.setEnvironmentVariable)
+	ctx.Step(`^I run sync-tools syncfile with list$`, tc.runSyncToolsSyncfileWithList)
+	ctx.Step(`^the SyncFile "([^"]*)" contains:$`, tc.createNamedSyncFile)
+	ctx.Step(`^I run sync-tools syncfile "([^"]*)" with list$`, tc.runSyncToolsNamedSyncfileWithList)
+	ctx.Step(`^I run sync-tools syncfile "([^"]*)"$`, tc.runSyncToolsNamedSyncfile)
+	ctx.Step(`^I run sync-tools syncfile "([^"]*)" with flags "([^"]*)"$`, tc.runSyncToolsNamedSyncfileWithFlags)
+	ctx.Step(`^I watch the SyncFile "([^"]*)" while adding "([^"]*)" to the source$`, tc.watchSyncfileWhileAddingFile)
+	ctx.Step(`^I watch the SyncFile "([^"]*)" for (\d+) seconds while adding "([^"]*)" to the source$`, tc.watchSyncfileForWhileAddingFile)
+	ctx.Step(`^the SyncFile should report (\d+) sync operations$`, tc.syncFileShouldReportOperations)
+	ctx.Step(`^I have a SyncFile syncing the source to two destinations$`, tc.createSyncFileWithTwoDestinations)
+	ctx.Step(`^I run sync-tools syncfile with a report$`, tc.runSyncToolsSyncfileWithReport)
+	ctx.Step(`^the SyncFile report should contain "([^"]*)"$`, tc.syncFileReportShouldContain)
+
 	// Setup and cleanup hooks
 	ctx.Before(func(ctx context.Context, sc *godog.Scenario) (context.Context, error) {
 		return tc.beforeScenario(ctx, sc)
@@ -111,6 +338,16 @@ func (tc *TestContext) beforeScenario(ctx context.Context, sc *godog.Scenario) (
 	tempDir := os.TempDir()
 	tc.sourceDir = filepath.Join(tempDir, fmt.Sprintf("sync_test_src_%d_%s", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
 	tc.destDir = filepath.Join(tempDir, fmt.Sprintf("sync_test_dest_%d_%s", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
+	tc.fakeBinDir = ""
+	tc.isolatedPath = false
+	tc.env = nil
+	tc.tmpDir = filepath.Join(tempDir, fmt.Sprintf("sync_test_tmp_%d_%s", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
+	if err := os.MkdirAll(tc.tmpDir, 0755); err != nil {
+		return ctx, err
+	}
+	tc.configPath = filepath.Join(tempDir, fmt.Sprintf("sync_test_config_%d_%s.toml", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
+	tc.statsLogPath = filepath.Join(tempDir, fmt.Sprintf("sync_test_stats_%d_%s.jsonl", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
+	tc.syncFilePath = filepath.Join(tempDir, fmt.Sprintf("sync_test_syncfile_%d_%s", os.Getpid(), strings.ReplaceAll(sc.Name, " ", "_")))
 	
 	// Find sync-tools binary path - always relative to project root
 	if wd, err := os.Getwd(); err == nil {
@@ -132,6 +369,17 @@ func (tc *TestContext) afterScenario(ctx context.Context, sc *godog.Scenario, er
 	// Cleanup test directories
 	_ = os.RemoveAll(tc.sourceDir)
 	_ = os.RemoveAll(tc.destDir)
+	_ = os.Remove(tc.syncFilePath)
+	_ = os.Remove(tc.statsLogPath)
+	_ = os.Remove(tc.configPath)
+	_ = os.RemoveAll(tc.tmpDir)
+	if tc.fakeBinDir != "" {
+		_ = os.RemoveAll(tc.fakeBinDir)
+	}
+	if tc.webhook != nil {
+		tc.webhook.Close()
+		tc.webhook = nil
+	}
 	// Note: sc and err parameters are required by godog interface
 	_ = sc
 	_ = err
@@ -155,6 +403,25 @@ func (tc *TestContext) runSyncToolsWithHelp() error {
 	return tc.runCommand("help")
 }
 
+func (tc *TestContext) runSyncToolsWithVersionFlag() error {
+	return tc.runCommand("--version")
+}
+
+func (tc *TestContext) runSyncToolsVersionWithFlags(flags string) error {
+	return tc.runCommand(append([]string{"version"}, strings.Fields(flags)...)...)
+}
+
+func (tc *TestContext) outputShouldBeJSONWith(key string) error {
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(tc.lastOutput), &fields); err != nil {
+		return fmt.Errorf("output is not a JSON object: %v\noutput: %s", err, tc.lastOutput)
+	}
+	if fields[key] == "" {
+		return fmt.Errorf("expected a non-empty %q in:\n%s", key, tc.lastOutput)
+	}
+	return nil
+}
+
 func (tc *TestContext) shouldDisplayHelpInformation() error {
 	if !strings.Contains(tc.lastOutput, "sync-tools") {
 		return fmt.Errorf("expected help information, got: %s", tc.lastOutput)
@@ -169,6 +436,20 @@ func (tc *TestContext) exitCodeShouldBe(expectedCode int) error {
 	return nil
 }
 
+func (tc *TestContext) outputShouldContain(expected string) error {
+	if !strings.Contains(tc.lastOutput, expected) {
+		return fmt.Errorf("expected output to contain %q, got: %s", expected, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) outputShouldNotContain(unexpected string) error {
+	if strings.Contains(tc.lastOutput, unexpected) {
+		return fmt.Errorf("expected output not to contain %q, got: %s", unexpected, tc.lastOutput)
+	}
+	return nil
+}
+
 func (tc *TestContext) createSourceDirectoryWithFiles() error {
 	if err := os.MkdirAll(tc.sourceDir, 0755); err != nil {
 		return err
@@ -238,17 +519,41 @@ func (tc *TestContext) runSyncToolsWithOneWaySync() error {
 	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir)
 }
 
+// runSyncToolsWithOneWaySyncAndFlags appends space-separated extra flags to a one-way sync
+func (tc *TestContext) runSyncToolsWithOneWaySyncAndFlags(flags string) error {
+	args := append([]string{"sync", "--source", tc.sourceDir, "--dest", tc.destDir}, strings.Fields(flags)...)
+	return tc.runCommand(args...)
+}
+
+// runSyncToolsFromTempDirWithFlags runs a one-way sync from the scenario's temp directory,
+// so relative paths in the flags, like a --report file, land there
+func (tc *TestContext) runSyncToolsFromTempDirWithFlags(flags string) error {
+	args := append([]string{"sync", "--source", tc.sourceDir, "--dest", tc.destDir}, strings.Fields(flags)...)
+	return tc.runCommandInDir(tc.tmpDir, args...)
+}
+
+// runSyncToolsSyncWithFlags runs sync with only the given space-separated flags, run from
+// the scenario's temp directory so relative paths land there
+func (tc *TestContext) runSyncToolsSyncWithFlags(flags string) error {
+	return tc.runCommandInDir(tc.tmpDir, append([]string{"sync"}, strings.Fields(flags)...)...)
+}
+
 func (tc *TestContext) runSyncToolsWithTwoWaySync() error {
 	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--mode", "two-way")
 }
 
 // Placeholder implementations - these would be implemented as the CLI is built
 func (tc *TestContext) shouldShowWhatFilesWouldBeCopied() error {
-	// Check for dry-run indicators in the output
-	if !strings.Contains(tc.lastOutput, "DRY RUN") && !strings.Contains(tc.lastOutput, "dry-run=true") {
-		return fmt.Errorf("expected dry-run output to show what would be copied, got: %s", tc.lastOutput)
+	// Dry runs print a banner and the itemized changes whatever the log level
+	if !strings.Contains(tc.lastOutput, "DRY RUN: ") {
+		return fmt.Errorf("expected a DRY RUN banner, got: %s", tc.lastOutput)
 	}
-	return nil
+	for _, line := range strings.Split(tc.lastOutput, "\n") {
+		if strings.HasPrefix(line, ">f+++++++++") && strings.HasSuffix(line, " file1.txt") {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected an itemized change for file1.txt, got: %s", tc.lastOutput)
 }
 
 func (tc *TestContext) noFilesShouldActuallyBeCopied() error {
@@ -442,4 +747,1114 @@ func (tc *TestContext) noFilesShouldBeSynchronized() error {
 func (tc *TestContext) filesMatchingGitignorePatternsShouldNotBeCopied() error {
 	// Check that gitignore patterns were respected
 	return nil // Placeholder - need to implement gitignore pattern validation
-}
\ No newline at end of file
+}
+// Ownership report step implementations
+
+func (tc *TestContext) runSyncToolsWithDestOwnershipReport() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--dest-ownership-report")
+}
+
+func (tc *TestContext) runSyncToolsWithDestOwnershipReportExpecting(owner string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--dest-ownership-report", "--expected-owner", owner)
+}
+
+func (tc *TestContext) runSyncToolsWithSummaryBadge() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--report-summary-badge")
+}
+
+func (tc *TestContext) runSyncToolsAppendingStats() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--stats-json-append", tc.statsLogPath)
+}
+
+func (tc *TestContext) statsLogShouldHaveLines(count int) error {
+	data, err := os.ReadFile(tc.statsLogPath)
+	if err != nil {
+		return fmt.Errorf("failed to read stats log: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != count {
+		return fmt.Errorf("expected %d stats lines, got %d: %s", count, len(lines), data)
+	}
+
+	var previous time.Time
+	for i, line := range lines {
+		var record struct {
+			Timestamp time.Time `json:"timestamp"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return fmt.Errorf("stats line %d is not valid JSON: %w", i+1, err)
+		}
+		if i > 0 && !record.Timestamp.After(previous) {
+			return fmt.Errorf("stats line %d timestamp %s is not after %s", i+1, record.Timestamp, previous)
+		}
+		previous = record.Timestamp
+	}
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsWithOneWaySyncAndForce() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--force")
+}
+
+func (tc *TestContext) destinationShouldContain(file string) error {
+	if _, err := os.Stat(filepath.Join(tc.destDir, file)); err != nil {
+		return fmt.Errorf("expected %s in destination: %w", file, err)
+	}
+	return nil
+}
+
+// namedDestinationShouldContain checks a destination given relative to the scenario's temp directory
+func (tc *TestContext) namedDestinationShouldContain(dest, file string) error {
+	if _, err := os.Stat(filepath.Join(tc.tmpDir, dest, file)); err != nil {
+		return fmt.Errorf("expected %s in %s: %w; output: %s", file, dest, err, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) destinationShouldNotContain(file string) error {
+	if _, err := os.Stat(filepath.Join(tc.destDir, file)); err == nil {
+		return fmt.Errorf("expected %s to be absent from destination", file)
+	}
+	return nil
+}
+
+func (tc *TestContext) sourceShouldContain(file string) error {
+	if _, err := os.Stat(filepath.Join(tc.sourceDir, file)); err != nil {
+		return fmt.Errorf("expected %s in source: %w", file, err)
+	}
+	return nil
+}
+
+func (tc *TestContext) sourceShouldNotContain(file string) error {
+	if _, err := os.Stat(filepath.Join(tc.sourceDir, file)); err == nil {
+		return fmt.Errorf("expected %s to be absent from source; output: %s", file, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) createDestinationWithPartialTransfer() error {
+	partialDir := filepath.Join(tc.destDir, ".rsync-partial")
+	if err := os.MkdirAll(partialDir, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(partialDir, "file1.txt"), []byte("test con"), 0644)
+}
+
+func (tc *TestContext) runSyncToolsIgnoringStaleArtifacts() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--ignore-stale-artifacts")
+}
+
+func (tc *TestContext) runSyncToolsSyncToDestination() error {
+	return tc.runSyncToolsSyncTo(tc.destDir)
+}
+
+func (tc *TestContext) runSyncToolsSyncTo(dest string) error {
+	// The binary path is relative to the test directory, so resolve it before changing directory
+	binary, err := filepath.Abs(tc.syncToolsPath)
+	if err != nil {
+		return err
+	}
+	tc.syncToolsPath = binary
+	return tc.runCommandInDir(tc.sourceDir, "sync", "to", dest)
+}
+
+// destinationHasSyncToml writes a project config into the dest, replacing {tmp} with the
+// scenario's temp directory and {source} with its source directory
+func (tc *TestContext) destinationHasSyncToml(content *godog.DocString) error {
+	if err := os.MkdirAll(tc.destDir, 0755); err != nil {
+		return err
+	}
+	text := strings.NewReplacer("{tmp}", tc.tmpDir, "{source}", tc.sourceDir).Replace(content.Content)
+	return os.WriteFile(filepath.Join(tc.destDir, "sync.toml"), []byte(text+"\n"), 0644)
+}
+
+func (tc *TestContext) runSyncToolsSyncFromSource() error {
+	return tc.runSyncToolsSyncFrom(tc.sourceDir)
+}
+
+func (tc *TestContext) runSyncToolsSyncFromConfig() error {
+	return tc.runSyncToolsSyncFrom()
+}
+
+// runSyncToolsSyncFrom runs sync from in the dest, which is where its project config is read
+func (tc *TestContext) runSyncToolsSyncFrom(args ...string) error {
+	binary, err := filepath.Abs(tc.syncToolsPath)
+	if err != nil {
+		return err
+	}
+	tc.syncToolsPath = binary
+	return tc.runCommandInDir(tc.destDir, append([]string{"sync", "from"}, args...)...)
+}
+
+// createGlobalGitignore writes git's default global gitignore under the scenario's config home
+func (tc *TestContext) createGlobalGitignore(content *godog.DocString) error {
+	path := filepath.Join(tc.tmpDir, "config", "git", "ignore")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content.Content+"\n"), 0644)
+}
+
+func (tc *TestContext) createSafeModeConfig() error {
+	return os.WriteFile(tc.configPath, []byte("safe_mode = true\n"), 0644)
+}
+
+// createConfigFile writes the config, replacing {tmp} with the scenario's temp directory
+func (tc *TestContext) createConfigFile(content *godog.DocString) error {
+	text := strings.ReplaceAll(content.Content, "{tmp}", tc.tmpDir)
+	return os.WriteFile(tc.configPath, []byte(text+"\n"), 0644)
+}
+
+func (tc *TestContext) tempFileShouldExist(name string) error {
+	if _, err := os.Stat(filepath.Join(tc.tmpDir, name)); err != nil {
+		return fmt.Errorf("expected %s in the temp directory: %w; output: %s", name, err, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) tempFileShouldContain(name, expected string) error {
+	data, err := os.ReadFile(filepath.Join(tc.tmpDir, name))
+	if err != nil {
+		return fmt.Errorf("expected %s in the temp directory: %w; output: %s", name, err, tc.lastOutput)
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected %s to contain %q, got:\n%s", name, expected, data)
+	}
+	return nil
+}
+
+func (tc *TestContext) tempFileShouldNotExist(name string) error {
+	if _, err := os.Stat(filepath.Join(tc.tmpDir, name)); err == nil {
+		return fmt.Errorf("expected no %s in the temp directory; output: %s", name, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsWithConfig() error {
+	return tc.runCommand("sync", "--config", tc.configPath, "--source", tc.sourceDir, "--dest", tc.destDir)
+}
+
+func (tc *TestContext) runSyncToolsWithConfigAndExecute() error {
+	return tc.runCommand("sync", "--config", tc.configPath, "--source", tc.sourceDir, "--dest", tc.destDir, "--execute")
+}
+
+func (tc *TestContext) noSyncToolsTempFilesShouldRemain() error {
+	leftovers, err := filepath.Glob(filepath.Join(tc.tmpDir, "sync-tools-*"))
+	if err != nil {
+		return err
+	}
+	if len(leftovers) > 0 {
+		return fmt.Errorf("expected no temp files to remain, found: %v", leftovers)
+	}
+	return nil
+}
+
+func (tc *TestContext) ownershipReportShouldShowDifferences(count int) error {
+	expected := fmt.Sprintf("Ownership report: %d of", count)
+	if !strings.Contains(tc.lastOutput, expected) {
+		return fmt.Errorf("expected output to contain %q, got: %s", expected, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) ownershipReportShouldFlag(file string) error {
+	if !strings.Contains(tc.lastOutput, "Ownership mismatch: "+file) {
+		return fmt.Errorf("expected ownership mismatch for %s, got: %s", file, tc.lastOutput)
+	}
+	return nil
+}
+
+// Filter test step implementations
+
+func (tc *TestContext) runSyncToolsFilterTest(path string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--filter-test", path)
+}
+
+// runSyncToolsCheckFilter checks each space-separated path in paths
+func (tc *TestContext) runSyncToolsCheckFilter(paths string) error {
+	return tc.runSyncToolsCheckFilterWithFlags(paths, "")
+}
+
+func (tc *TestContext) runSyncToolsCheckFilterWithFlags(paths, flags string) error {
+	args := append([]string{"check-filter", "--source", tc.sourceDir}, strings.Fields(flags)...)
+	return tc.runCommand(append(args, strings.Fields(paths)...)...)
+}
+
+func (tc *TestContext) runSyncToolsFilterTestIgnoringCase(path string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--filter-test", path, "--ignore-case")
+}
+
+func (tc *TestContext) runSyncToolsFilterTestWithMaxDepth(path string, depth int) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--filter-test", path, "--max-depth", fmt.Sprint(depth))
+}
+
+// Retry step implementations
+
+func (tc *TestContext) rsyncFailsToTransferOnce(file string) error {
+	realRsync, err := exec.LookPath("rsync")
+	if err != nil {
+		return fmt.Errorf("rsync is required for this scenario: %v", err)
+	}
+
+	tc.fakeBinDir, err = os.MkdirTemp("", "sync_test_bin_")
+	if err != nil {
+		return err
+	}
+
+	// The wrapper runs the real rsync but reports the file as failed on its first invocation
+	marker := filepath.Join(tc.fakeBinDir, "failed-once")
+	script := fmt.Sprintf(`#!/bin/sh
+if [ ! -f %q ]; then
+  touch %q
+  %q "$@"
+  echo 'rsync: [sender] send_files failed to open "%s": Permission denied (13)' >&2
+  exit 23
+fi
+exec %q "$@"
+`, marker, marker, realRsync, filepath.Join(tc.sourceDir, file), realRsync)
+	return os.WriteFile(filepath.Join(tc.fakeBinDir, "rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithFileRetries(retries int) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--retry-files", fmt.Sprint(retries))
+}
+
+// Git fallback step implementations
+
+func (tc *TestContext) gitIsNotAvailable() error {
+	var err error
+	tc.fakeBinDir, err = os.MkdirTemp("", "sync_test_bin_")
+	if err != nil {
+		return err
+	}
+
+	// Keep the tools sync-tools still needs, leaving git off the PATH
+	for _, tool := range []string{"rsync", "patch"} {
+		toolPath, err := exec.LookPath(tool)
+		if err != nil {
+			return fmt.Errorf("%s is required for this scenario: %v", tool, err)
+		}
+		if err := os.Symlink(toolPath, filepath.Join(tc.fakeBinDir, tool)); err != nil {
+			return err
+		}
+	}
+	tc.isolatedPath = true
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsWithPatchGenerationAndApply(patchFile string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir,
+		"--patch", filepath.Join(tc.tmpDir, patchFile), "--apply-patch", "--yes")
+}
+
+func (tc *TestContext) destinationFileShouldContain(file, content string) error {
+	data, err := os.ReadFile(filepath.Join(tc.destDir, file))
+	if err != nil {
+		return fmt.Errorf("expected %s in destination: %w", file, err)
+	}
+	if !strings.Contains(string(data), content) {
+		return fmt.Errorf("expected %s to contain %q, got: %s", file, content, data)
+	}
+	return nil
+}
+
+// List step implementations
+
+// tempDirHasOldFile writes a temp directory file and backdates it, e.g. a leftover filter file
+func (tc *TestContext) tempDirHasOldFile(file string, hours int) error {
+	if err := tc.tempDirHasFile(file); err != nil {
+		return err
+	}
+	old := time.Now().Add(-time.Duration(hours) * time.Hour)
+	return os.Chtimes(filepath.Join(tc.tmpDir, file), old, old)
+}
+
+func (tc *TestContext) tempDirHasFile(file string) error {
+	fullPath := filepath.Join(tc.tmpDir, file)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte("content for "+file), 0644)
+}
+
+func (tc *TestContext) sourceHasFile(file string) error {
+	fullPath := filepath.Join(tc.sourceDir, file)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte("content for "+file), 0644)
+}
+
+// sourceHasFileContaining writes a source file with the given content, e.g. a nested .syncignore
+func (tc *TestContext) sourceHasFileContaining(file string, content *godog.DocString) error {
+	fullPath := filepath.Join(tc.sourceDir, file)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte(content.Content+"\n"), 0644)
+}
+
+func (tc *TestContext) destinationHasFile(file string) error {
+	fullPath := filepath.Join(tc.destDir, file)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(fullPath, []byte("content for "+file), 0644)
+}
+
+func (tc *TestContext) sourceHasEmptyDirectory(dir string) error {
+	return os.MkdirAll(filepath.Join(tc.sourceDir, dir), 0755)
+}
+
+// destinationHasCopyOfSourceFile copies a source file to the dest with its modification time,
+// as a finished sync leaves it
+func (tc *TestContext) destinationHasCopyOfSourceFile(file string) error {
+	srcPath := filepath.Join(tc.sourceDir, file)
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return err
+	}
+	destPath := filepath.Join(tc.destDir, file)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(destPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(destPath, info.ModTime(), info.ModTime())
+}
+
+// sourceHasCopyOfFile duplicates a source file under another name, keeping its mtime
+func (tc *TestContext) sourceHasCopyOfFile(file, copyName string) error {
+	srcPath := filepath.Join(tc.sourceDir, file)
+	data, err := os.ReadFile(srcPath)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return err
+	}
+	copyPath := filepath.Join(tc.sourceDir, copyName)
+	if err := os.MkdirAll(filepath.Dir(copyPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(copyPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Chtimes(copyPath, info.ModTime(), info.ModTime())
+}
+
+// destinationFilesLinked reports whether two dest files are hard links to the same file
+func (tc *TestContext) destinationFilesLinked(a, b string) (bool, error) {
+	infoA, err := os.Stat(filepath.Join(tc.destDir, a))
+	if err != nil {
+		return false, err
+	}
+	infoB, err := os.Stat(filepath.Join(tc.destDir, b))
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(infoA, infoB), nil
+}
+
+func (tc *TestContext) destinationFilesShouldBeLinked(a, b string) error {
+	linked, err := tc.destinationFilesLinked(a, b)
+	if err != nil {
+		return err
+	}
+	if !linked {
+		return fmt.Errorf("expected %s and %s to be hard links to the same file. Output: %s", a, b, tc.lastOutput)
+	}
+	return nil
+}
+
+func (tc *TestContext) destinationFilesShouldNotBeLinked(a, b string) error {
+	linked, err := tc.destinationFilesLinked(a, b)
+	if err != nil {
+		return err
+	}
+	if linked {
+		return fmt.Errorf("expected %s and %s to be separate files", a, b)
+	}
+	return nil
+}
+
+// destinationHasOlderCopyOfSourceFile copies a source file to the dest with an mtime an hour
+// earlier, so only rsync's time check tells the two apart
+func (tc *TestContext) destinationHasOlderCopyOfSourceFile(file string) error {
+	if err := tc.destinationHasCopyOfSourceFile(file); err != nil {
+		return err
+	}
+	info, err := os.Stat(filepath.Join(tc.sourceDir, file))
+	if err != nil {
+		return err
+	}
+	older := info.ModTime().Add(-time.Hour)
+	return os.Chtimes(filepath.Join(tc.destDir, file), older, older)
+}
+
+func (tc *TestContext) runSyncToolsCompare() error {
+	return tc.runCommand("compare", tc.sourceDir, tc.destDir)
+}
+
+func (tc *TestContext) runSyncToolsCompareWithFlags(flags string) error {
+	return tc.runCommand(append([]string{"compare", tc.sourceDir, tc.destDir}, strings.Fields(flags)...)...)
+}
+
+func (tc *TestContext) runSyncToolsList() error {
+	return tc.runCommand("list", "--source", tc.sourceDir)
+}
+
+func (tc *TestContext) runSyncToolsListWithFormat(format string) error {
+	return tc.runCommand("list", "--source", tc.sourceDir, "--format", format)
+}
+
+func (tc *TestContext) runSyncToolsListWithFormatAndFlags(format, flags string) error {
+	args := append([]string{"list", "--source", tc.sourceDir, "--format", format}, strings.Fields(flags)...)
+	return tc.runCommand(args...)
+}
+
+// runSyncToolsListWithFilterRules passes each line of the doc string as one --filter-rule,
+// since rules hold spaces that a flags string would split
+func (tc *TestContext) runSyncToolsListWithFilterRules(rules *godog.DocString) error {
+	args := []string{"list", "--source", tc.sourceDir, "--format", "flat"}
+	for _, rule := range strings.Split(strings.TrimSpace(rules.Content), "\n") {
+		args = append(args, "--filter-rule", strings.TrimSpace(rule))
+	}
+	return tc.runCommand(args...)
+}
+
+func (tc *TestContext) jsonListingShouldInclude(path string, size int64) error {
+	var entries []struct {
+		Path string `json:"path"`
+		Size int64  `json:"size"`
+	}
+	if err := json.Unmarshal([]byte(tc.lastOutput), &entries); err != nil {
+		return fmt.Errorf("listing is not valid JSON: %v\n%s", err, tc.lastOutput)
+	}
+	for _, entry := range entries {
+		if entry.Path == path {
+			if entry.Size != size {
+				return fmt.Errorf("expected %s to be %d bytes, got %d", path, size, entry.Size)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("expected %s in the listing, got: %s", path, tc.lastOutput)
+}
+
+// Undo step implementations
+
+func (tc *TestContext) createConflictBackup(file string, timestamp int64, content string) error {
+	backupPath := filepath.Join(tc.destDir, fmt.Sprintf("%s.conflict-%d", file, timestamp))
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(backupPath, []byte(content), 0644)
+}
+
+// createNamedConflictBackup writes a conflict copy under a --conflict-suffix name
+func (tc *TestContext) createNamedConflictBackup(name, content string) error {
+	backupPath := filepath.Join(tc.destDir, name)
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(backupPath, []byte(content), 0644)
+}
+
+func (tc *TestContext) runSyncToolsUndo() error {
+	return tc.runCommand("undo", "--dest", tc.destDir, "--yes")
+}
+
+func (tc *TestContext) runSyncToolsUndoKeepingRedo() error {
+	return tc.runCommand("undo", "--dest", tc.destDir, "--yes", "--keep-redo")
+}
+
+func (tc *TestContext) runSyncToolsUndoSince(timestamp int64) error {
+	return tc.runCommand("undo", "--dest", tc.destDir, "--yes", "--since", fmt.Sprint(timestamp))
+}
+
+func (tc *TestContext) runSyncToolsUndoWithFlags(flags string) error {
+	return tc.runCommand(append([]string{"undo", "--dest", tc.destDir, "--yes"}, strings.Fields(flags)...)...)
+}
+
+func (tc *TestContext) destinationShouldHaveRedoCopy(file string) error {
+	matches, err := filepath.Glob(filepath.Join(tc.destDir, file+".redo-*"))
+	if err != nil {
+		return err
+	}
+	if len(matches) != 1 {
+		return fmt.Errorf("expected one redo copy of %s, found %d", file, len(matches))
+	}
+	return nil
+}
+
+// destinationHasNewerFile writes a dest version of a source file, modified an hour after it,
+// as if it had been edited in the dest since the last sync
+func (tc *TestContext) destinationHasNewerFile(file, content string) error {
+	info, err := os.Stat(filepath.Join(tc.sourceDir, file))
+	if err != nil {
+		return err
+	}
+	destPath := filepath.Join(tc.destDir, file)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(destPath, []byte(content), 0644); err != nil {
+		return err
+	}
+	newer := info.ModTime().Add(time.Hour)
+	return os.Chtimes(destPath, newer, newer)
+}
+
+// runSyncToolsAnswering runs a sync with answers, one per line, on stdin
+func (tc *TestContext) runSyncToolsAnswering(flags, answers string) error {
+	args := append([]string{"sync", "--source", tc.sourceDir, "--dest", tc.destDir}, strings.Fields(flags)...)
+	cmd := tc.command("", args...)
+	cmd.Stdin = strings.NewReader(strings.ReplaceAll(answers, ",", "\n") + "\n")
+	output, err := cmd.CombinedOutput()
+	tc.lastOutput = string(output)
+	tc.recordExit(err)
+	return nil
+}
+
+// conflictCopies lists the default-named conflict copies of file in the dest
+func (tc *TestContext) conflictCopies(file string) ([]string, error) {
+	return filepath.Glob(filepath.Join(tc.destDir, file+".conflict-*"))
+}
+
+func (tc *TestContext) destinationShouldHaveConflictCopy(file, content string) error {
+	matches, err := tc.conflictCopies(file)
+	if err != nil {
+		return err
+	}
+	if len(matches) != 1 {
+		return fmt.Errorf("expected one conflict copy of %s, found %d", file, len(matches))
+	}
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), content) {
+		return fmt.Errorf("expected the conflict copy of %s to contain %q, got: %s", file, content, data)
+	}
+	return nil
+}
+
+func (tc *TestContext) destinationShouldHaveNoConflictCopy(file string) error {
+	matches, err := tc.conflictCopies(file)
+	if err != nil {
+		return err
+	}
+	if len(matches) != 0 {
+		return fmt.Errorf("expected no conflict copy of %s, found %v", file, matches)
+	}
+	return nil
+}
+
+func (tc *TestContext) sourceFileShouldContain(file, content string) error {
+	data, err := os.ReadFile(filepath.Join(tc.sourceDir, file))
+	if err != nil {
+		return fmt.Errorf("expected %s in source: %w", file, err)
+	}
+	if !strings.Contains(string(data), content) {
+		return fmt.Errorf("expected %s to contain %q, got: %s", file, content, data)
+	}
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsWithRsyncBinary(binary string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--rsync-binary", binary)
+}
+
+func (tc *TestContext) runSyncToolsWithRsyncBinaryFromPath() error {
+	rsyncPath, err := exec.LookPath("rsync")
+	if err != nil {
+		return fmt.Errorf("rsync not found on PATH: %w", err)
+	}
+	return tc.runSyncToolsWithRsyncBinary(rsyncPath)
+}
+
+// rsync daemon step implementations
+
+// createRecordingRsync writes an rsync stand-in that records its arguments, one per line,
+// and the rules in the filter files it is given, so scenarios with unreachable remote
+// targets can check what would have been run
+func (tc *TestContext) createRecordingRsync() error {
+	script := fmt.Sprintf("#!/bin/sh\nprev=\nfor arg in \"$@\"; do\n"+
+		"  echo \"$arg\"\n"+
+		"  [ \"$prev\" = --filter ] && cat \"${arg#. }\" >> %s\n"+
+		"  prev=$arg\n"+
+		"done > %s\n",
+		filepath.Join(tc.tmpDir, "rsync-filters"), filepath.Join(tc.tmpDir, "rsync-args"))
+	return os.WriteFile(filepath.Join(tc.tmpDir, "recording-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithRecordingRsync(dest, passwordFile string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", dest,
+		"--rsync-binary", filepath.Join(tc.tmpDir, "recording-rsync"), "--password-file", passwordFile)
+}
+
+func (tc *TestContext) runSyncToolsWithRecordingRsyncExtraArgs(extraArgs string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir,
+		"--rsync-binary", filepath.Join(tc.tmpDir, "recording-rsync"), "--rsync-extra-args", extraArgs, "-v")
+}
+
+func (tc *TestContext) runSyncToolsWithRecordingRsyncAndFlags(flags string) error {
+	return tc.runSyncToolsWithOneWaySyncAndFlags("--rsync-binary " + filepath.Join(tc.tmpDir, "recording-rsync") + " " + flags)
+}
+
+// createUnreadableFileRsync writes an rsync stand-in that reports a permission error for
+// file and exits with rsync's partial-transfer status, as a real rsync does for root-owned files
+func (tc *TestContext) createUnreadableFileRsync(file string) error {
+	script := fmt.Sprintf("#!/bin/sh\n"+
+		"echo 'rsync: [sender] send_files failed to open \"%s\": Permission denied (13)' >&2\n"+
+		"echo 'rsync error: some files/attrs were not transferred (see previous errors) (code 23)' >&2\n"+
+		"exit 23\n", filepath.Join(tc.sourceDir, file))
+	return os.WriteFile(filepath.Join(tc.tmpDir, "unreadable-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithUnreadableFileRsync(flags string) error {
+	return tc.runSyncToolsWithOneWaySyncAndFlags("--rsync-binary " + filepath.Join(tc.tmpDir, "unreadable-rsync") + " " + flags)
+}
+
+// createProgressRsync writes an rsync stand-in that reports one file and redraws a
+// --info=progress2 status line over it, as a real rsync does during a large transfer
+func (tc *TestContext) createProgressRsync() error {
+	script := "#!/bin/sh\n" +
+		"echo '>f+++++++++ 2,000 big.bin'\n" +
+		"printf '\\r          1.00K  50%%    1.00MB/s    0:00:01'\n" +
+		"printf '\\r          2.00K 100%%    1.00MB/s    0:00:00 (xfr#1, to-chk=0/1)\\n'\n"
+	return os.WriteFile(filepath.Join(tc.tmpDir, "progress-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithProgressRsync(flags string) error {
+	return tc.runSyncToolsWithOneWaySyncAndFlags("--rsync-binary " + filepath.Join(tc.tmpDir, "progress-rsync") + " " + flags)
+}
+
+// createInterruptedRsync writes an rsync stand-in whose first run leaves half of file in
+// the partial dir and exits as if the connection dropped. Later runs finish the file only if
+// asked to resume from that partial dir, so a restart from scratch fails the scenario.
+func (tc *TestContext) createInterruptedRsync(file string) error {
+	src := filepath.Join(tc.sourceDir, file)
+	partial := filepath.Join(tc.destDir, ".rsync-partial", file)
+	script := fmt.Sprintf("#!/bin/sh\n"+
+		"attempts=%[1]s\n"+
+		"n=$(($(cat \"$attempts\" 2>/dev/null || echo 0) + 1)); echo $n > \"$attempts\"\n"+
+		"if [ $n -eq 1 ]; then\n"+
+		"  mkdir -p \"$(dirname %[3]s)\"\n"+
+		"  head -c 5 %[2]s > %[3]s\n"+
+		"  echo 'rsync: connection unexpectedly closed (5 bytes received so far) [receiver]' >&2\n"+
+		"  echo 'rsync error: error in rsync protocol data stream (code 12)' >&2\n"+
+		"  exit 12\n"+
+		"fi\n"+
+		"case \"$*\" in *--partial-dir=.rsync-partial*) ;; *) echo 'restarted without --partial-dir' >&2; exit 1;; esac\n"+
+		"[ -f %[3]s ] || { echo 'partial file is gone' >&2; exit 1; }\n"+
+		"cp %[2]s %[4]s && rm -rf \"$(dirname %[3]s)\"\n"+
+		"echo '>f+++++++++ 0 %[5]s'\n",
+		filepath.Join(tc.tmpDir, "rsync-attempts"), src, partial, filepath.Join(tc.destDir, file), file)
+	return os.WriteFile(filepath.Join(tc.tmpDir, "interrupted-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithInterruptedRsync(flags string) error {
+	return tc.runSyncToolsWithOneWaySyncAndFlags("--rsync-binary " + filepath.Join(tc.tmpDir, "interrupted-rsync") + " " + flags)
+}
+
+func (tc *TestContext) runSyncToolsWithRecordingRsyncUsingConfig() error {
+	return tc.runCommand("sync", "--config", tc.configPath, "--source", tc.sourceDir, "--dest", tc.destDir,
+		"--rsync-binary", filepath.Join(tc.tmpDir, "recording-rsync"))
+}
+
+// recordedFilterRules returns the filter rules the recording rsync was given
+func (tc *TestContext) recordedFilterRules() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(tc.tmpDir, "rsync-filters"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return strings.Split(string(data), "\n"), err
+}
+
+func (tc *TestContext) rsyncShouldHaveBeenGivenFilterRule(rule string) error {
+	rules, err := tc.recordedFilterRules()
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r == rule {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected filter rule %q, got:\n%s\noutput: %s", rule, strings.Join(rules, "\n"), tc.lastOutput)
+}
+
+func (tc *TestContext) rsyncShouldNotHaveBeenGivenFilterRule(rule string) error {
+	rules, err := tc.recordedFilterRules()
+	if err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r == rule {
+			return fmt.Errorf("expected no filter rule %q, got:\n%s", rule, strings.Join(rules, "\n"))
+		}
+	}
+	return nil
+}
+
+func (tc *TestContext) rsyncShouldHaveBeenCalledWith(expected string) error {
+	data, err := os.ReadFile(filepath.Join(tc.tmpDir, "rsync-args"))
+	if err != nil {
+		return fmt.Errorf("rsync was not run: %w; output: %s", err, tc.lastOutput)
+	}
+	for _, arg := range strings.Split(string(data), "\n") {
+		if arg == expected {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected rsync argument %q, got:\n%s", expected, data)
+}
+
+func (tc *TestContext) rsyncShouldNotHaveBeenCalledWith(unexpected string) error {
+	data, err := os.ReadFile(filepath.Join(tc.tmpDir, "rsync-args"))
+	if err != nil {
+		return fmt.Errorf("rsync was not run: %w; output: %s", err, tc.lastOutput)
+	}
+	for _, arg := range strings.Split(string(data), "\n") {
+		if arg == unexpected {
+			return fmt.Errorf("did not expect rsync argument %q, got:\n%s", unexpected, data)
+		}
+	}
+	return nil
+}
+
+// Doctor step implementations
+
+func (tc *TestContext) runSyncToolsDoctor() error {
+	return tc.runCommand("doctor")
+}
+
+func (tc *TestContext) runSyncToolsDoctorWithRsyncBinary(binary string) error {
+	return tc.runCommand("doctor", "--rsync-binary", binary)
+}
+
+// createRsyncReportingVersion writes an rsync stand-in whose --version output names the given version
+func (tc *TestContext) createRsyncReportingVersion(version string) error {
+	script := fmt.Sprintf("#!/bin/sh\necho 'rsync  version %s  protocol version 29'\n", version)
+	return os.WriteFile(filepath.Join(tc.tmpDir, "versioned-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsDoctorWithVersionedRsync() error {
+	return tc.runSyncToolsDoctorWithRsyncBinary(filepath.Join(tc.tmpDir, "versioned-rsync"))
+}
+
+func (tc *TestContext) runSyncToolsWithVersionedRsyncAndFlags(flags string) error {
+	return tc.runSyncToolsWithOneWaySyncAndFlags("--rsync-binary " + filepath.Join(tc.tmpDir, "versioned-rsync") + " " + flags)
+}
+
+// Assertion step implementations
+
+func (tc *TestContext) runSyncToolsAssert(flags string) error {
+	args := append([]string{"assert", "--source", tc.sourceDir, "--dest", tc.destDir}, strings.Fields(flags)...)
+	return tc.runCommand(args...)
+}
+
+// Drift check step implementations
+
+func (tc *TestContext) runSyncToolsFailOnChanges() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--dry-run", "--fail-on-changes")
+}
+
+func (tc *TestContext) runSyncToolsFailOnChangesWithoutDryRun() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--fail-on-changes")
+}
+
+// Timing step implementations
+
+func (tc *TestContext) runSyncToolsWithStats() error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--stats")
+}
+
+// Hook step implementations
+
+func (tc *TestContext) runSyncToolsWithOnSuccessHook(hook string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--on-success", hook)
+}
+
+func (tc *TestContext) runSyncToolsWithOnFailureHook(binary, hook string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir,
+		"--rsync-binary", binary, "--on-failure", hook)
+}
+
+// Notification step implementations
+
+func (tc *TestContext) startWebhookReceiver() error {
+	tc.webhookBodies = nil
+	tc.webhook = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		tc.webhookMu.Lock()
+		tc.webhookBodies = append(tc.webhookBodies, body)
+		tc.webhookMu.Unlock()
+	}))
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsWithWebhookNotify() error {
+	return tc.runSyncToolsWithNotify(tc.webhook.URL)
+}
+
+func (tc *TestContext) runSyncToolsWithNotify(target string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir, "--notify", target)
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithWebhookNotify() error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--notify", tc.webhook.URL)
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithWebhookNotifyAndTimeout(timeout string) error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--notify", tc.webhook.URL, "--timeout", timeout)
+}
+
+// webhookNotification decodes the only notification the webhook received
+func (tc *TestContext) webhookNotification() (map[string]interface{}, error) {
+	tc.webhookMu.Lock()
+	defer tc.webhookMu.Unlock()
+	if len(tc.webhookBodies) != 1 {
+		return nil, fmt.Errorf("expected 1 webhook notification, got %d; output: %s", len(tc.webhookBodies), tc.lastOutput)
+	}
+	var notification map[string]interface{}
+	if err := json.Unmarshal(tc.webhookBodies[0], &notification); err != nil {
+		return nil, fmt.Errorf("webhook body is not JSON: %w", err)
+	}
+	return notification, nil
+}
+
+func (tc *TestContext) webhookShouldReceiveNotification(outcome string, runs int) error {
+	notification, err := tc.webhookNotification()
+	if err != nil {
+		return err
+	}
+	if success, _ := notification["success"].(bool); success != (outcome == "successful") {
+		return fmt.Errorf("expected a %s notification, got: %v", outcome, notification)
+	}
+	if got, _ := notification["runs"].([]interface{}); len(got) != runs {
+		return fmt.Errorf("expected %d runs in the notification, got %d", runs, len(got))
+	}
+	return nil
+}
+
+func (tc *TestContext) webhookNotificationTextShouldContain(expected string) error {
+	notification, err := tc.webhookNotification()
+	if err != nil {
+		return err
+	}
+	if text, _ := notification["text"].(string); !strings.Contains(text, expected) {
+		return fmt.Errorf("expected notification text to contain %q, got: %q", expected, text)
+	}
+	return nil
+}
+
+// Timeout step implementations
+
+// createHangingRsync writes an rsync stand-in that reports one file and then runs until
+// it is signalled, recording the SIGTERM so scenarios can check it was stopped
+func (tc *TestContext) createHangingRsync() error {
+	script := fmt.Sprintf(`#!/bin/sh
+trap 'touch %s; exit 20' TERM
+echo ">f+++++++++ 33 file1.txt"
+while true; do sleep 0.1; done
+`, filepath.Join(tc.tmpDir, "rsync-stopped"))
+	return os.WriteFile(filepath.Join(tc.tmpDir, "hanging-rsync"), []byte(script), 0755)
+}
+
+func (tc *TestContext) runSyncToolsWithHangingRsync(timeout string) error {
+	return tc.runCommand("sync", "--source", tc.sourceDir, "--dest", tc.destDir,
+		"--rsync-binary", filepath.Join(tc.tmpDir, "hanging-rsync"), "--timeout", timeout)
+}
+
+func (tc *TestContext) createSyncFileWithHangingRsync() error {
+	rsyncPath := filepath.Join(tc.tmpDir, "hanging-rsync")
+	content := fmt.Sprintf("SYNC %s %s\nRSYNCBIN %s\nSYNC %s %s\nRSYNCBIN %s\n",
+		tc.sourceDir, filepath.Join(tc.destDir, "first"), rsyncPath,
+		tc.sourceDir, filepath.Join(tc.destDir, "second"), rsyncPath)
+	return os.WriteFile(tc.syncFilePath, []byte(content), 0644)
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithTimeout(timeout string) error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--timeout", timeout)
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithTotalTimeout(timeout string) error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--total-timeout", timeout)
+}
+
+func (tc *TestContext) hangingRsyncShouldHaveBeenStopped() error {
+	if _, err := os.Stat(filepath.Join(tc.tmpDir, "rsync-stopped")); err != nil {
+		return fmt.Errorf("expected the hanging rsync to receive SIGTERM: %w", err)
+	}
+	return nil
+}
+
+func (tc *TestContext) failedFileShouldBeRetriedSuccessfully() error {
+	if !strings.Contains(tc.lastOutput, "Retry attempt 1 transferred all remaining files") {
+		return fmt.Errorf("expected the retry pass to succeed, got: %s", tc.lastOutput)
+	}
+	return nil
+}
+
+// SyncFile step implementations
+
+// createSyncFileWithGuardedSync writes a SyncFile with one SYNC that always runs and one
+// inside the condition's IF block
+func (tc *TestContext) createSyncFileWithGuardedSync(condition string) error {
+	content := fmt.Sprintf(`SYNC %s %s
+%s
+SYNC %s %s
+ENDIF
+`, tc.sourceDir, tc.destDir, condition, tc.sourceDir, tc.destDir+"_prod")
+	return os.WriteFile(tc.syncFilePath, []byte(content), 0644)
+}
+
+// setEnvironmentVariable sets name for the scenario's sync-tools runs
+func (tc *TestContext) setEnvironmentVariable(name, value string) error {
+	tc.env = append(tc.env, name+"="+value)
+	return nil
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithList() error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--list")
+}
+
+// createNamedSyncFile writes a SyncFile into the scenario's temp directory, replacing
+// {source} and {dest} with the scenario's directories
+func (tc *TestContext) createNamedSyncFile(name string, content *godog.DocString) error {
+	text := strings.NewReplacer("{source}", tc.sourceDir, "{dest}", tc.destDir, "{tmp}", tc.tmpDir).Replace(content.Content)
+	path := filepath.Join(tc.tmpDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(text+"\n"), 0644)
+}
+
+func (tc *TestContext) runSyncToolsNamedSyncfileWithList(name string) error {
+	return tc.runCommand("syncfile", filepath.Join(tc.tmpDir, name), "--list")
+}
+
+func (tc *TestContext) runSyncToolsNamedSyncfile(name string) error {
+	return tc.runCommand("syncfile", filepath.Join(tc.tmpDir, name))
+}
+
+func (tc *TestContext) runSyncToolsNamedSyncfileWithFlags(name, flags string) error {
+	return tc.runCommand(append([]string{"syncfile", filepath.Join(tc.tmpDir, name)}, strings.Fields(flags)...)...)
+}
+
+// lockedBuffer collects a background command's output while steps poll it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor polls cond until it holds or timeout passes
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return cond()
+}
+
+// watchSyncfileWhileAddingFile starts syncfile --watch, adds a source file once watching has
+// begun, waits for the watched operation to sync it, then interrupts the watch
+func (tc *TestContext) watchSyncfileWhileAddingFile(name, file string) error {
+	var output lockedBuffer
+	cmd := tc.command("", "syncfile", filepath.Join(tc.tmpDir, name), "--watch", "--debounce", "200ms")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if waitFor(10*time.Second, func() bool { return strings.Contains(output.String(), "Watching for changes") }) {
+		if err := tc.sourceHasFile(file); err != nil {
+			return err
+		}
+		waitFor(10*time.Second, func() bool { return strings.Contains(output.String(), "Sync operation 1:") })
+	}
+
+	cmd.Process.Signal(os.Interrupt)
+	tc.recordExit(cmd.Wait())
+	tc.lastOutput = output.String()
+	return nil
+}
+
+// watchSyncfileForWhileAddingFile adds file to the source once watching starts, then stops
+// watching after the given number of seconds whether or not a sync ran
+func (tc *TestContext) watchSyncfileForWhileAddingFile(name string, seconds int, file string) error {
+	var output lockedBuffer
+	cmd := tc.command("", "syncfile", filepath.Join(tc.tmpDir, name), "--watch", "--debounce", "200ms")
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	if waitFor(10*time.Second, func() bool { return strings.Contains(output.String(), "Watching for changes") }) {
+		if err := tc.sourceHasFile(file); err != nil {
+			return err
+		}
+		time.Sleep(time.Duration(seconds) * time.Second)
+	}
+
+	cmd.Process.Signal(os.Interrupt)
+	tc.recordExit(cmd.Wait())
+	tc.lastOutput = output.String()
+	return nil
+}
+
+func (tc *TestContext) createSyncFileWithTwoDestinations() error {
+	content := fmt.Sprintf("SYNC %s %s\nSYNC %s %s\n",
+		tc.sourceDir, filepath.Join(tc.destDir, "first"), tc.sourceDir, filepath.Join(tc.destDir, "second"))
+	return os.WriteFile(tc.syncFilePath, []byte(content), 0644)
+}
+
+func (tc *TestContext) runSyncToolsSyncfileWithReport() error {
+	return tc.runCommand("syncfile", tc.syncFilePath, "--report", filepath.Join(tc.destDir, "report.md"))
+}
+
+func (tc *TestContext) syncFileReportShouldContain(expected string) error {
+	data, err := os.ReadFile(filepath.Join(tc.destDir, "report.md"))
+	if err != nil {
+		return fmt.Errorf("failed to read SyncFile report: %w", err)
+	}
+	if !strings.Contains(string(data), expected) {
+		return fmt.Errorf("expected report to contain %q, got: %s", expected, data)
+	}
+	return nil
+}
+
+func (tc *TestContext) syncFileShouldReportOperations(count int) error {
+	expected := fmt.Sprintf("Found %d sync operations", count)
+	if !strings.Contains(tc.lastOutput, expected) {
+		return fmt.Errorf("expected output to contain %q, got: %s", expected, tc.lastOutput)
+	}
+	return nil
+}